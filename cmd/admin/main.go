@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/rollup"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+var errMismatch = errors.New("rollup differs from subscriptions")
+
+var CommandMapper = map[string]func(context.Context, *slog.Logger, *config.Config, postgres.RollupAPI) error{
+	"rollup-rebuild": rebuild,
+	"rollup-refresh": refresh,
+	"rollup-verify":  verify,
+}
+
+func main() {
+	var configPath string
+	flag.StringVar(&configPath, "c", "config.yaml", "config path")
+	flag.Parse()
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config parse fail: %s\n", err)
+		os.Exit(1)
+	}
+
+	logger, err := logger.Setup(&cfg.Logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "setup logger fail: %s\n", err)
+		os.Exit(1)
+	}
+
+	if flag.NArg() < 1 {
+		logger.Error("empty command")
+		os.Exit(1)
+	}
+
+	exec, ok := CommandMapper[flag.Arg(0)]
+	if !ok {
+		logger.Error("invalid command", "command", flag.Arg(0))
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	db, err := postgres.Open(ctx, logger, &cfg.Postgres)
+	if err != nil {
+		logger.Error("connect to database fail", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := exec(ctx, logger, cfg, postgres.NewRollupAPI(db)); err != nil {
+		logger.Error(flag.Arg(0), "error", err)
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+// rebuild recomputes the whole rollup, e.g. after fixing data by hand with
+// triggers disabled.
+func rebuild(ctx context.Context, logger *slog.Logger, cfg *config.Config, store postgres.RollupAPI) error {
+	through := rollup.Horizon(time.Now(), cfg.Rollup.HorizonMonths)
+
+	start := time.Now()
+	if err := store.Rebuild(ctx, through); err != nil {
+		return err
+	}
+
+	logger.Info("rollup rebuilt", "through", through.Format("01-2006"), "took", time.Since(start))
+	return nil
+}
+
+func refresh(ctx context.Context, logger *slog.Logger, cfg *config.Config, store postgres.RollupAPI) error {
+	through := rollup.Horizon(time.Now(), cfg.Rollup.HorizonMonths)
+
+	if err := store.Refresh(ctx, through); err != nil {
+		return err
+	}
+
+	logger.Info("rollup refreshed", "through", through.Format("01-2006"))
+	return nil
+}
+
+func verify(ctx context.Context, logger *slog.Logger, cfg *config.Config, store postgres.RollupAPI) error {
+	through, ok, err := store.Through(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		logger.Info("rollup not built yet")
+		return nil
+	}
+
+	diffs, err := store.Verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, diff := range diffs {
+		logger.Warn(
+			"rollup mismatch",
+			"month", diff.Month,
+			"user_id", diff.UserID,
+			"service_name", diff.ServiceName,
+			"category", diff.Category,
+			"want", diff.Want,
+			"got", diff.Got,
+		)
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("%w: %d rows", errMismatch, len(diffs))
+	}
+
+	logger.Info("rollup matches subscriptions", "through", through.Format("01-2006"))
+	return nil
+}
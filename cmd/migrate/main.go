@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -9,115 +11,293 @@ import (
 
 	"github.com/P3rCh1/subs-aggregator/internal/config"
 	"github.com/P3rCh1/subs-aggregator/internal/logger"
-	"github.com/golang-migrate/migrate"
-	_ "github.com/golang-migrate/migrate/database/postgres"
-	_ "github.com/golang-migrate/migrate/source/file"
+	"github.com/P3rCh1/subs-aggregator/internal/migrator"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/migrations"
 )
 
-var CommandMapper = map[string]func(*slog.Logger, *migrate.Migrate){
+const (
+	exitOK = iota
+	exitFail
+	exitUsage
+)
+
+var errUsage = errors.New("invalid usage")
+
+type options struct {
+	dryRun bool
+	args   []string
+}
+
+var CommandMapper = map[string]func(*slog.Logger, *migrator.Migrator, *options) error{
 	"up":      up,
 	"down":    down,
+	"goto":    goTo,
+	"status":  status,
 	"version": version,
 	"force":   force,
 }
 
+const usage = `usage: migrate [-c config.yaml] [-dry-run] [-dir migrations] COMMAND [ARG]
+
+commands:
+  up           apply all pending migrations
+  down         roll back the last migration
+  goto N       migrate up or down to version N (0 rolls back everything)
+  status       list applied and pending migrations
+  version      print the applied version
+  force N      set the version without running migrations
+  create NAME  add empty up and down files to -dir
+
+-dry-run prints the SQL of up, down and goto instead of running it.
+Flags may come before or after the command and its argument.
+`
+
 func main() {
-	var configPath string
+	os.Exit(run())
+}
+
+func run() int {
+	var (
+		configPath string
+		dir        string
+		opts       options
+	)
+
 	flag.StringVar(&configPath, "c", "config.yaml", "config path")
-	flag.Parse()
+	flag.StringVar(&dir, "dir", "migrations", "directory for create")
+	flag.BoolVar(&opts.dryRun, "dry-run", false, "print SQL instead of running it")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+
+	args, err := parseArgs(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(args) < 1 {
+		flag.Usage()
+		return exitUsage
+	}
+
+	command := args[0]
+	opts.args = args[1:]
+
+	if command == "create" {
+		return create(dir, opts.args)
+	}
+
+	exec, ok := CommandMapper[command]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		flag.Usage()
+		return exitUsage
+	}
 
 	cfg, err := config.Load(configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "config parse fail: %s\n", err)
-		os.Exit(1)
+		return exitFail
 	}
 
 	logger, err := logger.Setup(&cfg.Logger)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "setup logger fail: %s\n", err)
-		os.Exit(1)
+		return exitFail
 	}
 
-	if len(os.Args) < 2 {
-		logger.Error("empty command")
-		os.Exit(1)
+	db, err := postgres.Open(context.Background(), logger, &cfg.Postgres)
+	if err != nil {
+		logger.Error("connect to database fail", "error", err)
+		return exitFail
+	}
+
+	m, err := migrator.New(db.DB, migrations.FS)
+	if err != nil {
+		db.Close()
+		logger.Error("create migrator fail", "error", err)
+		return exitFail
+	}
+	defer m.Close()
+
+	if err := exec(logger, m, &opts); err != nil {
+		logger.Error(command+" fail", "error", err)
+
+		if errors.Is(err, errUsage) || errors.Is(err, migrator.ErrVersion) {
+			return exitUsage
+		}
+		return exitFail
 	}
 
-	if exec, ok := CommandMapper[os.Args[1]]; !ok {
-		logger.Error("invalid command")
-		os.Exit(1)
+	return exitOK
+}
+
+// parseArgs parses the flags in args wherever they stand, so that both
+// migrate -dry-run goto 3 and migrate goto 3 -dry-run work, and returns
+// the positional arguments in order. Everything after "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
 
-	} else {
-		info := fmt.Sprintf(
-			"postgres://%s:%s@%s:%s/%s?sslmode=%s",
-			cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Host,
-			cfg.Postgres.Port, cfg.Postgres.DB, cfg.Postgres.SSLMode,
-		)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
 
-		migrator, err := migrate.New("file://migrations", info)
-		if err != nil {
-			logger.Error("create migrate instance fail: %s", "error", err)
-			os.Exit(1)
+		// Parse consumes a "--" terminator, so compare with what it saw.
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
 		}
 
-		defer migrator.Close()
-		exec(logger, migrator)
+		positional = append(positional, rest[0])
+		args = rest[1:]
 	}
 }
 
-func up(logger *slog.Logger, migrator *migrate.Migrate) {
-	err := migrator.Up()
-	switch err {
-	case nil:
-		logger.Info("migrations applied successfully")
+func up(logger *slog.Logger, m *migrator.Migrator, opts *options) error {
+	if opts.dryRun {
+		return printSteps(m.Plan(m.Latest()))
+	}
 
-	case migrate.ErrNoChange:
-		logger.Info("no changes to migrate")
+	return report(logger, m, m.Up(), "migrations applied successfully", "no changes to migrate")
+}
 
-	default:
-		logger.Error("migrate up fail", "error", err)
+func down(logger *slog.Logger, m *migrator.Migrator, opts *options) error {
+	if opts.dryRun {
+		return printSteps(m.PlanDown())
 	}
+
+	return report(logger, m, m.Down(), "migration rolled back successfully", "no migrations to rollback")
 }
 
-func down(logger *slog.Logger, migrator *migrate.Migrate) {
-	err := migrator.Steps(-1)
-	switch err {
-	case nil:
-		logger.Info("migration rolled back successfully")
+func goTo(logger *slog.Logger, m *migrator.Migrator, opts *options) error {
+	target, err := versionArg(opts.args)
+	if err != nil {
+		return err
+	}
 
-	case migrate.ErrNoChange:
-		logger.Info("no migrations to rollback")
+	if opts.dryRun {
+		return printSteps(m.Plan(target))
+	}
 
-	default:
-		logger.Error("migrate down fail", "error", err)
+	return report(logger, m, m.Goto(target), "migrated successfully", "already at requested version")
+}
+
+func status(logger *slog.Logger, m *migrator.Migrator, opts *options) error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		state := "pending"
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Applied:
+			state = "applied"
+		}
+
+		fmt.Printf("%03d  %-8s  %s\n", s.Version, state, s.Identifier)
 	}
+
+	return nil
 }
 
-func version(logger *slog.Logger, migrator *migrate.Migrate) {
-	version, dirty, err := migrator.Version()
+func version(logger *slog.Logger, m *migrator.Migrator, opts *options) error {
+	version, dirty, err := m.Version()
 	if err != nil {
-		logger.Error("get migration version fail", "error", err)
+		return err
 	}
 
-	slog.Info("migration version", "version", version, "dirty", dirty)
+	logger.Info("migration version", "version", version, "dirty", dirty, "latest", m.Latest())
+	return nil
 }
 
-func force(logger *slog.Logger, migrator *migrate.Migrate) {
-	if len(os.Args) < 3 {
-		logger.Error("force command requires version number")
-		os.Exit(1)
+func force(logger *slog.Logger, m *migrator.Migrator, opts *options) error {
+	version, err := versionArg(opts.args)
+	if err != nil {
+		return err
 	}
 
-	version, err := strconv.Atoi(os.Args[2])
-	if err != nil || version <= 0 {
-		logger.Error("invalid version")
-		os.Exit(1)
+	if version == 0 {
+		return fmt.Errorf("%w: force requires a positive version", errUsage)
 	}
 
-	if err := migrator.Force(version); err != nil {
-		logger.Error("force version fail", "error", err)
-		os.Exit(1)
+	if err := m.Force(version); err != nil {
+		return err
 	}
 
 	logger.Info("forced version", "version", version)
+	return nil
+}
+
+func create(dir string, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "create requires a migration name")
+		return exitUsage
+	}
+
+	up, down, err := migrator.Create(dir, args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create fail: %s\n", err)
+		if errors.Is(err, migrator.ErrName) {
+			return exitUsage
+		}
+		return exitFail
+	}
+
+	fmt.Println(up)
+	fmt.Println(down)
+	return exitOK
+}
+
+// printSteps prints the SQL a dry run would have run.
+func printSteps(steps []migrator.Step, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		fmt.Println("-- no changes")
+		return nil
+	}
+
+	for _, step := range steps {
+		fmt.Printf("-- %03d_%s.%s.sql\n%s\n\n", step.Version, step.Identifier, step.Direction, step.SQL)
+	}
+
+	return nil
+}
+
+func report(logger *slog.Logger, m *migrator.Migrator, err error, done, unchanged string) error {
+	switch {
+	case err == nil:
+		version, _, _ := m.Version()
+		logger.Info(done, "version", version)
+		return nil
+
+	case errors.Is(err, migrator.ErrNoChange):
+		logger.Info(unchanged)
+		return nil
+
+	default:
+		return err
+	}
+}
+
+func versionArg(args []string) (uint, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: expected one version argument", errUsage)
+	}
+
+	version, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid version %q", errUsage, args[0])
+	}
+
+	return uint(version), nil
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		dryRun bool
+		dir    string
+		want   []string
+	}{
+		{name: "flags first", args: []string{"-dry-run", "goto", "3"}, dryRun: true, want: []string{"goto", "3"}},
+		{name: "flags last", args: []string{"goto", "3", "-dry-run"}, dryRun: true, want: []string{"goto", "3"}},
+		{name: "flags between", args: []string{"goto", "-dry-run", "3"}, dryRun: true, want: []string{"goto", "3"}},
+		{name: "flag with value", args: []string{"create", "add_index", "-dir", "tmp"}, dir: "tmp", want: []string{"create", "add_index"}},
+		{name: "no flags", args: []string{"up"}, want: []string{"up"}},
+		{name: "terminator", args: []string{"goto", "--", "-dry-run"}, want: []string{"goto", "-dry-run"}},
+		{name: "empty", args: nil, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+			dryRun := fs.Bool("dry-run", false, "")
+			dir := fs.String("dir", "", "")
+
+			got, err := parseArgs(fs, test.args)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, test.dryRun, *dryRun)
+			assert.Equal(t, test.dir, *dir)
+		})
+	}
+}
+
+func TestParseArgs_UnknownFlag(t *testing.T) {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	_, err := parseArgs(fs, []string{"up", "-force"})
+	assert.Error(t, err)
+}
@@ -5,18 +5,45 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "github.com/P3rCh1/subs-aggregator/docs"
+	"github.com/P3rCh1/subs-aggregator/internal/budget"
+	"github.com/P3rCh1/subs-aggregator/internal/cache"
 	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/lifecycle"
 	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/metrics"
+	"github.com/P3rCh1/subs-aggregator/internal/migrator"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/rollup"
+	"github.com/P3rCh1/subs-aggregator/internal/scheduler"
+	"github.com/P3rCh1/subs-aggregator/internal/server/gql"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/budgets"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/discounts"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/health"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/members"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/pauses"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/statuses"
 	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/subs"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/webhooks"
 	"github.com/P3rCh1/subs-aggregator/internal/server/middleware"
+	"github.com/P3rCh1/subs-aggregator/internal/server/rpc"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/memory"
 	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/sqlite"
+	"github.com/P3rCh1/subs-aggregator/internal/tracing"
+	"github.com/P3rCh1/subs-aggregator/internal/webhook"
+	"github.com/P3rCh1/subs-aggregator/migrations"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoswagger "github.com/swaggo/echo-swagger"
 )
 
@@ -41,19 +68,116 @@ func main() {
 		os.Exit(1)
 	}
 
-	db, err := postgres.NewSubsAPI(&cfg.Postgres)
+	slog.SetDefault(logger)
+	models.ConfigureDates(models.DateFormat(cfg.Dates.Format), cfg.Dates.DayPrecision)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		logger.Error(
+			"setup tracing fail",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	metrics := metrics.New()
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	db, conn, closeStorage, err := openStorage(jobsCtx, logger, cfg, metrics)
 	if err != nil {
 		logger.Error(
-			"postgres connection fail",
+			"storage",
+			"backend", cfg.Storage.Backend,
 			"error", err,
 		)
 		os.Exit(1)
 	}
-	defer db.Close()
+	defer closeStorage()
+
+	if cfg.Cache.Enabled {
+		if cfg.Storage.Backend == "postgres" && len(cfg.Postgres.Replicas) > 0 {
+			logger.Warn(
+				"summary cache is invalidated in this process only and may keep totals read from lagging replicas",
+				"ttl", cfg.Cache.TTL,
+			)
+		}
+
+		db = cache.NewSubsAPI(db, cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL), metrics)
+	}
+
+	var (
+		evaluator    subs.BudgetEvaluator = budget.NopEvaluator{}
+		hooksAPI     *webhooks.ServerAPI
+		budgetsAPI   *budgets.ServerAPI
+		discountsAPI *discounts.ServerAPI
+		membersAPI   *members.ServerAPI
+		pausesAPI    *pauses.ServerAPI
+		statusesAPI  *statuses.ServerAPI
+		checks       = []health.Check{health.DatabaseCheck(db)}
+	)
+
+	// Webhooks, budgets, discounts, members, pauses, statuses, reminders and business metrics
+	// live in PostgreSQL only; other backends serve subscriptions alone.
+	if conn != nil {
+		hooksDB := postgres.NewWebhooksAPI(conn)
+		budgetsDB := postgres.NewBudgetsAPI(conn)
+		discountsDB := cache.NewDiscountsAPI(postgres.NewDiscountsAPI(conn), db)
+		membersDB := cache.NewMembersAPI(postgres.NewMembersAPI(conn), db)
+		pausesDB := cache.NewPausesAPI(postgres.NewPausesAPI(conn), db)
+		statusDB := cache.NewStatusAPI(postgres.NewStatusAPI(conn), db)
+
+		budgetEvaluator := budget.NewEvaluator(logger, &cfg.Budgets, db, budgetsDB)
+		evaluator = budgetEvaluator
+
+		hooksAPI = webhooks.NewServerAPI(logger, hooksDB)
+		budgetsAPI = budgets.NewServerAPI(logger, budgetsDB, budgetEvaluator)
+		discountsAPI = discounts.NewServerAPI(logger, discountsDB, db, budgetEvaluator)
+		membersAPI = members.NewServerAPI(logger, membersDB, db, budgetEvaluator)
+		pausesAPI = pauses.NewServerAPI(logger, pausesDB, db, statusDB, budgetEvaluator)
+		statusesAPI = statuses.NewServerAPI(logger, statusDB, db, budgetEvaluator)
+		checks = append(checks, health.MigrationCheck(db, postgres.SchemaVersion))
+
+		go metrics.RunBusiness(jobsCtx, logger, postgres.NewStatsAPI(conn), cfg.Metrics.RefreshInterval)
+
+		dispatcher := webhook.NewDispatcher(logger, &cfg.Webhooks, hooksDB)
+		go dispatcher.Run(jobsCtx)
 
-	subs := subs.NewServerAPI(logger, cfg, db)
+		if cfg.Scheduler.Enabled {
+			var notifier scheduler.Notifier = &scheduler.LogNotifier{Logger: logger}
+			if cfg.Scheduler.Notifier == "webhook" {
+				notifier = &webhook.Notifier{Store: hooksDB}
+			}
+
+			reminders := scheduler.New(logger, &cfg.Scheduler, postgres.NewRemindersAPI(conn), notifier)
+			go reminders.Run(jobsCtx)
+		}
+
+		if cfg.Rollup.Enabled {
+			refresher := rollup.NewRefresher(logger, &cfg.Rollup, postgres.NewRollupAPI(conn))
+			go refresher.Run(jobsCtx)
+		}
+
+		if cfg.Lifecycle.Enabled {
+			advancer := lifecycle.NewAdvancer(logger, &cfg.Lifecycle, statusDB)
+			go advancer.Run(jobsCtx)
+		}
+	}
 
-	router := SetupServer(subs)
+	subs := subs.NewServerAPI(logger, cfg, db, evaluator)
+	probes := health.NewServerAPI(logger, cfg.Health.Timeout, checks...)
+
+	graph, err := gql.NewHandler(gql.NewResolver(logger, db, evaluator))
+	if err != nil {
+		logger.Error(
+			"graphql schema",
+			"error", err,
+		)
+		os.Exit(1)
+	}
+
+	router := SetupServer(metrics, subs, hooksAPI, budgetsAPI, discountsAPI, membersAPI, pausesAPI, statusesAPI, probes, graph)
 
 	go func() {
 		logger.Info("start server")
@@ -68,36 +192,191 @@ func main() {
 		}
 	}()
 
+	grpcServer, grpcHealth := rpc.Register(rpc.NewServer(logger, db, evaluator))
+
+	go func() {
+		addr := cfg.GRPC.Host + ":" + cfg.GRPC.Port
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Error(
+				"grpc listen",
+				"error", err,
+			)
+
+			return
+		}
+
+		logger.Info("start grpc server", "addr", addr)
+		if err := grpcServer.Serve(listener); err != nil {
+			logger.Error(
+				"grpc serve",
+				"error", err,
+			)
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("starting shutdown server")
 
+	// Fail readiness first and give load balancers time to notice before
+	// the listeners stop accepting connections.
+	probes.Drain()
+	grpcHealth.Shutdown()
+	time.Sleep(cfg.Health.DrainDelay)
+
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
 
+	defer func() {
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error(
+				"shutdown tracing",
+				"error", err,
+			)
+		}
+	}()
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	if err := router.Shutdown(ctx); err != nil {
 		logger.Error(
 			"shutdown server",
 			"error", err,
 		)
 
+		grpcServer.Stop()
+		return
+	}
+
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		logger.Error(
+			"shutdown grpc server",
+			"error", ctx.Err(),
+		)
+
+		grpcServer.Stop()
 		return
 	}
 
 	logger.Info("server stopped gracefully")
 }
 
-func SetupServer(subs *subs.ServerAPI) *echo.Echo {
+// openStorage opens the subscriptions backend chosen in cfg.Storage. conn
+// is the PostgreSQL pool, or nil for the other backends. Replica health
+// checks run until ctx is done.
+func openStorage(
+	ctx context.Context,
+	logger *slog.Logger,
+	cfg *config.Config,
+	metrics *metrics.Metrics,
+) (db postgres.SubsAPI, conn *sqlx.DB, closeFn func(), err error) {
+	switch cfg.Storage.Backend {
+	case "memory":
+		return metrics.InstrumentSubsAPI(memory.NewSubsAPI()), nil, func() {}, nil
+
+	case "sqlite":
+		lite, err := sqlite.Open(ctx, cfg.Storage.SQLitePath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		metrics.RegisterDB(lite.DB, "sqlite")
+
+		return metrics.InstrumentSubsAPI(sqlite.NewSubsAPI(lite)), nil, func() { lite.Close() }, nil
+	}
+
+	if cfg.Migrations.Auto {
+		if err := migrator.Auto(ctx, logger, &cfg.Postgres, cfg.Migrations.LockKey, migrations.FS); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// The other PostgreSQL stores share conn, so the driver setting
+	// switches them together with subscriptions.
+	var (
+		closeConn func()
+		newSubs   func(reads *postgres.ReplicaSet) postgres.SubsAPI
+	)
+
+	if cfg.Postgres.Driver == "pgx" {
+		pool, err := postgres.OpenPool(ctx, logger, &cfg.Postgres)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		conn = postgres.PoolDB(pool)
+		closeConn = func() {
+			conn.Close()
+			pool.Close()
+		}
+		newSubs = func(reads *postgres.ReplicaSet) postgres.SubsAPI {
+			return postgres.NewReplicatedPgxSubsAPI(pool, reads)
+		}
+	} else {
+		if conn, err = postgres.Open(ctx, logger, &cfg.Postgres); err != nil {
+			return nil, nil, nil, err
+		}
+
+		closeConn = func() { conn.Close() }
+		newSubs = func(reads *postgres.ReplicaSet) postgres.SubsAPI {
+			return postgres.NewReplicatedSubsAPI(conn, reads)
+		}
+	}
+
+	metrics.RegisterDB(conn.DB, "postgres")
+
+	replicas, err := postgres.OpenReplicaSet(ctx, conn, &cfg.Postgres)
+	if err != nil {
+		closeConn()
+		return nil, nil, nil, err
+	}
+
+	go replicas.Run(ctx, logger, cfg.Postgres.ReplicaCheck)
+
+	closeFn = func() {
+		replicas.Close()
+		closeConn()
+	}
+
+	return metrics.InstrumentSubsAPI(newSubs(replicas)), conn, closeFn, nil
+}
+
+func SetupServer(
+	metrics *metrics.Metrics,
+	subs *subs.ServerAPI,
+	hooks *webhooks.ServerAPI,
+	budgets *budgets.ServerAPI,
+	discounts *discounts.ServerAPI,
+	members *members.ServerAPI,
+	pauses *pauses.ServerAPI,
+	statuses *statuses.ServerAPI,
+	probes *health.ServerAPI,
+	graph *gql.Handler,
+) *echo.Echo {
 	router := echo.New()
 
 	router.Debug = false
 	router.HideBanner = true
 	router.Logger.SetOutput(io.Discard)
 
+	router.Use(middleware.Tracing())
+	router.Use(middleware.RequestID(subs.Logger))
+	router.Use(middleware.ReadPrimary())
 	router.Use(middleware.Recover(subs.Logger))
 	router.Use(middleware.Logger(subs.Logger))
+	router.Use(middleware.Metrics(metrics))
 
 	router.POST("/subs", subs.Create)
 	router.GET("/subs/:id", subs.Read)
@@ -105,7 +384,55 @@ func SetupServer(subs *subs.ServerAPI) *echo.Echo {
 	router.DELETE("/subs/:id", subs.Delete)
 	router.GET("/subs/list/:id", subs.List)
 	router.POST("/subs/summary", subs.Summary)
+
+	if hooks != nil {
+		router.POST("/webhooks", hooks.Create)
+		router.GET("/webhooks", hooks.List)
+		router.DELETE("/webhooks/:id", hooks.Delete)
+		router.GET("/webhooks/:id/deliveries", hooks.Deliveries)
+		router.POST("/webhooks/deliveries/:id/redeliver", hooks.Redeliver)
+	}
+
+	if budgets != nil {
+		router.POST("/budgets", budgets.Create)
+		router.GET("/budgets/:id", budgets.Read)
+		router.PUT("/budgets/:id", budgets.Update)
+		router.DELETE("/budgets/:id", budgets.Delete)
+		router.GET("/budgets/list/:id", budgets.List)
+		router.GET("/budgets/alerts/:id", budgets.Alerts)
+	}
+
+	if discounts != nil {
+		router.POST("/subs/:id/discounts", discounts.Create)
+		router.GET("/subs/:id/discounts", discounts.List)
+		router.GET("/discounts/:id", discounts.Read)
+		router.PUT("/discounts/:id", discounts.Update)
+		router.DELETE("/discounts/:id", discounts.Delete)
+	}
+
+	if members != nil {
+		router.GET("/subs/:id/members", members.List)
+		router.PUT("/subs/:id/members/:user", members.Set)
+		router.DELETE("/subs/:id/members/:user", members.Remove)
+	}
+
+	if pauses != nil {
+		router.POST("/subs/:id/pause", pauses.Pause)
+		router.POST("/subs/:id/resume", pauses.Resume)
+		router.GET("/subs/:id/pauses", pauses.List)
+	}
+
+	if statuses != nil {
+		router.POST("/subs/:id/cancel", statuses.Cancel)
+		router.GET("/subs/:id/transitions", statuses.Transitions)
+		router.GET("/subs/churn", statuses.Churn)
+	}
+
+	router.POST("/graphql", graph.Serve)
+	router.GET("/healthz", probes.Live)
+	router.GET("/readyz", probes.Ready)
 	router.GET("/swagger/*", echoswagger.WrapHandler)
+	router.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
 
 	router.Server.Addr = subs.Config.HTTP.Host + ":" + subs.Config.HTTP.Port
 	router.Server.ReadTimeout = subs.Config.HTTP.ReadTimeout
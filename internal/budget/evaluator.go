@@ -0,0 +1,134 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+type Evaluator struct {
+	Logger  *slog.Logger
+	Config  *config.Budgets
+	Subs    postgres.SubsAPI
+	Budgets postgres.BudgetsAPI
+	Now     func() time.Time
+}
+
+func NewEvaluator(
+	logger *slog.Logger,
+	cfg *config.Budgets,
+	subs postgres.SubsAPI,
+	budgets postgres.BudgetsAPI,
+) *Evaluator {
+	return &Evaluator{
+		Logger:  logger,
+		Config:  cfg,
+		Subs:    subs,
+		Budgets: budgets,
+		Now:     time.Now,
+	}
+}
+
+// Evaluate compares every budget of the user with the spend of the current
+// month and the projected spend of the next one, stores state changes and
+// logs budgets that enter a non ok state.
+func (e *Evaluator) Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	budgets, err := e.Budgets.ListBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	y, m, _ := e.Now().UTC().Date()
+	current := models.MonthDate{Time: time.Date(y, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	next := models.MonthDate{Time: current.Time.AddDate(0, 1, 0), Valid: true}
+
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		status := models.BudgetStatus{Budget: budget}
+
+		if status.Current, err = e.spend(ctx, &budget, current); err != nil {
+			return nil, err
+		}
+
+		if status.Next, err = e.spend(ctx, &budget, next); err != nil {
+			return nil, err
+		}
+
+		status.State = e.classify(&status)
+
+		if status.State != budget.State {
+			if err := e.Budgets.SetBudgetState(ctx, budget.ID, status.State); err != nil {
+				return nil, err
+			}
+
+			if status.State != models.BudgetOK {
+				logger.FromContext(ctx, e.Logger).WarnContext(
+					ctx,
+					"budget alert",
+					"budget_id", budget.ID,
+					"user_id", budget.UserID,
+					"state", status.State,
+					"limit", budget.Limit,
+					"current", status.Current,
+					"next", status.Next,
+				)
+			}
+
+			status.Budget.State = status.State
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// spend returns what the subscriptions the budget limits charge for month
+// after discounts.
+func (e *Evaluator) spend(ctx context.Context, budget *models.Budget, month models.MonthDate) (int, error) {
+	sum, err := e.Subs.Summary(ctx, &models.SumRequest{
+		ServiceName: budget.ServiceName,
+		Category:    budget.Category,
+		UserID:      budget.UserID,
+		StartDate:   month,
+		EndDate:     month,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("budget spend fail: %w", err)
+	}
+
+	return sum.Net, nil
+}
+
+// classify reports breached when the current month is over the limit and
+// warning when the next month will be over it or the current one reaches
+// the configured share of it.
+func (e *Evaluator) classify(status *models.BudgetStatus) models.BudgetState {
+	limit := status.Budget.Limit
+
+	switch {
+	case status.Current > limit:
+		return models.BudgetBreached
+
+	case status.Next > limit, status.Current*100 >= limit*e.Config.WarnPercent:
+		return models.BudgetWarning
+
+	default:
+		return models.BudgetOK
+	}
+}
+
+// NopEvaluator reports no budgets. It stands in for Evaluator when the
+// storage backend has no budgets table.
+type NopEvaluator struct{}
+
+func (NopEvaluator) Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	return nil, nil
+}
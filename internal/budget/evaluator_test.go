@@ -0,0 +1,91 @@
+package budget
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSubs struct {
+	postgres.SubsAPI
+	spend map[time.Month]int
+}
+
+func (f *fakeSubs) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	return models.Undiscounted(f.spend[req.StartDate.Time.Month()]), nil
+}
+
+type fakeBudgets struct {
+	postgres.BudgetsAPI
+	budgets []models.Budget
+	states  map[uuid.UUID]models.BudgetState
+}
+
+func (f *fakeBudgets) ListBudgets(ctx context.Context, userID uuid.UUID) ([]models.Budget, error) {
+	return f.budgets, nil
+}
+
+func (f *fakeBudgets) SetBudgetState(ctx context.Context, id uuid.UUID, state models.BudgetState) error {
+	f.states[id] = state
+	return nil
+}
+
+func setup(current, next int, budget models.Budget) (*Evaluator, *fakeBudgets) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	subs := &fakeSubs{spend: map[time.Month]int{time.March: current, time.April: next}}
+	budgets := &fakeBudgets{
+		budgets: []models.Budget{budget},
+		states:  map[uuid.UUID]models.BudgetState{},
+	}
+
+	evaluator := NewEvaluator(logger, &config.Budgets{WarnPercent: 90}, subs, budgets)
+	evaluator.Now = func() time.Time {
+		return time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)
+	}
+
+	return evaluator, budgets
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		next    int
+		want    models.BudgetState
+	}{
+		{name: "under limit", current: 500, next: 500, want: models.BudgetOK},
+		{name: "close to limit", current: 900, next: 900, want: models.BudgetWarning},
+		{name: "next month over limit", current: 500, next: 1200, want: models.BudgetWarning},
+		{name: "current month over limit", current: 1200, next: 1200, want: models.BudgetBreached},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			budget := models.Budget{ID: uuid.New(), UserID: uuid.New(), Limit: 1000, State: models.BudgetOK}
+			evaluator, store := setup(test.current, test.next, budget)
+
+			statuses, err := evaluator.Evaluate(context.Background(), budget.UserID)
+			require.NoError(t, err)
+			require.Len(t, statuses, 1)
+
+			assert.Equal(t, test.want, statuses[0].State)
+			assert.Equal(t, test.current, statuses[0].Current)
+			assert.Equal(t, test.next, statuses[0].Next)
+
+			if test.want == models.BudgetOK {
+				assert.Empty(t, store.states)
+			} else {
+				assert.Equal(t, test.want, store.states[budget.ID])
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+)
+
+// Key is a SumRequest normalised to comparable values: requests that
+// select the same subscriptions over the same months share a key.
+type Key struct {
+	ServiceName string
+	Category    string
+	UserID      uuid.UUID
+	Start       time.Time
+	End         time.Time
+	Prorate     bool
+	Status      models.Status
+}
+
+// KeyFor truncates the request dates to the first day of their month.
+func KeyFor(req *models.SumRequest) Key {
+	return Key{
+		ServiceName: req.ServiceName,
+		Category:    req.Category,
+		UserID:      req.UserID,
+		Start:       month(req.StartDate),
+		End:         month(req.EndDate),
+		Prorate:     req.Prorate,
+		Status:      req.Status,
+	}
+}
+
+func month(m models.MonthDate) time.Time {
+	y, mon, _ := m.Time.Date()
+	return time.Date(y, mon, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Affects reports whether sub is counted by the summary cached under k, so
+// that a change to sub may change the cached total.
+func (k Key) Affects(sub *models.Subscription) bool {
+	if k.UserID != uuid.Nil && k.UserID != sub.UserID {
+		return false
+	}
+
+	if k.ServiceName != "" && k.ServiceName != sub.ServiceName {
+		return false
+	}
+
+	if k.Category != "" && k.Category != sub.Category {
+		return false
+	}
+
+	if k.Status != "" && k.Status != sub.Status {
+		return false
+	}
+
+	if month(sub.StartDate).After(k.End) {
+		return false
+	}
+
+	return !sub.EndDate.Valid || !month(sub.EndDate).Before(k.Start)
+}
+
+// Backend stores summaries. Implementations must be safe for concurrent
+// use.
+type Backend interface {
+	Get(key Key) (models.Summary, bool)
+	Set(key Key, total models.Summary)
+	// DeleteFunc removes the entries whose key matches and returns how
+	// many were removed.
+	DeleteFunc(match func(Key) bool) int
+}
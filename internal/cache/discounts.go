@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+type cachedDiscounts struct {
+	postgres.DiscountsAPI
+	subs *cachedSubs
+}
+
+// NewDiscountsAPI drops the summaries cached by subs, as returned by
+// NewSubsAPI, that count the subscription of a created, updated or deleted
+// discount. Any other subs leaves db as is.
+func NewDiscountsAPI(db postgres.DiscountsAPI, subs postgres.SubsAPI) postgres.DiscountsAPI {
+	cached, ok := subs.(*cachedSubs)
+	if !ok {
+		return db
+	}
+
+	return &cachedDiscounts{
+		DiscountsAPI: db,
+		subs:         cached,
+	}
+}
+
+func (c *cachedDiscounts) CreateDiscount(ctx context.Context, discount *models.Discount) error {
+	if err := c.DiscountsAPI.CreateDiscount(ctx, discount); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, discount.SubscriptionID)
+	return nil
+}
+
+func (c *cachedDiscounts) UpdateDiscount(ctx context.Context, discount *models.Discount) error {
+	if err := c.DiscountsAPI.UpdateDiscount(ctx, discount); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, discount.SubscriptionID)
+	return nil
+}
+
+func (c *cachedDiscounts) DeleteDiscount(ctx context.Context, id uuid.UUID) error {
+	discount, err := c.DiscountsAPI.ReadDiscount(postgres.WithPrimary(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.DiscountsAPI.DeleteDiscount(ctx, id); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, discount.SubscriptionID)
+	return nil
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/memory"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDiscounts struct {
+	postgres.DiscountsAPI
+	discounts map[uuid.UUID]models.Discount
+}
+
+func (f *fakeDiscounts) CreateDiscount(ctx context.Context, discount *models.Discount) error {
+	discount.ID = uuid.New()
+	f.discounts[discount.ID] = *discount
+	return nil
+}
+
+func (f *fakeDiscounts) ReadDiscount(ctx context.Context, id uuid.UUID) (*models.Discount, error) {
+	discount, ok := f.discounts[id]
+	if !ok {
+		return nil, postgres.ErrNotFound
+	}
+	return &discount, nil
+}
+
+func (f *fakeDiscounts) DeleteDiscount(ctx context.Context, id uuid.UUID) error {
+	delete(f.discounts, id)
+	return nil
+}
+
+func TestDiscounts_Invalidate(t *testing.T) {
+	store, db, m := setup()
+	discounts := NewDiscountsAPI(&fakeDiscounts{discounts: map[uuid.UUID]models.Discount{}}, db)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	summary := func(service string) {
+		_, err := db.Summary(ctx, yearOf(service))
+		require.NoError(t, err)
+	}
+
+	summary("Netflix")
+	summary("Spotify")
+	require.Equal(t, 2, store.summaries)
+
+	discount := &models.Discount{
+		SubscriptionID: sub.ID, Kind: models.DiscountPercent, Amount: 10, StartDate: sub.StartDate,
+	}
+	require.NoError(t, discounts.CreateDiscount(ctx, discount))
+
+	summary("Netflix")
+	summary("Spotify")
+	assert.Equal(t, 3, store.summaries, "only the Netflix total must be dropped")
+
+	require.NoError(t, discounts.DeleteDiscount(ctx, discount.ID))
+	summary("Netflix")
+	assert.Equal(t, 4, store.summaries)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.CacheInvalidations))
+}
+
+func TestNewDiscountsAPI_Uncached(t *testing.T) {
+	db := &fakeDiscounts{}
+	assert.Same(t, db, NewDiscountsAPI(db, memory.NewSubsAPI()))
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+)
+
+type entry struct {
+	key     Key
+	total   models.Summary
+	expires time.Time
+}
+
+// LRU keeps at most size entries, each for at most ttl, and evicts the
+// least recently used one when full.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[Key]*list.Element
+
+	Now func() time.Time
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[Key]*list.Element, size),
+		Now:     time.Now,
+	}
+}
+
+func (c *LRU) Get(key Key) (models.Summary, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return models.Summary{}, false
+	}
+
+	e := elem.Value.(*entry)
+	if !c.Now().Before(e.expires) {
+		c.remove(elem)
+		return models.Summary{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return e.total, true
+}
+
+func (c *LRU) Set(key Key, total models.Summary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.Now().Add(c.ttl)
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.total, e.expires = total, expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, total: total, expires: expires})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU) DeleteFunc(match func(Key) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*entry).key) {
+			c.remove(elem)
+			removed++
+		}
+		elem = next
+	}
+
+	return removed
+}
+
+// Len returns the number of entries, expired ones included.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
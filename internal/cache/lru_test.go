@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func key(service string) Key {
+	return Key{ServiceName: service}
+}
+
+func TestLRU_Evicts(t *testing.T) {
+	c := NewLRU(2, time.Minute)
+
+	c.Set(key("a"), models.Undiscounted(1))
+	c.Set(key("b"), models.Undiscounted(2))
+
+	_, ok := c.Get(key("a"))
+	assert.True(t, ok)
+
+	c.Set(key("c"), models.Undiscounted(3))
+
+	_, ok = c.Get(key("b"))
+	assert.False(t, ok, "least recently used entry must be evicted")
+
+	total, ok := c.Get(key("a"))
+	assert.True(t, ok)
+	assert.Equal(t, 1, total.Net)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_Expires(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU(10, time.Minute)
+	c.Now = func() time.Time { return now }
+
+	c.Set(key("a"), models.Undiscounted(1))
+
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get(key("a"))
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get(key("a"))
+	assert.False(t, ok)
+	assert.Zero(t, c.Len())
+}
+
+func TestLRU_SetRefreshes(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU(10, time.Minute)
+	c.Now = func() time.Time { return now }
+
+	c.Set(key("a"), models.Undiscounted(1))
+	now = now.Add(30 * time.Second)
+	c.Set(key("a"), models.Undiscounted(2))
+	now = now.Add(45 * time.Second)
+
+	total, ok := c.Get(key("a"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, total.Net)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestLRU_DeleteFunc(t *testing.T) {
+	c := NewLRU(10, time.Minute)
+	c.Set(key("a"), models.Undiscounted(1))
+	c.Set(key("b"), models.Undiscounted(2))
+	c.Set(key("c"), models.Undiscounted(3))
+
+	removed := c.DeleteFunc(func(k Key) bool { return k.ServiceName != "b" })
+	assert.Equal(t, 2, removed)
+
+	_, ok := c.Get(key("b"))
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.Len())
+}
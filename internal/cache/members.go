@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+type cachedMembers struct {
+	postgres.MembersAPI
+	subs *cachedSubs
+}
+
+// NewMembersAPI drops the summaries cached by subs, as returned by
+// NewSubsAPI, that count a share of a subscription whose members change.
+// It also lets subs find the members of the subscriptions it writes. Any
+// other subs leaves db as is.
+func NewMembersAPI(db postgres.MembersAPI, subs postgres.SubsAPI) postgres.MembersAPI {
+	cached, ok := subs.(*cachedSubs)
+	if !ok {
+		return db
+	}
+
+	cached.members = db
+
+	return &cachedMembers{
+		MembersAPI: db,
+		subs:       cached,
+	}
+}
+
+func (c *cachedMembers) SetMember(ctx context.Context, member *models.Member) error {
+	if err := c.MembersAPI.SetMember(ctx, member); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, member.SubscriptionID)
+	return nil
+}
+
+func (c *cachedMembers) RemoveMember(ctx context.Context, subID, userID uuid.UUID) error {
+	if err := c.MembersAPI.RemoveMember(ctx, subID, userID); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, subID, models.Member{UserID: userID})
+	return nil
+}
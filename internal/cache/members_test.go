@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMembers struct {
+	postgres.MembersAPI
+	members map[uuid.UUID][]models.Member
+}
+
+func (f *fakeMembers) SetMember(ctx context.Context, member *models.Member) error {
+	f.members[member.SubscriptionID] = append(f.members[member.SubscriptionID], *member)
+	return nil
+}
+
+func (f *fakeMembers) RemoveMember(ctx context.Context, subID, userID uuid.UUID) error {
+	kept := f.members[subID][:0]
+	for _, m := range f.members[subID] {
+		if m.UserID != userID {
+			kept = append(kept, m)
+		}
+	}
+	f.members[subID] = kept
+	return nil
+}
+
+func (f *fakeMembers) ListMembers(ctx context.Context, subID uuid.UUID) ([]models.Member, error) {
+	return f.members[subID], nil
+}
+
+func TestMembers_Invalidate(t *testing.T) {
+	store, db, _ := setup()
+	members := NewMembersAPI(&fakeMembers{members: map[uuid.UUID][]models.Member{}}, db)
+	ctx := context.Background()
+
+	owner, member, other := uuid.New(), uuid.New(), uuid.New()
+
+	sub := &models.Subscription{
+		ServiceName: "Spotify", Price: 300, UserID: owner, StartDate: storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	summary := func(user uuid.UUID) {
+		req := yearOf("Spotify")
+		req.UserID = user
+		_, err := db.Summary(ctx, req)
+		require.NoError(t, err)
+	}
+
+	summary(member)
+	summary(other)
+	require.Equal(t, 2, store.summaries)
+
+	require.NoError(t, members.SetMember(ctx, &models.Member{SubscriptionID: sub.ID, UserID: member, Weight: 1}))
+	summary(member)
+	summary(other)
+	assert.Equal(t, 3, store.summaries, "only the member total must be dropped")
+
+	sub.Price = 400
+	require.NoError(t, db.Update(ctx, sub))
+	summary(member)
+	assert.Equal(t, 4, store.summaries, "updates must drop the totals of members")
+
+	require.NoError(t, members.RemoveMember(ctx, sub.ID, member))
+	summary(member)
+	summary(other)
+	assert.Equal(t, 5, store.summaries, "removed members must not keep their share")
+}
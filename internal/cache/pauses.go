@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+type cachedPauses struct {
+	postgres.PausesAPI
+	subs *cachedSubs
+}
+
+// NewPausesAPI drops the summaries cached by subs, as returned by
+// NewSubsAPI, that count the subscription of a created or updated pause.
+// Any other subs leaves db as is.
+func NewPausesAPI(db postgres.PausesAPI, subs postgres.SubsAPI) postgres.PausesAPI {
+	cached, ok := subs.(*cachedSubs)
+	if !ok {
+		return db
+	}
+
+	return &cachedPauses{
+		PausesAPI: db,
+		subs:      cached,
+	}
+}
+
+func (c *cachedPauses) CreatePause(ctx context.Context, pause *models.Pause) error {
+	if err := c.PausesAPI.CreatePause(ctx, pause); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, pause.SubscriptionID)
+	return nil
+}
+
+func (c *cachedPauses) UpdatePause(ctx context.Context, pause *models.Pause) error {
+	if err := c.PausesAPI.UpdatePause(ctx, pause); err != nil {
+		return err
+	}
+
+	c.subs.invalidateSub(ctx, pause.SubscriptionID)
+	return nil
+}
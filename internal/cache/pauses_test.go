@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePauses struct {
+	postgres.PausesAPI
+}
+
+func (f *fakePauses) CreatePause(ctx context.Context, pause *models.Pause) error {
+	pause.ID = uuid.New()
+	return nil
+}
+
+func (f *fakePauses) UpdatePause(ctx context.Context, pause *models.Pause) error {
+	return nil
+}
+
+func TestPauses_Invalidate(t *testing.T) {
+	store, db, _ := setup()
+	pauses := NewPausesAPI(&fakePauses{}, db)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	summary := func(service string) {
+		_, err := db.Summary(ctx, yearOf(service))
+		require.NoError(t, err)
+	}
+
+	summary("Netflix")
+	summary("Spotify")
+	require.Equal(t, 2, store.summaries)
+
+	pause := &models.Pause{SubscriptionID: sub.ID, StartDate: storagetest.Month(2024, time.June)}
+	require.NoError(t, pauses.CreatePause(ctx, pause))
+
+	summary("Netflix")
+	summary("Spotify")
+	assert.Equal(t, 3, store.summaries, "only the Netflix total must be dropped")
+
+	pause.Resume(storagetest.Month(2024, time.September))
+	require.NoError(t, pauses.UpdatePause(ctx, pause))
+	summary("Netflix")
+	assert.Equal(t, 4, store.summaries)
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+type cachedStatus struct {
+	postgres.StatusAPI
+	subs *cachedSubs
+}
+
+// NewStatusAPI drops the summaries cached by subs, as returned by
+// NewSubsAPI, that count a subscription before or after it changes status.
+// Any other subs leaves db as is.
+func NewStatusAPI(db postgres.StatusAPI, subs postgres.SubsAPI) postgres.StatusAPI {
+	cached, ok := subs.(*cachedSubs)
+	if !ok {
+		return db
+	}
+
+	return &cachedStatus{
+		StatusAPI: db,
+		subs:      cached,
+	}
+}
+
+func (c *cachedStatus) Cancel(ctx context.Context, cancellation *models.Cancellation) (*models.Transition, error) {
+	old, err := c.subs.SubsAPI.Read(postgres.WithPrimary(ctx), cancellation.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := c.subs.withMembers(ctx, old)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := c.StatusAPI.Cancel(ctx, cancellation)
+	if err != nil {
+		return nil, err
+	}
+
+	c.subs.invalidate(affected...)
+	c.subs.invalidateSub(ctx, cancellation.SubscriptionID)
+	return transition, nil
+}
+
+func (c *cachedStatus) Advance(ctx context.Context, month models.MonthDate, reason string, ids ...uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		moved, err := c.StatusAPI.Advance(ctx, month, reason)
+		if moved > 0 {
+			c.subs.invalidateAll()
+		}
+
+		return moved, err
+	}
+
+	var affected []*models.Subscription
+
+	for _, id := range ids {
+		old, err := c.subs.SubsAPI.Read(postgres.WithPrimary(ctx), id)
+		if err != nil {
+			return 0, err
+		}
+
+		subs, err := c.subs.withMembers(ctx, old)
+		if err != nil {
+			return 0, err
+		}
+
+		affected = append(affected, subs...)
+	}
+
+	moved, err := c.StatusAPI.Advance(ctx, month, reason, ids...)
+	if moved == 0 {
+		return moved, err
+	}
+
+	c.subs.invalidate(affected...)
+
+	for _, id := range ids {
+		c.subs.invalidateSub(ctx, id)
+	}
+
+	return moved, err
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStatus struct {
+	postgres.StatusAPI
+	moved int
+}
+
+func (f *fakeStatus) Cancel(ctx context.Context, cancellation *models.Cancellation) (*models.Transition, error) {
+	return &models.Transition{
+		ID:             uuid.New(),
+		SubscriptionID: cancellation.SubscriptionID,
+		From:           models.StatusActive,
+		To:             models.StatusCancelled,
+		Reason:         cancellation.TransitionReason(),
+	}, nil
+}
+
+func (f *fakeStatus) Advance(ctx context.Context, month models.MonthDate, reason string, ids ...uuid.UUID) (int, error) {
+	return f.moved, nil
+}
+
+func TestStatus_Invalidate(t *testing.T) {
+	store, db, _ := setup()
+	fake := &fakeStatus{}
+	status := NewStatusAPI(fake, db)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	summary := func(service string) {
+		req := yearOf(service)
+		req.Status = models.StatusActive
+		_, err := db.Summary(ctx, req)
+		require.NoError(t, err)
+	}
+
+	summary("Netflix")
+	summary("Spotify")
+	require.Equal(t, 2, store.summaries)
+
+	_, err := status.Cancel(ctx, &models.Cancellation{
+		SubscriptionID: sub.ID, EffectiveDate: storagetest.Month(2024, time.June), ReasonCode: models.CancelTooExpensive,
+	})
+	require.NoError(t, err)
+
+	summary("Netflix")
+	summary("Spotify")
+	assert.Equal(t, 3, store.summaries, "only the Netflix total must be dropped")
+
+	_, err = status.Advance(ctx, storagetest.Month(2024, time.July), "")
+	require.NoError(t, err)
+	summary("Spotify")
+	assert.Equal(t, 3, store.summaries, "nothing moved, nothing to drop")
+
+	fake.moved = 1
+	_, err = status.Advance(ctx, storagetest.Month(2024, time.July), "")
+	require.NoError(t, err)
+	summary("Spotify")
+	assert.Equal(t, 4, store.summaries, "a move across all subscriptions drops everything")
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/P3rCh1/subs-aggregator/internal/metrics"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+type cachedSubs struct {
+	postgres.SubsAPI
+	backend Backend
+	metrics *metrics.Metrics
+
+	// members, set by NewMembersAPI, lists the members of shared
+	// subscriptions whose cached shares a write changes too.
+	members postgres.MembersAPI
+
+	// generation changes on every invalidation. A total computed while it
+	// changed may predate the write and is not stored. mu makes the check
+	// and the store one step that an invalidation cannot fall between.
+	mu         sync.Mutex
+	generation uint64
+}
+
+// NewSubsAPI caches the results of db.Summary in backend. Writes drop only
+// the entries whose filters and date range cover the old or the new
+// version of the changed subscription. Only writes made through this
+// process are seen: entries stay stale for their TTL after writes of
+// other instances, and may be filled from a lagging replica.
+func NewSubsAPI(db postgres.SubsAPI, backend Backend, m *metrics.Metrics) postgres.SubsAPI {
+	return &cachedSubs{
+		SubsAPI: db,
+		backend: backend,
+		metrics: m,
+	}
+}
+
+func (c *cachedSubs) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	key := KeyFor(req)
+
+	if total, ok := c.backend.Get(key); ok {
+		c.metrics.CacheRequests.WithLabelValues("hit").Inc()
+		return total, nil
+	}
+
+	c.metrics.CacheRequests.WithLabelValues("miss").Inc()
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	total, err := c.SubsAPI.Summary(ctx, req)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.backend.Set(key, total)
+	}
+	c.mu.Unlock()
+
+	return total, nil
+}
+
+func (c *cachedSubs) Create(ctx context.Context, sub *models.Subscription) error {
+	if err := c.SubsAPI.Create(ctx, sub); err != nil {
+		return err
+	}
+
+	c.invalidate(sub)
+	return nil
+}
+
+func (c *cachedSubs) Update(ctx context.Context, sub *models.Subscription) error {
+	old, err := c.SubsAPI.Read(postgres.WithPrimary(ctx), sub.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := c.withMembers(ctx, old, sub)
+	if err != nil {
+		return err
+	}
+
+	if err := c.SubsAPI.Update(ctx, sub); err != nil {
+		return err
+	}
+
+	c.invalidate(affected...)
+	return nil
+}
+
+func (c *cachedSubs) Delete(ctx context.Context, id uuid.UUID) error {
+	old, err := c.SubsAPI.Read(postgres.WithPrimary(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	// Members go with the subscription, list them first.
+	affected, err := c.withMembers(ctx, old)
+	if err != nil {
+		return err
+	}
+
+	if err := c.SubsAPI.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	c.invalidate(affected...)
+	return nil
+}
+
+// withMembers returns subs followed by a copy of every sub for each of its
+// members, owned by the member, so that Key.Affects matches the summaries
+// of every user paying a share of it.
+func (c *cachedSubs) withMembers(ctx context.Context, subs ...*models.Subscription) ([]*models.Subscription, error) {
+	if c.members == nil {
+		return subs, nil
+	}
+
+	affected := subs
+
+	for _, sub := range subs {
+		members, err := c.members.ListMembers(postgres.WithPrimary(ctx), sub.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range members {
+			share := *sub
+			share.UserID = m.UserID
+			affected = append(affected, &share)
+		}
+	}
+
+	return affected, nil
+}
+
+func (c *cachedSubs) invalidate(subs ...*models.Subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	removed := c.backend.DeleteFunc(func(key Key) bool {
+		for _, sub := range subs {
+			if key.Affects(sub) {
+				return true
+			}
+		}
+		return false
+	})
+
+	c.metrics.CacheInvalidations.Add(float64(removed))
+}
+
+// invalidateSub drops the summaries counting the subscription subID after
+// a change made elsewhere, or all of them when it cannot be read: the
+// change has already happened.
+func (c *cachedSubs) invalidateSub(ctx context.Context, subID uuid.UUID, extra ...models.Member) {
+	sub, err := c.SubsAPI.Read(postgres.WithPrimary(ctx), subID)
+	if err != nil {
+		c.invalidateAll()
+		return
+	}
+
+	affected, err := c.withMembers(ctx, sub)
+	if err != nil {
+		c.invalidateAll()
+		return
+	}
+
+	for _, m := range extra {
+		share := *sub
+		share.UserID = m.UserID
+		affected = append(affected, &share)
+	}
+
+	c.invalidate(affected...)
+}
+
+func (c *cachedSubs) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	removed := c.backend.DeleteFunc(func(Key) bool { return true })
+
+	c.metrics.CacheInvalidations.Add(float64(removed))
+}
@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/metrics"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/memory"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsAPI(t *testing.T) {
+	storagetest.RunSubsAPI(t, func(t *testing.T) postgres.SubsAPI {
+		return NewSubsAPI(memory.NewSubsAPI(), NewLRU(100, time.Minute), metrics.New())
+	})
+}
+
+func TestKeyFor_Normalizes(t *testing.T) {
+	req := &models.SumRequest{
+		ServiceName: "Netflix",
+		StartDate:   models.MonthDate{Time: time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC), Valid: true},
+		EndDate:     models.MonthDate{Time: time.Date(2024, time.March, 1, 3, 0, 0, 0, time.FixedZone("MSK", 3*3600)), Valid: true},
+	}
+
+	assert.Equal(t, Key{
+		ServiceName: "Netflix",
+		Start:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}, KeyFor(req))
+}
+
+func TestKey_Affects(t *testing.T) {
+	alice := uuid.New()
+
+	base := Key{
+		Start: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	defaultSub := func() *models.Subscription {
+		return &models.Subscription{
+			ServiceName: "Netflix",
+			Category:    "video",
+			UserID:      alice,
+			StartDate:   storagetest.Month(2024, time.January),
+			EndDate:     storagetest.Month(2024, time.March),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		key    func(k *Key)
+		sub    func(s *models.Subscription)
+		affect bool
+	}{
+		{name: "no filters", affect: true},
+		{name: "same user", key: func(k *Key) { k.UserID = alice }, affect: true},
+		{name: "other user", key: func(k *Key) { k.UserID = uuid.New() }},
+		{name: "same service", key: func(k *Key) { k.ServiceName = "Netflix" }, affect: true},
+		{name: "other service", key: func(k *Key) { k.ServiceName = "Spotify" }},
+		{name: "same category", key: func(k *Key) { k.Category = "video" }, affect: true},
+		{name: "other category", key: func(k *Key) { k.Category = "music" }},
+		{name: "ends before range", sub: func(s *models.Subscription) { s.EndDate = storagetest.Month(2024, time.February) }},
+		{name: "starts after range", sub: func(s *models.Subscription) {
+			s.StartDate, s.EndDate = storagetest.Month(2024, time.July), models.MonthDate{}
+		}},
+		{name: "starts on last month", sub: func(s *models.Subscription) {
+			s.StartDate, s.EndDate = storagetest.Month(2024, time.June), models.MonthDate{}
+		}, affect: true},
+		{name: "open ended", sub: func(s *models.Subscription) { s.EndDate = models.MonthDate{} }, affect: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, sub := base, defaultSub()
+			if test.key != nil {
+				test.key(&key)
+			}
+			if test.sub != nil {
+				test.sub(sub)
+			}
+
+			assert.Equal(t, test.affect, key.Affects(sub))
+		})
+	}
+}
+
+type countingSubs struct {
+	postgres.SubsAPI
+	summaries int
+}
+
+func (c *countingSubs) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	c.summaries++
+	return c.SubsAPI.Summary(ctx, req)
+}
+
+func setup() (*countingSubs, postgres.SubsAPI, *metrics.Metrics) {
+	m := metrics.New()
+	store := &countingSubs{SubsAPI: memory.NewSubsAPI()}
+	return store, NewSubsAPI(store, NewLRU(100, time.Minute), m), m
+}
+
+func yearOf(service string) *models.SumRequest {
+	return &models.SumRequest{
+		ServiceName: service,
+		StartDate:   storagetest.Month(2024, time.January),
+		EndDate:     storagetest.Month(2024, time.December),
+	}
+}
+
+func TestSummary_HitsAndMisses(t *testing.T) {
+	store, db, m := setup()
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(ctx, &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+	}))
+
+	for range 3 {
+		total, err := db.Summary(ctx, yearOf("Netflix"))
+		require.NoError(t, err)
+		assert.Equal(t, 12000, total.Net)
+	}
+
+	assert.Equal(t, 1, store.summaries)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.CacheRequests.WithLabelValues("miss")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.CacheRequests.WithLabelValues("hit")))
+}
+
+func TestWrites_InvalidateAffectedOnly(t *testing.T) {
+	store, db, m := setup()
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	summary := func(service string) int {
+		total, err := db.Summary(ctx, yearOf(service))
+		require.NoError(t, err)
+		return total.Net
+	}
+
+	summary("Netflix")
+	summary("Spotify")
+	require.Equal(t, 2, store.summaries)
+
+	require.NoError(t, db.Create(ctx, &models.Subscription{
+		ServiceName: "Spotify", Price: 300, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.June),
+	}))
+
+	assert.Equal(t, 12000, summary("Netflix"))
+	assert.Equal(t, 2, store.summaries, "Netflix total must stay cached")
+	assert.Equal(t, 2100, summary("Spotify"))
+	assert.Equal(t, 3, store.summaries)
+
+	// Renaming moves the subscription out of one cached total into another.
+	sub.ServiceName = "Spotify"
+	require.NoError(t, db.Update(ctx, sub))
+
+	assert.Equal(t, 0, summary("Netflix"))
+	assert.Equal(t, 14100, summary("Spotify"))
+	assert.Equal(t, 5, store.summaries)
+
+	require.NoError(t, db.Delete(ctx, sub.ID))
+	assert.Equal(t, 2100, summary("Spotify"))
+	assert.Equal(t, 0, summary("Netflix"))
+	assert.Equal(t, 6, store.summaries)
+
+	assert.Equal(t, float64(4), testutil.ToFloat64(m.CacheInvalidations))
+}
+
+func TestWrites_NotFound(t *testing.T) {
+	_, db, _ := setup()
+
+	err := db.Update(context.Background(), &models.Subscription{ID: uuid.New()})
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+
+	err = db.Delete(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+}
+
+type racingSubs struct {
+	postgres.SubsAPI
+	during func()
+}
+
+func (r *racingSubs) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	total, err := r.SubsAPI.Summary(ctx, req)
+	if r.during != nil {
+		r.during()
+		r.during = nil
+	}
+	return total, err
+}
+
+func TestSummary_SkipsStaleStore(t *testing.T) {
+	store := &racingSubs{SubsAPI: memory.NewSubsAPI()}
+	backend := NewLRU(100, time.Minute)
+	db := NewSubsAPI(store, backend, metrics.New())
+	ctx := context.Background()
+
+	store.during = func() {
+		require.NoError(t, db.Create(ctx, &models.Subscription{
+			ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+		}))
+	}
+
+	total, err := db.Summary(ctx, yearOf("Netflix"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, total.Net)
+	assert.Zero(t, backend.Len(), "total computed before the write must not be cached")
+
+	total, err = db.Summary(ctx, yearOf("Netflix"))
+	require.NoError(t, err)
+	assert.Equal(t, 12000, total.Net)
+}
+
+// slowSet delays every store, so that an invalidation started meanwhile
+// would land between the generation check and the store without a lock.
+type slowSet struct {
+	Backend
+	during func()
+}
+
+func (s *slowSet) Set(key Key, total models.Summary) {
+	if s.during != nil {
+		s.during()
+		s.during = nil
+	}
+	s.Backend.Set(key, total)
+}
+
+func TestSummary_InvalidateDuringSet(t *testing.T) {
+	lru := NewLRU(100, time.Minute)
+	backend := &slowSet{Backend: lru}
+	db := NewSubsAPI(memory.NewSubsAPI(), backend, metrics.New())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	backend.during = func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, db.Create(ctx, &models.Subscription{
+				ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+			}))
+		}()
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	total, err := db.Summary(ctx, yearOf("Netflix"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, total.Net)
+
+	wg.Wait()
+	assert.Zero(t, lru.Len(), "the invalidation must drop the total stored before it")
+
+	total, err = db.Summary(ctx, yearOf("Netflix"))
+	require.NoError(t, err)
+	assert.Equal(t, 12000, total.Net)
+}
@@ -3,14 +3,29 @@ package config
 import "time"
 
 type Config struct {
-	Logger   Logger   `yaml:"logger"`
-	HTTP     HTTP     `yaml:"server"`
-	Postgres Postgres `yaml:"postgres"`
+	Logger     Logger     `yaml:"logger"`
+	HTTP       HTTP       `yaml:"server"`
+	GRPC       GRPC       `yaml:"grpc"`
+	Storage    Storage    `yaml:"storage"`
+	Postgres   Postgres   `yaml:"postgres"`
+	Webhooks   Webhooks   `yaml:"webhooks"`
+	Scheduler  Scheduler  `yaml:"scheduler"`
+	Budgets    Budgets    `yaml:"budgets"`
+	Metrics    Metrics    `yaml:"metrics"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Health     Health     `yaml:"health"`
+	Cache      Cache      `yaml:"cache"`
+	Rollup     Rollup     `yaml:"rollup"`
+	Lifecycle  Lifecycle  `yaml:"lifecycle"`
+	Migrations Migrations `yaml:"migrations"`
+	Dates      Dates      `yaml:"dates"`
 }
 
 type Logger struct {
-	Level  string `yaml:"level"  env:"LOG_LEVEL"  env-default:"info"`
-	Format string `yaml:"format" env:"LOG_FORMAT" env-default:"json"`
+	Level    string   `yaml:"level"    env:"LOG_LEVEL"    env-default:"info"`
+	Format   string   `yaml:"format"   env:"LOG_FORMAT"   env-default:"json"`
+	Sampling float64  `yaml:"sampling" env:"LOG_SAMPLING" env-default:"1" validate:"gt=0,lte=1"`
+	Redact   []string `yaml:"redact"   env:"LOG_REDACT"   env-default:"password,secret,token,authorization"`
 }
 
 type HTTP struct {
@@ -22,11 +37,103 @@ type HTTP struct {
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"20s"`
 }
 
+type GRPC struct {
+	Host string `yaml:"host" env:"GRPC_HOST" env-default:"localhost"`
+	Port string `yaml:"port" env:"GRPC_PORT" env-default:"9090"`
+}
+
 type Postgres struct {
-	Host     string `yaml:"host"     env:"POSTGRES_HOST"     env-default:"localhost"`
-	Port     string `yaml:"port"     env:"POSTGRES_PORT"     env-default:"5432"`
-	User     string `                env:"POSTGRES_USER"     validate:"required"`
-	Password string `                env:"POSTGRES_PASSWORD" validate:"required"`
-	DB       string `                env:"POSTGRES_DB"       validate:"required"`
-	SSLMode  string `yaml:"ssl_mode" env:"POSTGRES_SSL_MODE" env-default:"disable"`
+	Host             string        `yaml:"host"               env:"POSTGRES_HOST"               env-default:"localhost"`
+	Port             string        `yaml:"port"               env:"POSTGRES_PORT"               env-default:"5432"`
+	User             string        `                          env:"POSTGRES_USER"               validate:"required"`
+	Password         string        `                          env:"POSTGRES_PASSWORD"           validate:"required"`
+	DB               string        `                          env:"POSTGRES_DB"                 validate:"required"`
+	SSLMode          string        `yaml:"ssl_mode"           env:"POSTGRES_SSL_MODE"           env-default:"disable"`
+	MaxOpenConns     int           `yaml:"max_open_conns"     env:"POSTGRES_MAX_OPEN_CONNS"     env-default:"25" validate:"gte=0"`
+	MaxIdleConns     int           `yaml:"max_idle_conns"     env:"POSTGRES_MAX_IDLE_CONNS"     env-default:"5"  validate:"gte=0"`
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime"  env:"POSTGRES_CONN_MAX_LIFETIME"  env-default:"30m"`
+	ConnMaxIdleTime  time.Duration `yaml:"conn_max_idle_time" env:"POSTGRES_CONN_MAX_IDLE_TIME" env-default:"5m"`
+	StatementTimeout time.Duration `yaml:"statement_timeout"  env:"POSTGRES_STATEMENT_TIMEOUT"  env-default:"30s"`
+	ApplicationName  string        `yaml:"application_name"   env:"POSTGRES_APPLICATION_NAME"   env-default:"subs-aggregator"`
+	StartupTimeout   time.Duration `yaml:"startup_timeout"    env:"POSTGRES_STARTUP_TIMEOUT"    env-default:"1m" validate:"gt=0"`
+	RetryBackoff     time.Duration `yaml:"retry_backoff"      env:"POSTGRES_RETRY_BACKOFF"      env-default:"500ms"`
+	RetryMaxBackoff  time.Duration `yaml:"retry_max_backoff"  env:"POSTGRES_RETRY_MAX_BACKOFF"  env-default:"10s"`
+	Replicas         []string      `yaml:"replicas"           env:"POSTGRES_REPLICAS"`
+	ReplicaCheck     time.Duration `yaml:"replica_check"      env:"POSTGRES_REPLICA_CHECK"      env-default:"5s"`
+	ReplicaTimeout   time.Duration `yaml:"replica_timeout"    env:"POSTGRES_REPLICA_TIMEOUT"    env-default:"1s" validate:"gt=0"`
+	Driver           string        `yaml:"driver"             env:"POSTGRES_DRIVER"             env-default:"pq" validate:"oneof=pq pgx"`
+	StatementCache   int           `yaml:"statement_cache"    env:"POSTGRES_STATEMENT_CACHE"    env-default:"512" validate:"gte=0"`
+}
+
+type Storage struct {
+	Backend    string `yaml:"backend"     env:"STORAGE_BACKEND"     env-default:"postgres" validate:"oneof=postgres memory sqlite"`
+	SQLitePath string `yaml:"sqlite_path" env:"STORAGE_SQLITE_PATH" env-default:"subs.db"`
+}
+
+type Webhooks struct {
+	PollInterval time.Duration `yaml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL" env-default:"5s"`
+	BatchSize    int           `yaml:"batch_size"    env:"WEBHOOKS_BATCH_SIZE"    env-default:"100" validate:"gt=0"`
+	MaxAttempts  int           `yaml:"max_attempts"  env:"WEBHOOKS_MAX_ATTEMPTS"  env-default:"8"   validate:"gt=0"`
+	BaseBackoff  time.Duration `yaml:"base_backoff"  env:"WEBHOOKS_BASE_BACKOFF"  env-default:"10s"`
+	MaxBackoff   time.Duration `yaml:"max_backoff"   env:"WEBHOOKS_MAX_BACKOFF"   env-default:"1h"`
+	Timeout      time.Duration `yaml:"timeout"       env:"WEBHOOKS_TIMEOUT"       env-default:"10s"`
+}
+
+type Scheduler struct {
+	Enabled           bool          `yaml:"enabled"             env:"SCHEDULER_ENABLED"             env-default:"true"`
+	Interval          time.Duration `yaml:"interval"            env:"SCHEDULER_INTERVAL"            env-default:"1h"`
+	RemindDays        int           `yaml:"remind_days"         env:"SCHEDULER_REMIND_DAYS"         env-default:"30" validate:"gt=0"`
+	LongRunningMonths int           `yaml:"long_running_months" env:"SCHEDULER_LONG_RUNNING_MONTHS" env-default:"12" validate:"gt=0"`
+	Notifier          string        `yaml:"notifier"            env:"SCHEDULER_NOTIFIER"            env-default:"log" validate:"oneof=log webhook"`
+	LockKey           int64         `yaml:"lock_key"            env:"SCHEDULER_LOCK_KEY"            env-default:"270027"`
+}
+
+type Budgets struct {
+	WarnPercent int `yaml:"warn_percent" env:"BUDGETS_WARN_PERCENT" env-default:"90" validate:"gt=0,lte=100"`
+}
+
+type Metrics struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval" env:"METRICS_REFRESH_INTERVAL" env-default:"1m" validate:"gt=0"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"     env:"TRACING_EXPORTER"     env-default:"none" validate:"oneof=none stdout otlp"`
+	Endpoint    string  `yaml:"endpoint"     env:"TRACING_ENDPOINT"     env-default:"localhost:4317"`
+	Insecure    bool    `yaml:"insecure"     env:"TRACING_INSECURE"     env-default:"true"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1" validate:"gte=0,lte=1"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME" env-default:"subs-aggregator"`
+}
+
+type Health struct {
+	Timeout    time.Duration `yaml:"timeout"     env:"HEALTH_TIMEOUT"     env-default:"2s"`
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HEALTH_DRAIN_DELAY" env-default:"5s"`
+}
+
+type Cache struct {
+	Enabled bool          `yaml:"enabled" env:"CACHE_ENABLED" env-default:"false"`
+	Size    int           `yaml:"size"    env:"CACHE_SIZE"    env-default:"1024" validate:"gt=0"`
+	TTL     time.Duration `yaml:"ttl"     env:"CACHE_TTL"     env-default:"1m" validate:"gt=0"`
+}
+
+type Rollup struct {
+	Enabled       bool          `yaml:"enabled"        env:"ROLLUP_ENABLED"        env-default:"true"`
+	Interval      time.Duration `yaml:"interval"       env:"ROLLUP_INTERVAL"       env-default:"24h"`
+	HorizonMonths int           `yaml:"horizon_months" env:"ROLLUP_HORIZON_MONTHS" env-default:"24"     validate:"gte=0"`
+	LockKey       int64         `yaml:"lock_key"       env:"ROLLUP_LOCK_KEY"       env-default:"270028"`
+}
+
+type Lifecycle struct {
+	Enabled  bool          `yaml:"enabled"  env:"LIFECYCLE_ENABLED"  env-default:"true"`
+	Interval time.Duration `yaml:"interval" env:"LIFECYCLE_INTERVAL" env-default:"1h"`
+	LockKey  int64         `yaml:"lock_key" env:"LIFECYCLE_LOCK_KEY" env-default:"270030"`
+}
+
+type Migrations struct {
+	Auto    bool  `yaml:"auto"     env:"MIGRATIONS_AUTO"     env-default:"false"`
+	LockKey int64 `yaml:"lock_key" env:"MIGRATIONS_LOCK_KEY" env-default:"270029"`
+}
+
+type Dates struct {
+	Format       string `yaml:"format"        env:"DATES_FORMAT"        env-default:"month" validate:"oneof=month iso"`
+	DayPrecision bool   `yaml:"day_precision" env:"DATES_DAY_PRECISION" env-default:"false"`
 }
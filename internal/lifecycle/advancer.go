@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+// Advancer moves subscriptions along their lifecycle as months pass:
+// scheduled ones become active in their start month, paused ones follow
+// their pauses and ended ones expire.
+type Advancer struct {
+	Logger *slog.Logger
+	Config *config.Lifecycle
+	Store  postgres.StatusAPI
+	Now    func() time.Time
+}
+
+func NewAdvancer(logger *slog.Logger, cfg *config.Lifecycle, store postgres.StatusAPI) *Advancer {
+	return &Advancer{
+		Logger: logger,
+		Config: cfg,
+		Store:  store,
+		Now:    time.Now,
+	}
+}
+
+func (a *Advancer) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick advances statuses to the current month if this replica wins the
+// advisory lock.
+func (a *Advancer) Tick(ctx context.Context) {
+	release, leader, err := a.Store.TryLock(ctx, a.Config.LockKey)
+	if err != nil {
+		a.Logger.Error(
+			"lifecycle lock",
+			"error", err,
+		)
+		return
+	}
+
+	if !leader {
+		a.Logger.Debug("lifecycle lock held by another replica")
+		return
+	}
+	defer release()
+
+	month := models.MonthOf(a.Now())
+
+	moved, err := a.Store.Advance(ctx, month, "")
+	if err != nil {
+		a.Logger.Error(
+			"lifecycle advance",
+			"month", month.Time,
+			"error", err,
+		)
+		return
+	}
+
+	a.Logger.Debug("lifecycle advanced", "month", month.Time, "transitions", moved)
+}
@@ -0,0 +1,68 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	postgres.StatusAPI
+	leader   bool
+	released bool
+	advanced []models.MonthDate
+	err      error
+}
+
+func (f *fakeStore) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	return func() { f.released = true }, f.leader, nil
+}
+
+func (f *fakeStore) Advance(ctx context.Context, month models.MonthDate, reason string, ids ...uuid.UUID) (int, error) {
+	f.advanced = append(f.advanced, month)
+	return 1, f.err
+}
+
+func setup(store *fakeStore) *Advancer {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	advancer := NewAdvancer(logger, &config.Lifecycle{}, store)
+	advancer.Now = func() time.Time {
+		return time.Date(2024, time.November, 30, 23, 0, 0, 0, time.UTC)
+	}
+
+	return advancer
+}
+
+func TestTick(t *testing.T) {
+	store := &fakeStore{leader: true}
+	setup(store).Tick(context.Background())
+
+	want := models.MonthDate{Time: time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	assert.Equal(t, []models.MonthDate{want}, store.advanced)
+	assert.True(t, store.released)
+}
+
+func TestTick_NotLeader(t *testing.T) {
+	store := &fakeStore{}
+	setup(store).Tick(context.Background())
+
+	assert.Empty(t, store.advanced)
+}
+
+func TestTick_AdvanceError(t *testing.T) {
+	store := &fakeStore{leader: true, err: errors.New("boom")}
+	setup(store).Tick(context.Background())
+
+	assert.Len(t, store.advanced, 1)
+	assert.True(t, store.released)
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"errors"
+	"io"
 	"log/slog"
 	"os"
 
@@ -14,6 +15,13 @@ var (
 )
 
 func Setup(cfg *config.Logger) (*slog.Logger, error) {
+	return New(cfg, os.Stdout)
+}
+
+// New builds the logger described by cfg writing to w. Records carry the
+// ids of the current span, values of the cfg.Redact keys are hidden and
+// debug and info records are kept with probability cfg.Sampling.
+func New(cfg *config.Logger, w io.Writer) (*slog.Logger, error) {
 	levelMapper := map[string]slog.Level{
 		"debug": slog.LevelDebug,
 		"info":  slog.LevelInfo,
@@ -26,19 +34,28 @@ func Setup(cfg *config.Logger) (*slog.Logger, error) {
 		return nil, ErrUnknownLevel
 	}
 
-	ops := &slog.HandlerOptions{Level: level}
+	ops := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact(cfg.Redact),
+	}
 
 	var handler slog.Handler
 	switch cfg.Format {
 	case "json":
-		handler = slog.NewJSONHandler(os.Stdout, ops)
+		handler = slog.NewJSONHandler(w, ops)
 
 	case "text":
-		handler = slog.NewTextHandler(os.Stdout, ops)
+		handler = slog.NewTextHandler(w, ops)
 
 	default:
 		return nil, ErrUnknownFormat
 	}
 
+	handler = traceHandler{handler}
+
+	if cfg.Sampling > 0 && cfg.Sampling < 1 {
+		handler = sampleHandler{handler, cfg.Sampling}
+	}
+
 	return slog.New(handler), nil
 }
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultConfig() *config.Logger {
+	return &config.Logger{
+		Level:    "debug",
+		Format:   "text",
+		Sampling: 1,
+		Redact:   []string{"password", "token"},
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Level = "trace"
+	_, err := New(cfg, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnknownLevel)
+
+	cfg = defaultConfig()
+	cfg.Format = "xml"
+	_, err = New(cfg, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestNew_Redact(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(defaultConfig(), &buf)
+	require.NoError(t, err)
+
+	logger.WithGroup("webhook").Info("created", "url", "https://example.com", "Token", "s3cr3t")
+	logger.Info("login", "password", "hunter2")
+
+	out := buf.String()
+	assert.Contains(t, out, "webhook.url=https://example.com")
+	assert.Contains(t, out, "webhook.Token="+redacted)
+	assert.Contains(t, out, "password="+redacted)
+	assert.NotContains(t, out, "s3cr3t")
+	assert.NotContains(t, out, "hunter2")
+}
+
+func TestNew_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := defaultConfig()
+	cfg.Sampling = 1e-12
+
+	logger, err := New(cfg, &buf)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		logger.Debug("noise")
+		logger.Info("noise")
+	}
+	logger.Warn("kept")
+	logger.Error("kept")
+
+	assert.Equal(t, 2, strings.Count(buf.String(), "msg=kept"))
+	assert.NotContains(t, buf.String(), "noise")
+}
@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// redact returns a ReplaceAttr hook hiding the values of attributes whose
+// key matches one of keys, case-insensitively and inside any group.
+func redact(keys []string) func(groups []string, a slog.Attr) slog.Attr {
+	sensitive := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			sensitive[strings.ToLower(key)] = true
+		}
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if sensitive[strings.ToLower(a.Key)] {
+			return slog.String(a.Key, redacted)
+		}
+
+		return a
+	}
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+)
+
+// sampleHandler drops debug and info records with probability 1-rate.
+// Warnings and errors are always written.
+type sampleHandler struct {
+	slog.Handler
+	rate float64
+}
+
+func (h sampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn && rand.Float64() >= h.rate {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h sampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sampleHandler{h.Handler.WithAttrs(attrs), h.rate}
+}
+
+func (h sampleHandler) WithGroup(name string) slog.Handler {
+	return sampleHandler{h.Handler.WithGroup(name), h.rate}
+}
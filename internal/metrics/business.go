@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+// RunBusiness refreshes business gauges every interval until ctx is done,
+// so that scrapes never hit the database.
+func (m *Metrics) RunBusiness(ctx context.Context, logger *slog.Logger, store postgres.StatsAPI, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.RefreshBusiness(ctx, store); err != nil {
+			logger.Error(
+				"refresh business metrics",
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Metrics) RefreshBusiness(ctx context.Context, store postgres.StatsAPI) error {
+	stats, err := store.ServiceStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.ActiveSubs.Reset()
+	m.MonthlyRev.Reset()
+
+	for _, stat := range stats {
+		m.ActiveSubs.WithLabelValues(stat.ServiceName).Set(float64(stat.Active))
+		m.MonthlyRev.WithLabelValues(stat.ServiceName).Set(float64(stat.MonthlyRevenue))
+	}
+
+	return nil
+}
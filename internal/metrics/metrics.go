@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+	DBDuration   *prometheus.HistogramVec
+	ActiveSubs   *prometheus.GaugeVec
+	MonthlyRev   *prometheus.GaugeVec
+
+	CacheRequests      *prometheus.CounterVec
+	CacheInvalidations prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by route template, method and status.",
+		}, []string{"route", "method", "status"}),
+
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route template, method and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+
+		DBDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Storage call latency by SubsAPI method and outcome.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "outcome"}),
+
+		ActiveSubs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "subscriptions_active",
+			Help: "Subscriptions charged in the current month by service.",
+		}, []string{"service"}),
+
+		MonthlyRev: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "subscriptions_monthly_recurring_revenue",
+			Help: "Sum of prices charged in the current month by service.",
+		}, []string{"service"}),
+
+		CacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "summary_cache_requests_total",
+			Help: "Summary cache lookups by result (hit or miss).",
+		}, []string{"result"}),
+
+		CacheInvalidations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "summary_cache_invalidations_total",
+			Help: "Summary cache entries dropped because a subscription changed.",
+		}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.DBDuration,
+		m.ActiveSubs,
+		m.MonthlyRev,
+		m.CacheRequests,
+		m.CacheInvalidations,
+	)
+
+	return m
+}
+
+// RegisterDB exposes connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSubs struct {
+	postgres.SubsAPI
+}
+
+func (f *fakeSubs) Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	return nil, postgres.ErrNotFound
+}
+
+func (f *fakeSubs) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	return models.Undiscounted(42), nil
+}
+
+type fakeStats struct {
+	stats []models.ServiceStats
+}
+
+func (f *fakeStats) ServiceStats(ctx context.Context) ([]models.ServiceStats, error) {
+	return f.stats, nil
+}
+
+func TestInstrumentSubsAPI(t *testing.T) {
+	m := New()
+	db := m.InstrumentSubsAPI(&fakeSubs{})
+
+	sum, err := db.Summary(context.Background(), &models.SumRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 42, sum.Net)
+
+	_, err = db.Read(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.DBDuration))
+	assert.Equal(t, uint64(1), histogramCount(t, m, "Summary", "ok"))
+	assert.Equal(t, uint64(1), histogramCount(t, m, "Read", "not_found"))
+}
+
+func histogramCount(t *testing.T, m *Metrics, method, outcome string) uint64 {
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "db_query_duration_seconds" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			if labels["method"] == method && labels["outcome"] == outcome {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
+
+func TestRefreshBusiness(t *testing.T) {
+	m := New()
+	store := &fakeStats{stats: []models.ServiceStats{
+		{ServiceName: "Netflix", Active: 3, MonthlyRevenue: 3000},
+		{ServiceName: "Spotify", Active: 1, MonthlyRevenue: 300},
+	}}
+
+	require.NoError(t, m.RefreshBusiness(context.Background(), store))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.ActiveSubs.WithLabelValues("Netflix")))
+	assert.Equal(t, float64(300), testutil.ToFloat64(m.MonthlyRev.WithLabelValues("Spotify")))
+
+	store.stats = store.stats[:1]
+	require.NoError(t, m.RefreshBusiness(context.Background(), store))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.ActiveSubs))
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type instrumentedSubs struct {
+	postgres.SubsAPI
+	duration *prometheus.HistogramVec
+}
+
+// InstrumentSubsAPI wraps db so that every call is observed in
+// db_query_duration_seconds.
+func (m *Metrics) InstrumentSubsAPI(db postgres.SubsAPI) postgres.SubsAPI {
+	return &instrumentedSubs{
+		SubsAPI:  db,
+		duration: m.DBDuration,
+	}
+}
+
+func (i *instrumentedSubs) observe(method string, start time.Time, err error) {
+	outcome := "ok"
+	switch {
+	case errors.Is(err, postgres.ErrNotFound):
+		outcome = "not_found"
+	case err != nil:
+		outcome = "error"
+	}
+
+	i.duration.WithLabelValues(method, outcome).Observe(time.Since(start).Seconds())
+}
+
+func (i *instrumentedSubs) Create(ctx context.Context, sub *models.Subscription) error {
+	start := time.Now()
+	err := i.SubsAPI.Create(ctx, sub)
+	i.observe("Create", start, err)
+	return err
+}
+
+func (i *instrumentedSubs) Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	start := time.Now()
+	res, err := i.SubsAPI.Read(ctx, id)
+	i.observe("Read", start, err)
+	return res, err
+}
+
+func (i *instrumentedSubs) Update(ctx context.Context, sub *models.Subscription) error {
+	start := time.Now()
+	err := i.SubsAPI.Update(ctx, sub)
+	i.observe("Update", start, err)
+	return err
+}
+
+func (i *instrumentedSubs) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := i.SubsAPI.Delete(ctx, id)
+	i.observe("Delete", start, err)
+	return err
+}
+
+func (i *instrumentedSubs) List(ctx context.Context, userID uuid.UUID) ([]models.Subscription, error) {
+	start := time.Now()
+	res, err := i.SubsAPI.List(ctx, userID)
+	i.observe("List", start, err)
+	return res, err
+}
+
+func (i *instrumentedSubs) ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error) {
+	start := time.Now()
+	res, err := i.SubsAPI.ListUsers(ctx, userIDs)
+	i.observe("ListUsers", start, err)
+	return res, err
+}
+
+func (i *instrumentedSubs) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	start := time.Now()
+	res, err := i.SubsAPI.Summary(ctx, req)
+	i.observe("Summary", start, err)
+	return res, err
+}
@@ -0,0 +1,64 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+// Auto applies pending migrations at startup. Replicas starting together
+// queue on the advisory lock key, so exactly one applies the migrations
+// and the others find nothing left to do.
+func Auto(ctx context.Context, logger *slog.Logger, cfg *config.Postgres, key int64, fsys fs.FS) error {
+	db, err := postgres.Open(ctx, logger, cfg)
+	if err != nil {
+		return err
+	}
+
+	// The lock must be held before New, which creates schema_migrations.
+	release, err := postgres.AdvisoryLock(ctx, db, key)
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	migrator, err := New(db.DB, fsys)
+	if err != nil {
+		release()
+		db.Close()
+		return err
+	}
+
+	// Closing the migrator closes db, so unlock first.
+	defer func() {
+		release()
+		migrator.Close()
+	}()
+
+	before, _, err := migrator.Version()
+	if err != nil {
+		return fmt.Errorf("get migration version fail: %w", err)
+	}
+
+	if err := migrator.Up(); err != nil && !errors.Is(err, ErrNoChange) {
+		return fmt.Errorf("migrate up fail: %w", err)
+	}
+
+	after, _, err := migrator.Version()
+	if err != nil {
+		return fmt.Errorf("get migration version fail: %w", err)
+	}
+
+	logger.Info(
+		"migrations applied",
+		"from", before,
+		"to", after,
+	)
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var ErrName = errors.New("migration name must be lower case letters, digits and underscores")
+
+var namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create adds empty up and down files for the version after the latest one
+// in dir and returns their paths.
+func Create(dir, name string) (string, string, error) {
+	if !namePattern.MatchString(name) {
+		return "", "", ErrName
+	}
+
+	src, err := newSource(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	version := uint(1)
+	if n := len(src.versions); n > 0 {
+		version = src.versions[n-1] + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%03d_%s", version, name))
+	up, down := base+".up.sql", base+".down.sql"
+
+	for _, file := range []string{up, down} {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("create migration fail: %w", err)
+		}
+		f.Close()
+	}
+
+	return up, down, nil
+}
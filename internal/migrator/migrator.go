@@ -0,0 +1,211 @@
+// Package migrator applies the embedded SQL migrations with golang-migrate
+// and plans migrations without running them for dry runs.
+package migrator
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate"
+	"github.com/golang-migrate/migrate/database/postgres"
+	"github.com/golang-migrate/migrate/source"
+)
+
+var (
+	ErrNoChange = migrate.ErrNoChange
+	ErrDirty    = errors.New("database is dirty")
+	ErrVersion  = errors.New("unknown migration version")
+)
+
+// Step is one migration that moving to a target version would run.
+type Step struct {
+	Version    uint
+	Identifier string
+	Direction  source.Direction
+	SQL        string
+}
+
+// Status describes one migration of the source against the database.
+type Status struct {
+	Version    uint
+	Identifier string
+	Applied    bool
+	Dirty      bool
+}
+
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  *fsSource
+}
+
+// New migrates db with the migrations in the root of fsys. Close closes db.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	src, err := newSource(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("create migrate driver fail: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("embed", src, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("create migrate instance fail: %w", err)
+	}
+
+	return &Migrator{
+		migrate: m,
+		source:  src,
+	}, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.migrate.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// Latest returns the highest version of the source.
+func (m *Migrator) Latest() uint {
+	if len(m.source.versions) == 0 {
+		return 0
+	}
+	return m.source.versions[len(m.source.versions)-1]
+}
+
+// Version returns the applied version, 0 when nothing is applied.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+func (m *Migrator) Up() error {
+	return m.migrate.Up()
+}
+
+// Down rolls back the last applied migration.
+func (m *Migrator) Down() error {
+	return m.migrate.Steps(-1)
+}
+
+// Goto migrates up or down to version; 0 rolls back every migration.
+func (m *Migrator) Goto(version uint) error {
+	if version == 0 {
+		return m.migrate.Down()
+	}
+
+	if !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrVersion, version)
+	}
+
+	return m.migrate.Migrate(version)
+}
+
+func (m *Migrator) Force(version uint) error {
+	if !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrVersion, version)
+	}
+
+	return m.migrate.Force(int(version))
+}
+
+// Status lists every migration of the source and whether it is applied.
+func (m *Migrator) Status() ([]Status, error) {
+	current, dirty, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.source.versions))
+	for _, version := range m.source.versions {
+		statuses = append(statuses, Status{
+			Version:    version,
+			Identifier: m.source.identifier(version),
+			Applied:    version <= current,
+			Dirty:      dirty && version == current,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Plan returns the migrations Goto(target) would run, in order, without
+// touching the schema.
+func (m *Migrator) Plan(target uint) ([]Step, error) {
+	current, dirty, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	if dirty {
+		return nil, fmt.Errorf("%w at version %d", ErrDirty, current)
+	}
+
+	if target != 0 && !m.known(target) {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, target)
+	}
+
+	return m.source.plan(current, target)
+}
+
+// PlanDown returns the migration Down would roll back.
+func (m *Migrator) PlanDown() ([]Step, error) {
+	current, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	if current == 0 {
+		return []Step{}, nil
+	}
+
+	prev, err := m.source.Prev(current)
+	if err != nil {
+		prev = 0
+	}
+
+	return m.Plan(prev)
+}
+
+func (m *Migrator) known(version uint) bool {
+	_, ok := m.source.find(version, source.Up)
+	return ok
+}
+
+// plan lists the up migrations after current up to target, or the down
+// migrations from current down to just above target.
+func (s *fsSource) plan(current, target uint) ([]Step, error) {
+	steps := []Step{}
+
+	if target >= current {
+		for _, version := range s.versions {
+			if version > current && version <= target {
+				steps = append(steps, Step{Version: version, Direction: source.Up})
+			}
+		}
+	} else {
+		for i := len(s.versions) - 1; i >= 0; i-- {
+			if version := s.versions[i]; version <= current && version > target {
+				steps = append(steps, Step{Version: version, Direction: source.Down})
+			}
+		}
+	}
+
+	for i := range steps {
+		body, err := s.sql(steps[i].Version, steps[i].Direction)
+		if err != nil {
+			return nil, err
+		}
+
+		steps[i].Identifier = s.identifier(steps[i].Version)
+		steps[i].SQL = body
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,63 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/migrator"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	m, err := migrator.New(pgtest.EmptyDB(t).DB, migrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Close() })
+
+	plan, err := m.Plan(m.Latest())
+	require.NoError(t, err)
+	assert.Len(t, plan, int(postgres.SchemaVersion))
+
+	require.NoError(t, m.Up())
+	assertVersion(t, m, postgres.SchemaVersion)
+	assert.ErrorIs(t, m.Up(), migrator.ErrNoChange)
+
+	require.NoError(t, m.Down())
+	assertVersion(t, m, postgres.SchemaVersion-1)
+
+	require.NoError(t, m.Goto(0))
+	assertVersion(t, m, 0)
+
+	require.NoError(t, m.Goto(postgres.SchemaVersion))
+
+	statuses, err := m.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied, status.Identifier)
+	}
+}
+
+func TestMigrator_UnknownVersion(t *testing.T) {
+	m, err := migrator.New(pgtest.EmptyDB(t).DB, migrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Close() })
+
+	assert.ErrorIs(t, m.Goto(m.Latest()+1), migrator.ErrVersion)
+	_, err = m.Plan(m.Latest() + 1)
+	assert.ErrorIs(t, err, migrator.ErrVersion)
+}
+
+func assertVersion(t *testing.T, m *migrator.Migrator, want uint) {
+	t.Helper()
+
+	version, dirty, err := m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, want, version)
+	assert.False(t, dirty)
+}
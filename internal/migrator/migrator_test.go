@@ -0,0 +1,139 @@
+package migrator
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/migrations"
+	"github.com/golang-migrate/migrate/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"001_init.up.sql":     {Data: []byte("CREATE TABLE a ();")},
+		"001_init.down.sql":   {Data: []byte("DROP TABLE a;")},
+		"002_index.up.sql":    {Data: []byte("CREATE INDEX i ON a ();")},
+		"002_index.down.sql":  {Data: []byte("DROP INDEX i;")},
+		"010_column.up.sql":   {Data: []byte("ALTER TABLE a ADD c INT;")},
+		"010_column.down.sql": {Data: []byte("ALTER TABLE a DROP c;")},
+	}
+}
+
+func TestSource(t *testing.T) {
+	src, err := newSource(testFS())
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 10}, src.versions)
+
+	first, err := src.First()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), first)
+
+	next, err := src.Next(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint(10), next)
+
+	_, err = src.Next(10)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = src.Prev(1)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	r, identifier, err := src.ReadDown(2)
+	require.NoError(t, err)
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "index", identifier)
+	assert.Equal(t, "DROP INDEX i;", string(body))
+}
+
+func TestSource_InvalidName(t *testing.T) {
+	fsys := testFS()
+	fsys["init.sql"] = &fstest.MapFile{}
+
+	_, err := newSource(fsys)
+	assert.Error(t, err)
+}
+
+func TestSource_Embedded(t *testing.T) {
+	src, err := newSource(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, src.versions)
+
+	assert.Equal(t, postgres.SchemaVersion, src.versions[len(src.versions)-1],
+		"bump postgres.SchemaVersion together with new migrations")
+
+	for _, version := range src.versions {
+		_, err := src.sql(version, source.Up)
+		assert.NoError(t, err)
+		_, err = src.sql(version, source.Down)
+		assert.NoError(t, err)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	src, err := newSource(testFS())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		current uint
+		target  uint
+		want    []Step
+	}{
+		{name: "from scratch", current: 0, target: 2, want: []Step{
+			{Version: 1, Identifier: "init", Direction: source.Up, SQL: "CREATE TABLE a ();"},
+			{Version: 2, Identifier: "index", Direction: source.Up, SQL: "CREATE INDEX i ON a ();"},
+		}},
+		{name: "up", current: 2, target: 10, want: []Step{
+			{Version: 10, Identifier: "column", Direction: source.Up, SQL: "ALTER TABLE a ADD c INT;"},
+		}},
+		{name: "down", current: 10, target: 1, want: []Step{
+			{Version: 10, Identifier: "column", Direction: source.Down, SQL: "ALTER TABLE a DROP c;"},
+			{Version: 2, Identifier: "index", Direction: source.Down, SQL: "DROP INDEX i;"},
+		}},
+		{name: "all down", current: 2, target: 0, want: []Step{
+			{Version: 2, Identifier: "index", Direction: source.Down, SQL: "DROP INDEX i;"},
+			{Version: 1, Identifier: "init", Direction: source.Down, SQL: "DROP TABLE a;"},
+		}},
+		{name: "no change", current: 2, target: 2, want: []Step{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			steps, err := src.plan(test.current, test.target)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, steps)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range testFS() {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), file.Data, 0o644))
+	}
+
+	up, down, err := Create(dir, "add_users")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "011_add_users.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "011_add_users.down.sql"), down)
+	assert.FileExists(t, up)
+	assert.FileExists(t, down)
+
+	_, _, err = Create(dir, "Add Users")
+	assert.ErrorIs(t, err, ErrName)
+}
+
+func TestCreate_Empty(t *testing.T) {
+	up, _, err := Create(t.TempDir(), "init")
+	require.NoError(t, err)
+	assert.Equal(t, "001_init.up.sql", filepath.Base(up))
+}
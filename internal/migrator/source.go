@@ -0,0 +1,139 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/golang-migrate/migrate/source"
+)
+
+var errOpenURL = errors.New("fs source is created with newSource, not from a URL")
+
+// fsSource is a golang-migrate source over the files in the root of an
+// fs.FS, such as migrations.FS.
+type fsSource struct {
+	fsys       fs.FS
+	migrations *source.Migrations
+	versions   []uint
+}
+
+func newSource(fsys fs.FS) (*fsSource, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations fail: %w", err)
+	}
+
+	s := &fsSource{
+		fsys:       fsys,
+		migrations: source.NewMigrations(),
+	}
+
+	for _, file := range files {
+		m, err := source.Parse(path.Base(file))
+		if err != nil {
+			return nil, fmt.Errorf("parse migration %s fail: %w", file, err)
+		}
+
+		if !s.migrations.Append(m) {
+			return nil, fmt.Errorf("duplicate migration %s", file)
+		}
+
+		if n := len(s.versions); n == 0 || s.versions[n-1] != m.Version {
+			s.versions = append(s.versions, m.Version)
+		}
+	}
+
+	sort.Slice(s.versions, func(i, j int) bool { return s.versions[i] < s.versions[j] })
+
+	return s, nil
+}
+
+func (s *fsSource) Open(url string) (source.Driver, error) {
+	return nil, errOpenURL
+}
+
+func (s *fsSource) Close() error {
+	return nil
+}
+
+func (s *fsSource) First() (uint, error) {
+	if version, ok := s.migrations.First(); ok {
+		return version, nil
+	}
+	return 0, os.ErrNotExist
+}
+
+func (s *fsSource) Prev(version uint) (uint, error) {
+	if prev, ok := s.migrations.Prev(version); ok {
+		return prev, nil
+	}
+	return 0, os.ErrNotExist
+}
+
+func (s *fsSource) Next(version uint) (uint, error) {
+	if next, ok := s.migrations.Next(version); ok {
+		return next, nil
+	}
+	return 0, os.ErrNotExist
+}
+
+func (s *fsSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	return s.read(version, source.Up)
+}
+
+func (s *fsSource) ReadDown(version uint) (io.ReadCloser, string, error) {
+	return s.read(version, source.Down)
+}
+
+func (s *fsSource) read(version uint, direction source.Direction) (io.ReadCloser, string, error) {
+	m, ok := s.find(version, direction)
+	if !ok {
+		return nil, "", os.ErrNotExist
+	}
+
+	file, err := s.fsys.Open(m.Raw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return file, m.Identifier, nil
+}
+
+// sql returns the body of a migration.
+func (s *fsSource) sql(version uint, direction source.Direction) (string, error) {
+	m, ok := s.find(version, direction)
+	if !ok {
+		return "", fmt.Errorf("no %s migration for version %d", direction, version)
+	}
+
+	body, err := fs.ReadFile(s.fsys, m.Raw)
+	if err != nil {
+		return "", fmt.Errorf("read migration %s fail: %w", m.Raw, err)
+	}
+
+	return string(body), nil
+}
+
+func (s *fsSource) identifier(version uint) string {
+	if m, ok := s.find(version, source.Up); ok {
+		return m.Identifier
+	}
+
+	if m, ok := s.find(version, source.Down); ok {
+		return m.Identifier
+	}
+
+	return ""
+}
+
+func (s *fsSource) find(version uint, direction source.Direction) (*source.Migration, bool) {
+	if direction == source.Up {
+		return s.migrations.Up(version)
+	}
+	return s.migrations.Down(version)
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BudgetState string
+
+const (
+	BudgetOK       BudgetState = "ok"
+	BudgetWarning  BudgetState = "warning"
+	BudgetBreached BudgetState = "breached"
+)
+
+// Budget is a monthly spend limit of a user. With neither ServiceName nor
+// Category set it limits the overall spend.
+type Budget struct {
+	ID          uuid.UUID   `json:"id"                     db:"id"`
+	UserID      uuid.UUID   `json:"user_id"                db:"user_id"`
+	ServiceName string      `json:"service_name,omitempty" db:"service_name"`
+	Category    string      `json:"category,omitempty"     db:"category"`
+	Limit       int         `json:"limit"                  db:"monthly_limit"`
+	State       BudgetState `json:"state"                  db:"state"`
+	CreatedAt   time.Time   `json:"created_at"             db:"created_at"`
+}
+
+type BudgetStatus struct {
+	Budget  Budget      `json:"budget"`
+	Current int         `json:"current"`
+	Next    int         `json:"next"`
+	State   BudgetState `json:"state"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CancelReason is the code of the reason a subscription was cancelled,
+// free text aside, so that churn can be grouped by it.
+type CancelReason string
+
+const (
+	CancelTooExpensive    CancelReason = "too_expensive"
+	CancelNotUsing        CancelReason = "not_using"
+	CancelSwitched        CancelReason = "switched_service"
+	CancelMissingFeatures CancelReason = "missing_features"
+	CancelTechnicalIssues CancelReason = "technical_issues"
+	CancelOther           CancelReason = "other"
+)
+
+var CancelReasons = []CancelReason{
+	CancelTooExpensive,
+	CancelNotUsing,
+	CancelSwitched,
+	CancelMissingFeatures,
+	CancelTechnicalIssues,
+	CancelOther,
+}
+
+func (r CancelReason) Valid() bool {
+	for _, known := range CancelReasons {
+		if r == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Cancellation records that a subscription was cancelled and is charged
+// for the last time in EffectiveDate. The subscription fields, its start
+// month among them, are copied at cancellation, so that churn still
+// counts subscriptions deleted since.
+type Cancellation struct {
+	ID             uuid.UUID    `json:"id"              db:"id"`
+	SubscriptionID uuid.UUID    `json:"subscription_id" db:"subscription_id"`
+	UserID         uuid.UUID    `json:"user_id"         db:"user_id"`
+	ServiceName    string       `json:"service_name"    db:"service_name"`
+	Category       string       `json:"category"        db:"category"`
+	StartDate      MonthDate    `json:"start_date"      db:"start_date"`
+	EffectiveDate  MonthDate    `json:"effective_date"  db:"effective_date"`
+	ReasonCode     CancelReason `json:"reason_code"     db:"reason_code"`
+	Reason         string       `json:"reason"          db:"reason"`
+	CreatedAt      time.Time    `json:"created_at"      db:"created_at"`
+}
+
+// TransitionReason returns the reason recorded for the status transition
+// of c: its code, followed by its text when there is one.
+func (c *Cancellation) TransitionReason() string {
+	if c.Reason == "" {
+		return string(c.ReasonCode)
+	}
+	return string(c.ReasonCode) + ": " + c.Reason
+}
+
+// ChurnRequest selects the months and subscriptions a churn report covers.
+type ChurnRequest struct {
+	ServiceName string    `json:"service_name,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	StartDate   MonthDate `json:"start_date"`
+	EndDate     MonthDate `json:"end_date"`
+}
+
+// Churn is the churn of a service in a month: Cancelled of the Active
+// subscriptions charged in Month are charged for the last time in it.
+type Churn struct {
+	ServiceName string               `json:"service_name"`
+	Month       MonthDate            `json:"month"`
+	Active      int                  `json:"active"`
+	Cancelled   int                  `json:"cancelled"`
+	Rate        float64              `json:"rate"`
+	Reasons     map[CancelReason]int `json:"reasons"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelReason_Valid(t *testing.T) {
+	for _, code := range CancelReasons {
+		assert.True(t, code.Valid(), code)
+	}
+
+	assert.False(t, CancelReason("").Valid())
+	assert.False(t, CancelReason("bored").Valid())
+}
+
+func TestCancellation_TransitionReason(t *testing.T) {
+	c := Cancellation{ReasonCode: CancelTooExpensive}
+	assert.Equal(t, "too_expensive", c.TransitionReason())
+
+	c.Reason = "price went up"
+	assert.Equal(t, "too_expensive: price went up", c.TransitionReason())
+}
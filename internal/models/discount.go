@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DiscountKind string
+
+const (
+	DiscountPercent DiscountKind = "percent"
+	DiscountFixed   DiscountKind = "fixed"
+)
+
+func (k DiscountKind) Valid() bool {
+	return k == DiscountPercent || k == DiscountFixed
+}
+
+// Discount lowers the monthly charge of a subscription from StartDate to
+// EndDate: by Amount percent of the month's price or by Amount itself.
+type Discount struct {
+	ID             uuid.UUID    `json:"id"                 db:"id"`
+	SubscriptionID uuid.UUID    `json:"subscription_id"    db:"subscription_id"`
+	Kind           DiscountKind `json:"kind"               db:"kind"`
+	Amount         int          `json:"amount"             db:"amount"`
+	StartDate      MonthDate    `json:"start_date"         db:"start_date"`
+	EndDate        MonthDate    `json:"end_date,omitempty" db:"end_date"`
+	CreatedAt      time.Time    `json:"created_at"         db:"created_at"`
+}
+
+// Period returns the months the discount applies to.
+func (d *Discount) Period() MonthRange {
+	return MonthRange{From: d.StartDate.Month(), To: d.EndDate.Month()}
+}
+
+// Off returns how much the discount takes off a month charged price.
+// Percentages round half up; neither kind takes off more than price.
+func (d *Discount) Off(price int) int {
+	off := d.Amount
+	if d.Kind == DiscountPercent {
+		off = (price*d.Amount + 50) / 100
+	}
+
+	return min(off, price)
+}
+
+// Summary is what subscriptions are charged over some months: Gross before
+// discounts, Discount taken off it and Net left to pay.
+type Summary struct {
+	Gross    int `json:"gross"`
+	Discount int `json:"discount"`
+	Net      int `json:"net"`
+}
+
+// Add returns the sum of s and other.
+func (s Summary) Add(other Summary) Summary {
+	return Summary{
+		Gross:    s.Gross + other.Gross,
+		Discount: s.Discount + other.Discount,
+		Net:      s.Net + other.Net,
+	}
+}
+
+// Undiscounted returns the summary of a gross amount without discounts.
+func Undiscounted(gross int) Summary {
+	return Summary{Gross: gross, Net: gross}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pgregory.net/rapid"
+)
+
+func TestDiscount_Off(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		price    int
+		want     int
+	}{
+		{"percent", Discount{Kind: DiscountPercent, Amount: 20}, 1000, 200},
+		{"percent rounds half up", Discount{Kind: DiscountPercent, Amount: 50}, 15, 8},
+		{"percent of free month", Discount{Kind: DiscountPercent, Amount: 50}, 0, 0},
+		{"fixed", Discount{Kind: DiscountFixed, Amount: 300}, 1000, 300},
+		{"fixed over price", Discount{Kind: DiscountFixed, Amount: 1500}, 1000, 1000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.discount.Off(test.price))
+		})
+	}
+}
+
+func TestSubscription_DiscountAt(t *testing.T) {
+	sub := Subscription{Price: 1000, StartDate: month(0), TrialMonths: 1}
+	discounts := []Discount{
+		{Kind: DiscountPercent, Amount: 50, StartDate: month(0), EndDate: month(3)},
+		{Kind: DiscountFixed, Amount: 300, StartDate: month(2)},
+		{Kind: DiscountFixed, Amount: 400, StartDate: month(3), EndDate: month(3)},
+	}
+
+	want := []int{0, 500, 800, 1000, 300}
+	for i, off := range want {
+		assert.Equal(t, off, sub.DiscountAt(month(i), discounts), "month %d", i)
+	}
+}
+
+func TestSubscription_Charge(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		sub := Subscription{
+			Price:       rapid.IntRange(0, 1000).Draw(t, "price"),
+			StartDate:   genMonth().Draw(t, "start"),
+			TrialMonths: rapid.IntRange(0, 3).Draw(t, "trial"),
+		}
+		if rapid.Bool().Draw(t, "ended") {
+			sub.EndDate = genMonth().Draw(t, "end")
+		}
+
+		discounts := rapid.SliceOfN(rapid.Custom(func(t *rapid.T) Discount {
+			r := genRange().Draw(t, "period")
+			return Discount{
+				Kind:      rapid.SampledFrom([]DiscountKind{DiscountPercent, DiscountFixed}).Draw(t, "kind"),
+				Amount:    rapid.IntRange(1, 100).Draw(t, "amount"),
+				StartDate: r.From,
+				EndDate:   r.To,
+			}
+		}), 0, 3).Draw(t, "discounts")
+
+		req := SumRequest{
+			StartDate: genMonth().Draw(t, "from"),
+			EndDate:   genMonth().Draw(t, "to"),
+			Prorate:   rapid.Bool().Draw(t, "prorate"),
+		}
+
+		sum := sub.Charge(&req, discounts, nil)
+
+		assert.Equal(t, sub.Charge(&req, nil, nil).Gross, sum.Gross, "discounts leave gross alone")
+		assert.Equal(t, sum.Gross-sum.Discount, sum.Net)
+		assert.GreaterOrEqual(t, sum.Discount, 0)
+		assert.LessOrEqual(t, sum.Discount, sum.Gross)
+
+		if !req.Prorate {
+			assert.Equal(t, sub.Cost(req.StartDate, req.EndDate), sum.Gross)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Member shares the cost of a subscription with its other members in
+// proportion to Weight. The owner of a shared subscription is one of its
+// members.
+type Member struct {
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"         db:"user_id"`
+	Weight         int       `json:"weight"          db:"weight"`
+	CreatedAt      time.Time `json:"created_at"      db:"created_at"`
+}
+
+// Share returns the part of amount user pays for a subscription of owner
+// shared by members. Every member pays amount split by weight, rounded
+// down, and the owner also pays the remainder, so the parts always add up
+// to amount. Without members the owner pays it all.
+func Share(amount int, members []Member, owner, user uuid.UUID) int {
+	weights := 0
+	for _, m := range members {
+		weights += m.Weight
+	}
+
+	if weights == 0 {
+		if user == owner {
+			return amount
+		}
+		return 0
+	}
+
+	part := func(weight int) int {
+		return amount * weight / weights
+	}
+
+	if user != owner {
+		for _, m := range members {
+			if m.UserID == user {
+				return part(m.Weight)
+			}
+		}
+		return 0
+	}
+
+	rest := amount
+	for _, m := range members {
+		if m.UserID != owner {
+			rest -= part(m.Weight)
+		}
+	}
+
+	return rest
+}
+
+// Share returns the part of s user pays for a subscription of owner shared
+// by members. Gross and Discount are split separately, see Share.
+func (s Summary) Share(members []Member, owner, user uuid.UUID) Summary {
+	gross, discount := Share(s.Gross, members, owner, user), Share(s.Discount, members, owner, user)
+	return Summary{Gross: gross, Discount: discount, Net: gross - discount}
+}
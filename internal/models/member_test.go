@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"pgregory.net/rapid"
+)
+
+func TestShare(t *testing.T) {
+	owner, member, other := uuid.New(), uuid.New(), uuid.New()
+	members := []Member{
+		{UserID: owner, Weight: 1},
+		{UserID: member, Weight: 2},
+	}
+
+	tests := []struct {
+		name    string
+		members []Member
+		user    uuid.UUID
+		want    int
+	}{
+		{"unshared owner", nil, owner, 1000},
+		{"unshared other", nil, other, 0},
+		{"member rounds down", members, member, 666},
+		{"owner takes remainder", members, owner, 334},
+		{"not a member", members, other, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, Share(1000, test.members, owner, test.user))
+		})
+	}
+}
+
+func TestShare_AddsUp(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		owner := uuid.New()
+		members := []Member{{UserID: owner, Weight: rapid.IntRange(1, 5).Draw(t, "owner")}}
+		for _, w := range rapid.SliceOfN(rapid.IntRange(1, 5), 0, 5).Draw(t, "weights") {
+			members = append(members, Member{UserID: uuid.New(), Weight: w})
+		}
+
+		sum := Summary{Gross: rapid.IntRange(0, 10000).Draw(t, "gross")}
+		sum.Discount = rapid.IntRange(0, sum.Gross).Draw(t, "discount")
+		sum.Net = sum.Gross - sum.Discount
+
+		var total Summary
+		for _, m := range members {
+			part := sum.Share(members, owner, m.UserID)
+			assert.Equal(t, part.Gross-part.Discount, part.Net)
+			assert.GreaterOrEqual(t, part.Gross, 0)
+			total = total.Add(part)
+		}
+
+		assert.Equal(t, sum, total)
+	})
+}
@@ -4,14 +4,45 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// MonthDate is a date of month precision: the first day of its month.
+// With day precision enabled by ConfigureDates it keeps the parsed day.
 type MonthDate struct {
 	Time  time.Time
 	Valid bool
 }
 
-const layout = "01-2006"
+// DateFormat is the format dates are written in.
+type DateFormat string
+
+const (
+	FormatMonth DateFormat = "month" // MM-YYYY
+	FormatISO   DateFormat = "iso"   // YYYY-MM-DD
+)
+
+const (
+	layout    = "01-2006"
+	isoLayout = "2006-01-02"
+)
+
+// inputLayouts are tried in order by ParseMonthDate.
+var inputLayouts = []string{layout, "2006-01", isoLayout, time.RFC3339}
+
+var (
+	outputFormat = FormatMonth
+	dayPrecision = false
+)
+
+// ConfigureDates sets the format dates are written in and whether parsed
+// dates keep their day instead of being truncated to the month. It is
+// meant to be called once at startup, before any date is parsed.
+func ConfigureDates(format DateFormat, days bool) {
+	outputFormat = format
+	dayPrecision = days
+}
 
 func (m *MonthDate) UnmarshalJSON(b []byte) error {
 	s := string(b)
@@ -22,15 +53,12 @@ func (m *MonthDate) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
-	s = s[1 : len(s)-1]
-
-	t, err := time.Parse(layout, s)
+	parsed, err := ParseMonthDate(s[1 : len(s)-1])
 	if err != nil {
-		return fmt.Errorf("invalid date format (expected MM-YYYY): %w", err)
+		return err
 	}
 
-	m.Time = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
-	m.Valid = true
+	*m = parsed
 	return nil
 }
 
@@ -38,7 +66,60 @@ func (m MonthDate) MarshalJSON() ([]byte, error) {
 	if !m.Valid {
 		return []byte("null"), nil
 	}
-	return []byte(fmt.Sprintf("\"%02d-%04d\"", m.Time.Month(), m.Time.Year())), nil
+	return []byte(fmt.Sprintf("\"%s\"", m)), nil
+}
+
+// ParseMonthDate parses MM-YYYY, YYYY-MM, YYYY-MM-DD or an RFC 3339
+// timestamp, whose date is taken in its own offset. An empty string is an
+// unset date.
+func ParseMonthDate(s string) (MonthDate, error) {
+	if s == "" {
+		return MonthDate{}, nil
+	}
+
+	for _, l := range inputLayouts {
+		t, err := time.Parse(l, s)
+		if err != nil {
+			continue
+		}
+
+		y, m, d := t.Date()
+		if !dayPrecision {
+			d = 1
+		}
+
+		return MonthDate{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Valid: true}, nil
+	}
+
+	return MonthDate{}, fmt.Errorf("invalid date format %q (expected MM-YYYY, YYYY-MM, YYYY-MM-DD or RFC 3339)", s)
+}
+
+// String formats the date in the configured format, or returns "" when
+// it is unset.
+func (m MonthDate) String() string {
+	if !m.Valid {
+		return ""
+	}
+
+	if outputFormat == FormatISO {
+		return m.Time.Format(isoLayout)
+	}
+
+	return fmt.Sprintf("%02d-%04d", m.Time.Month(), m.Time.Year())
+}
+
+// Month returns the first day of the month of m.
+func (m MonthDate) Month() MonthDate {
+	if !m.Valid {
+		return m
+	}
+
+	return MonthDate{Time: time.Date(m.Time.Year(), m.Time.Month(), 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+// MonthOf returns the first day of the month of t in UTC.
+func MonthOf(t time.Time) MonthDate {
+	return MonthDate{Time: t.UTC(), Valid: true}.Month()
 }
 
 func (m *MonthDate) Scan(v any) error {
@@ -53,7 +134,7 @@ func (m *MonthDate) Scan(v any) error {
 		return fmt.Errorf("MonthDate.Scan: invalid DB value %T", v)
 	}
 
-	m.Time = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	m.Time = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 	m.Valid = true
 	return nil
 }
@@ -79,3 +160,23 @@ func (m MonthDate) MonthsBetween(other MonthDate) int {
 
 	return (y2-y1)*12 + int(m2-m1) + 1
 }
+
+// ScanDate lets pgx scan DATE columns without the database/sql glue.
+func (m *MonthDate) ScanDate(v pgtype.Date) error {
+	if !v.Valid {
+		*m = MonthDate{}
+		return nil
+	}
+
+	if v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("MonthDate.ScanDate: infinite date")
+	}
+
+	m.Time = time.Date(v.Time.Year(), v.Time.Month(), v.Time.Day(), 0, 0, 0, 0, time.UTC)
+	m.Valid = true
+	return nil
+}
+
+func (m MonthDate) DateValue() (pgtype.Date, error) {
+	return pgtype.Date{Time: m.Time, Valid: m.Valid}, nil
+}
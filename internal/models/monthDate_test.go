@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func configureDates(t *testing.T, format DateFormat, days bool) {
+	t.Helper()
+
+	ConfigureDates(format, days)
+	t.Cleanup(func() { ConfigureDates(FormatMonth, false) })
+}
+
+func TestParseMonthDate(t *testing.T) {
+	tests := []struct {
+		in    string
+		month time.Time
+		day   time.Time
+	}{
+		{"03-2024", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-15", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-15T23:30:00-05:00", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-31T22:00:00.5Z", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			m, err := ParseMonthDate(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, MonthDate{Time: tt.month, Valid: true}, m)
+
+			configureDates(t, FormatMonth, true)
+
+			m, err = ParseMonthDate(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, MonthDate{Time: tt.day, Valid: true}, m)
+		})
+	}
+
+	for _, in := range []string{"2024", "13-2024", "2024-02-30", "15.03.2024", "2024-03-15 10:00"} {
+		_, err := ParseMonthDate(in)
+		assert.Error(t, err, in)
+	}
+
+	m, err := ParseMonthDate("")
+	require.NoError(t, err)
+	assert.False(t, m.Valid)
+}
+
+func TestMonthDate_JSON(t *testing.T) {
+	configureDates(t, FormatMonth, true)
+
+	var m MonthDate
+	require.NoError(t, json.Unmarshal([]byte(`"2024-03-15"`), &m))
+
+	out, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, `"03-2024"`, string(out))
+
+	configureDates(t, FormatISO, true)
+
+	out, err = json.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, `"2024-03-15"`, string(out))
+
+	out, err = json.Marshal(MonthDate{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(out))
+}
+
+func TestSubscription_ProratedCostExamples(t *testing.T) {
+	day := func(m time.Month, d int) MonthDate {
+		return MonthDate{Time: time.Date(2024, m, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	tests := []struct {
+		name string
+		sub  Subscription
+		want int
+	}{
+		{"whole months", Subscription{Price: 3100, StartDate: day(time.January, 1)}, 3 * 3100},
+		{"starts mid month", Subscription{Price: 2900, StartDate: day(time.February, 15)}, 1500 + 2900},
+		{"ends mid month", Subscription{Price: 3100, StartDate: day(time.January, 1), EndDate: day(time.March, 16)}, 2*3100 + 1600},
+		{"end on the first covers the month", Subscription{Price: 3100, StartDate: day(time.January, 1), EndDate: day(time.March, 1)}, 3 * 3100},
+		{"single day", Subscription{Price: 3100, StartDate: day(time.January, 10), EndDate: day(time.January, 10)}, 100},
+		{"rounds half up", Subscription{Price: 62, StartDate: day(time.January, 1), EndDate: day(time.January, 16)}, 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.sub.ProratedCost(day(time.January, 1), day(time.March, 1))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
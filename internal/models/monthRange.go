@@ -0,0 +1,163 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MonthRange is the closed range of months From..To, both first days of
+// their months. An unset To leaves the range open ended. The zero value is
+// the empty range.
+//
+// In PostgreSQL it maps to the half open daterange [From, To + 1 month),
+// the form of the subscriptions.period column.
+type MonthRange struct {
+	From MonthDate
+	To   MonthDate
+}
+
+// Empty reports whether r holds no months.
+func (r MonthRange) Empty() bool {
+	return !r.From.Valid || r.To.Valid && r.To.Time.Before(r.From.Time)
+}
+
+// Contains reports whether the month of m is in r.
+func (r MonthRange) Contains(m MonthDate) bool {
+	if r.Empty() || !m.Valid {
+		return false
+	}
+
+	m = m.Month()
+
+	return !m.Time.Before(r.From.Time) && (!r.To.Valid || !m.Time.After(r.To.Time))
+}
+
+// Intersect returns the months that are in both r and other.
+func (r MonthRange) Intersect(other MonthRange) MonthRange {
+	if r.Empty() || other.Empty() {
+		return MonthRange{}
+	}
+
+	if other.From.Time.After(r.From.Time) {
+		r.From = other.From
+	}
+
+	if other.To.Valid && (!r.To.Valid || other.To.Time.Before(r.To.Time)) {
+		r.To = other.To
+	}
+
+	if r.Empty() {
+		return MonthRange{}
+	}
+
+	return r
+}
+
+// Overlaps reports whether r and other share at least one month.
+func (r MonthRange) Overlaps(other MonthRange) bool {
+	return !r.Intersect(other).Empty()
+}
+
+// Months returns the number of months in r: 0 when it is empty and -1
+// when it is open ended.
+func (r MonthRange) Months() int {
+	switch {
+	case r.Empty():
+		return 0
+	case !r.To.Valid:
+		return -1
+	}
+
+	return r.From.MonthsBetween(r.To)
+}
+
+// String formats r as a PostgreSQL daterange literal.
+func (r MonthRange) String() string {
+	if r.Empty() {
+		return "empty"
+	}
+
+	upper := ""
+	if r.To.Valid {
+		upper = nextMonth(r.To).Time.Format("2006-01-02")
+	}
+
+	return fmt.Sprintf("[%s,%s)", r.From.Time.Format("2006-01-02"), upper)
+}
+
+func (r MonthRange) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// IsNull, BoundTypes and Bounds let pgx encode r as a daterange.
+func (r MonthRange) IsNull() bool {
+	return false
+}
+
+func (r MonthRange) BoundTypes() (lower, upper pgtype.BoundType) {
+	switch {
+	case r.Empty():
+		return pgtype.Empty, pgtype.Empty
+	case !r.To.Valid:
+		return pgtype.Inclusive, pgtype.Unbounded
+	}
+
+	return pgtype.Inclusive, pgtype.Exclusive
+}
+
+func (r MonthRange) Bounds() (lower, upper any) {
+	return r.From, nextMonth(r.To)
+}
+
+// ScanNull, ScanBounds and SetBoundTypes let pgx scan a daterange into r.
+// Bounds are truncated to months, so only ranges over whole months, such
+// as subscriptions.period, round trip.
+func (r *MonthRange) ScanNull() error {
+	*r = MonthRange{}
+	return nil
+}
+
+func (r *MonthRange) ScanBounds() (lowerTarget, upperTarget any) {
+	return &r.From, &r.To
+}
+
+func (r *MonthRange) SetBoundTypes(lower, upper pgtype.BoundType) error {
+	if lower == pgtype.Empty {
+		*r = MonthRange{}
+		return nil
+	}
+
+	if lower != pgtype.Inclusive {
+		return fmt.Errorf("MonthRange.SetBoundTypes: unsupported lower bound %q", lower)
+	}
+
+	switch upper {
+	case pgtype.Unbounded:
+		r.To = MonthDate{}
+	case pgtype.Exclusive:
+		r.To = prevMonth(r.To)
+	case pgtype.Inclusive:
+	default:
+		return fmt.Errorf("MonthRange.SetBoundTypes: unsupported upper bound %q", upper)
+	}
+
+	return nil
+}
+
+func nextMonth(m MonthDate) MonthDate {
+	if !m.Valid {
+		return m
+	}
+
+	return MonthDate{Time: m.Time.AddDate(0, 1, 0), Valid: true}
+}
+
+func prevMonth(m MonthDate) MonthDate {
+	if !m.Valid {
+		return m
+	}
+
+	return MonthDate{Time: m.Time.AddDate(0, -1, 0), Valid: true}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pgregory.net/rapid"
+)
+
+// span is the number of months the generators draw from, starting at
+// January 2000. It is small enough to enumerate every month in checks.
+const span = 120
+
+func month(i int) MonthDate {
+	return MonthDate{Time: time.Date(2000, time.January+time.Month(i), 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func genMonth() *rapid.Generator[MonthDate] {
+	return rapid.Custom(func(t *rapid.T) MonthDate {
+		return month(rapid.IntRange(0, span-1).Draw(t, "month"))
+	})
+}
+
+// genRange draws closed, open ended, inverted and zero ranges.
+func genRange() *rapid.Generator[MonthRange] {
+	return rapid.Custom(func(t *rapid.T) MonthRange {
+		switch rapid.IntRange(0, 5).Draw(t, "kind") {
+		case 0:
+			return MonthRange{}
+		case 1:
+			return MonthRange{From: genMonth().Draw(t, "from")}
+		default:
+			return MonthRange{From: genMonth().Draw(t, "from"), To: genMonth().Draw(t, "to")}
+		}
+	})
+}
+
+// members lists the months of r within the generated span.
+func members(r MonthRange) []MonthDate {
+	var out []MonthDate
+	for i := 0; i < span; i++ {
+		if r.Contains(month(i)) {
+			out = append(out, month(i))
+		}
+	}
+	return out
+}
+
+func TestMonthRange_Intersect(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		a, b := genRange().Draw(t, "a"), genRange().Draw(t, "b")
+		m := genMonth().Draw(t, "m")
+
+		both := a.Intersect(b)
+		assert.Equal(t, both, b.Intersect(a), "commutative")
+		assert.Equal(t, a.Contains(m) && b.Contains(m), both.Contains(m))
+		assert.Equal(t, both, both.Intersect(a), "idempotent")
+		assert.Equal(t, !both.Empty(), a.Overlaps(b))
+
+		if both.Empty() {
+			assert.Equal(t, MonthRange{}, both, "empty intersections are the zero range")
+		}
+	})
+}
+
+func TestMonthRange_Months(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		r := genRange().Draw(t, "r")
+
+		switch {
+		case r.Empty():
+			assert.Equal(t, 0, r.Months())
+			assert.Empty(t, members(r))
+		case !r.To.Valid:
+			assert.Equal(t, -1, r.Months())
+		default:
+			assert.Equal(t, len(members(r)), r.Months())
+		}
+	})
+}
+
+func TestMonthRange_Contains(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		r := genRange().Draw(t, "r")
+		m := genMonth().Draw(t, "m")
+
+		assert.Equal(t, r.Contains(m), r.Overlaps(MonthRange{From: m, To: m}))
+		assert.False(t, r.Contains(MonthDate{}))
+
+		if !r.Empty() {
+			assert.True(t, r.Contains(r.From))
+		}
+	})
+}
+
+func TestSubscription_Cost(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		sub := Subscription{
+			Price:     rapid.IntRange(0, 1000).Draw(t, "price"),
+			StartDate: genMonth().Draw(t, "start"),
+		}
+		if rapid.Bool().Draw(t, "ended") {
+			sub.EndDate = genMonth().Draw(t, "end")
+		}
+
+		from, to := genMonth().Draw(t, "from"), genMonth().Draw(t, "to")
+
+		charged := 0
+		for _, m := range members(MonthRange{From: from, To: to}) {
+			if sub.Period().Contains(m) {
+				charged++
+			}
+		}
+
+		assert.Equal(t, sub.Price*charged, sub.Cost(from, to))
+	})
+}
+
+func TestSubscription_ProratedCost(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		day := func(label string) MonthDate {
+			m := genMonth().Draw(t, label)
+			m.Time = m.Time.AddDate(0, 0, rapid.IntRange(0, 27).Draw(t, label+" day"))
+			return m
+		}
+
+		sub := Subscription{
+			Price:     rapid.IntRange(0, 1000).Draw(t, "price"),
+			StartDate: day("start"),
+		}
+		if rapid.Bool().Draw(t, "ended") {
+			sub.EndDate = day("end")
+		}
+
+		from, to := genMonth().Draw(t, "from"), genMonth().Draw(t, "to")
+		prorated, whole := sub.ProratedCost(from, to), sub.Cost(from, to)
+
+		assert.LessOrEqual(t, prorated, whole)
+
+		months := Subscription{Price: sub.Price, StartDate: sub.StartDate.Month(), EndDate: sub.EndDate.Month()}
+		assert.Equal(t, months.Cost(from, to), months.ProratedCost(from, to), "month precision is never prorated")
+	})
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pause stops the charges of a subscription from StartDate to EndDate,
+// both months included. A pause without EndDate lasts until the
+// subscription is resumed.
+type Pause struct {
+	ID             uuid.UUID `json:"id"                 db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id"    db:"subscription_id"`
+	StartDate      MonthDate `json:"start_date"         db:"start_date"`
+	EndDate        MonthDate `json:"end_date,omitempty" db:"end_date"`
+	CreatedAt      time.Time `json:"created_at"         db:"created_at"`
+}
+
+// Period returns the paused months.
+func (p *Pause) Period() MonthRange {
+	return MonthRange{From: p.StartDate.Month(), To: p.EndDate.Month()}
+}
+
+// Resume ends the pause right before month m, the first month charged
+// again.
+func (p *Pause) Resume(m MonthDate) {
+	p.EndDate = prevMonth(m.Month())
+}
+
+// PauseAt returns the pause covering month m, if any.
+func PauseAt(m MonthDate, pauses []Pause) *Pause {
+	for i := range pauses {
+		if pauses[i].Period().Contains(m) {
+			return &pauses[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+)
+
+func TestPause_Resume(t *testing.T) {
+	pauses := []Pause{
+		{StartDate: month(2), EndDate: month(3)},
+		{StartDate: month(6)},
+	}
+
+	assert.Nil(t, PauseAt(month(4), pauses))
+	require.Same(t, &pauses[1], PauseAt(month(9), pauses))
+
+	PauseAt(month(9), pauses).Resume(month(9))
+	assert.Equal(t, month(8), pauses[1].EndDate)
+	assert.Nil(t, PauseAt(month(9), pauses), "the resume month is charged")
+	assert.NotNil(t, PauseAt(month(8), pauses))
+}
+
+func TestSubscription_ChargePaused(t *testing.T) {
+	sub := Subscription{Price: 1000, StartDate: month(0), TrialMonths: 1}
+	discounts := []Discount{{Kind: DiscountPercent, Amount: 50, StartDate: month(0)}}
+	pauses := []Pause{
+		{StartDate: month(2), EndDate: month(3)},
+		{StartDate: month(6)},
+	}
+
+	// Charged months 1, 4 and 5 at half price.
+	want := Summary{Gross: 3000, Discount: 1500, Net: 1500}
+	assert.Equal(t, want, sub.Charge(&SumRequest{StartDate: month(0), EndDate: month(11)}, discounts, pauses))
+}
+
+func TestSubscription_ChargePausedProperty(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		sub := Subscription{
+			Price:     rapid.IntRange(0, 1000).Draw(t, "price"),
+			StartDate: genMonth().Draw(t, "start"),
+		}
+
+		r := genRange().Draw(t, "pause")
+		pauses := []Pause{{StartDate: r.From, EndDate: r.To}}
+
+		req := SumRequest{
+			StartDate: genMonth().Draw(t, "from"),
+			EndDate:   genMonth().Draw(t, "to"),
+			Prorate:   rapid.Bool().Draw(t, "prorate"),
+		}
+
+		paused := 0
+		months := sub.overlap(req.StartDate, req.EndDate).Intersect(r)
+		for m := months.From; !months.Empty() && !m.Time.After(months.To.Time); m = nextMonth(m) {
+			paused += sub.Charge(&SumRequest{StartDate: m, EndDate: m, Prorate: req.Prorate}, nil, nil).Gross
+		}
+
+		assert.Equal(t, sub.Charge(&req, nil, nil).Gross-paused, sub.Charge(&req, nil, pauses).Gross)
+	})
+}
@@ -0,0 +1,25 @@
+package models
+
+type ReminderKind string
+
+const (
+	ReminderEndingSoon  ReminderKind = "ending_soon"
+	ReminderLongRunning ReminderKind = "long_running"
+	ReminderEnded       ReminderKind = "ended"
+)
+
+func (k ReminderKind) Event() EventType {
+	switch k {
+	case ReminderEndingSoon:
+		return EventSubEndingSoon
+	case ReminderLongRunning:
+		return EventSubLongRunning
+	default:
+		return EventSubEnded
+	}
+}
+
+type Reminder struct {
+	Kind         ReminderKind `json:"kind"`
+	Subscription Subscription `json:"subscription"`
+}
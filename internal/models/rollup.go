@@ -0,0 +1,16 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// RollupDiff is a spend_rollup row that disagrees with the total computed
+// from raw subscriptions.
+type RollupDiff struct {
+	Month       MonthDate `json:"month"        db:"month"`
+	UserID      uuid.UUID `json:"user_id"      db:"user_id"`
+	ServiceName string    `json:"service_name" db:"service_name"`
+	Category    string    `json:"category"     db:"category"`
+	Want        int       `json:"want"         db:"want"`
+	Got         int       `json:"got"          db:"got"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type ServiceStats struct {
+	ServiceName    string `json:"service_name"    db:"service_name"`
+	Active         int    `json:"active"          db:"active"`
+	MonthlyRevenue int    `json:"monthly_revenue" db:"monthly_revenue"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is where a subscription is in its lifecycle: scheduled until its
+// start month, then active, and from there paused, cancelled or expired.
+// Cancelled and expired are final.
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	StatusExpired   Status = "expired"
+)
+
+var Statuses = []Status{
+	StatusScheduled,
+	StatusActive,
+	StatusPaused,
+	StatusCancelled,
+	StatusExpired,
+}
+
+// transitions lists the statuses a subscription in each status can move
+// to.
+var transitions = map[Status][]Status{
+	StatusScheduled: {StatusActive},
+	StatusActive:    {StatusPaused, StatusCancelled, StatusExpired},
+	StatusPaused:    {StatusActive, StatusCancelled, StatusExpired},
+}
+
+func (s Status) Valid() bool {
+	for _, known := range Statuses {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CanMoveTo reports whether a subscription in status s can move to next.
+func (s Status) CanMoveTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if next == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Final reports whether a subscription in status s never moves again.
+func (s Status) Final() bool {
+	return s.Valid() && len(transitions[s]) == 0
+}
+
+// Transition records a status change of a subscription and its reason.
+type Transition struct {
+	ID             uuid.UUID `json:"id"              db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	From           Status    `json:"from"            db:"from_status"`
+	To             Status    `json:"to"              db:"to_status"`
+	Reason         string    `json:"reason"          db:"reason"`
+	CreatedAt      time.Time `json:"created_at"      db:"created_at"`
+}
+
+// NextStatus returns the status s moves to in month m, one step along the
+// lifecycle: scheduled subscriptions start in their start month, active
+// and paused ones expire after their end month and follow pauses, those
+// of s, in between. It returns s.Status when nothing changes.
+func (s *Subscription) NextStatus(m MonthDate, pauses []Pause) Status {
+	m = m.Month()
+	period := s.Period()
+	ended := period.To.Valid && m.Time.After(period.To.Time)
+	paused := PauseAt(m, pauses) != nil
+
+	switch s.Status {
+	case StatusScheduled:
+		if !m.Time.Before(period.From.Time) {
+			return StatusActive
+		}
+	case StatusActive:
+		switch {
+		case ended:
+			return StatusExpired
+		case paused:
+			return StatusPaused
+		}
+	case StatusPaused:
+		switch {
+		case ended:
+			return StatusExpired
+		case !paused:
+			return StatusActive
+		}
+	}
+
+	return s.Status
+}
+
+// Restatus returns the status s has in month m after an edit of its
+// dates, s.Status being the one before it. A cancellation stands; any
+// other status, expired included, is recomputed with StatusAt.
+func (s *Subscription) Restatus(m MonthDate, pauses []Pause) Status {
+	if s.Status == StatusCancelled {
+		return s.Status
+	}
+
+	return s.StatusAt(m, pauses)
+}
+
+// StatusAt returns the status a new subscription has in month m: its
+// dates and pauses followed from scheduled as far as they lead.
+func (s *Subscription) StatusAt(m MonthDate, pauses []Pause) Status {
+	sub := *s
+	sub.Status = StatusScheduled
+
+	for next := sub.NextStatus(m, pauses); next != sub.Status; next = sub.NextStatus(m, pauses) {
+		sub.Status = next
+	}
+
+	return sub.Status
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pgregory.net/rapid"
+)
+
+func TestStatus_CanMoveTo(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{StatusScheduled, StatusActive, true},
+		{StatusScheduled, StatusCancelled, false},
+		{StatusActive, StatusPaused, true},
+		{StatusActive, StatusCancelled, true},
+		{StatusActive, StatusExpired, true},
+		{StatusActive, StatusScheduled, false},
+		{StatusPaused, StatusActive, true},
+		{StatusPaused, StatusCancelled, true},
+		{StatusCancelled, StatusActive, false},
+		{StatusExpired, StatusActive, false},
+		{StatusActive, "gone", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.from.CanMoveTo(test.to), "%s -> %s", test.from, test.to)
+	}
+
+	assert.True(t, StatusCancelled.Final())
+	assert.True(t, StatusExpired.Final())
+	assert.False(t, StatusPaused.Final())
+	assert.False(t, Status("").Final())
+}
+
+func TestSubscription_StatusAt(t *testing.T) {
+	sub := Subscription{StartDate: month(2), EndDate: month(8)}
+	pauses := []Pause{{StartDate: month(4), EndDate: month(5)}}
+
+	tests := []struct {
+		month int
+		want  Status
+	}{
+		{1, StatusScheduled},
+		{2, StatusActive},
+		{4, StatusPaused},
+		{6, StatusActive},
+		{8, StatusActive},
+		{9, StatusExpired},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, sub.StatusAt(month(test.month), pauses), "month %d", test.month)
+	}
+}
+
+func TestSubscription_Restatus(t *testing.T) {
+	sub := Subscription{StartDate: month(2), EndDate: month(12), Status: StatusExpired}
+	assert.Equal(t, StatusActive, sub.Restatus(month(9), nil), "an extended subscription is active again")
+	assert.Equal(t, StatusPaused, sub.Restatus(month(9), []Pause{{StartDate: month(9)}}))
+
+	sub.Status = StatusCancelled
+	assert.Equal(t, StatusCancelled, sub.Restatus(month(9), nil), "a cancellation stands")
+}
+
+func TestSubscription_NextStatus(t *testing.T) {
+	sub := Subscription{StartDate: month(2), Status: StatusCancelled}
+	assert.Equal(t, StatusCancelled, sub.NextStatus(month(5), nil), "cancelled is final")
+
+	sub.Status = StatusScheduled
+	assert.Equal(t, StatusActive, sub.NextStatus(month(5), []Pause{{StartDate: month(3)}}), "one step at a time")
+}
+
+func TestSubscription_NextStatusProperty(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		r := genRange().Draw(t, "period")
+		sub := Subscription{StartDate: r.From, EndDate: r.To}
+
+		p := genRange().Draw(t, "pause")
+		pauses := []Pause{{StartDate: p.From, EndDate: p.To}}
+		m := genMonth().Draw(t, "month")
+
+		sub.Status = rapid.SampledFrom(Statuses).Draw(t, "status")
+		next := sub.NextStatus(m, pauses)
+
+		if next != sub.Status && !sub.Status.CanMoveTo(next) {
+			t.Fatalf("moved %s -> %s", sub.Status, next)
+		}
+	})
+}
@@ -1,21 +1,192 @@
 package models
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// Subscription is charged Price a month from StartDate to EndDate. The
+// first TrialMonths are free and the IntroMonths after them cost
+// IntroPrice instead. Status is kept by the storage and changes only
+// through its transitions.
 type Subscription struct {
-	ID          uuid.UUID `json:"id"                 db:"id"`
-	ServiceName string    `json:"service_name"       db:"service_name"`
-	Price       int       `json:"price,omitempty"    db:"price"`
-	UserID      uuid.UUID `json:"user_id"            db:"user_id"`
-	StartDate   MonthDate `json:"start_date"         db:"start_date"`
-	EndDate     MonthDate `json:"end_date,omitempty" db:"end_date"`
+	ID          uuid.UUID `json:"id"                     db:"id"`
+	ServiceName string    `json:"service_name"           db:"service_name"`
+	Category    string    `json:"category,omitempty"     db:"category"`
+	Price       int       `json:"price,omitempty"        db:"price"`
+	UserID      uuid.UUID `json:"user_id"                db:"user_id"`
+	StartDate   MonthDate `json:"start_date"             db:"start_date"`
+	EndDate     MonthDate `json:"end_date,omitempty"     db:"end_date"`
+	TrialMonths int       `json:"trial_months,omitempty" db:"trial_months"`
+	IntroPrice  int       `json:"intro_price,omitempty"  db:"intro_price"`
+	IntroMonths int       `json:"intro_months,omitempty" db:"intro_months"`
+	Status      Status    `json:"status,omitempty"       db:"status"`
+}
+
+// MarshalJSON adds in_trial: whether the current month is a trial month.
+func (s Subscription) MarshalJSON() ([]byte, error) {
+	type plain Subscription
+
+	return json.Marshal(struct {
+		plain
+		InTrial bool `json:"in_trial"`
+	}{plain(s), s.InTrial(time.Now())})
 }
 
 type SumRequest struct {
 	ServiceName string    `json:"service_name,omitempty"`
+	Category    string    `json:"category,omitempty"`
 	UserID      uuid.UUID `json:"user_id,omitempty"`
 	StartDate   MonthDate `json:"start_date"`
 	EndDate     MonthDate `json:"end_date"`
+	// Prorate charges partially covered months by the day.
+	Prorate bool `json:"prorate,omitempty"`
+	// Status counts only the subscriptions currently in it.
+	Status Status `json:"status,omitempty"`
+}
+
+// Period returns the months the subscription is billed for.
+func (s *Subscription) Period() MonthRange {
+	return MonthRange{From: s.StartDate.Month(), To: s.EndDate.Month()}
+}
+
+// Period returns the months the summary covers.
+func (r *SumRequest) Period() MonthRange {
+	return MonthRange{From: r.StartDate.Month(), To: r.EndDate.Month()}
+}
+
+// InTrial reports whether the month of now is a trial month of s.
+func (s *Subscription) InTrial(now time.Time) bool {
+	month := MonthDate{Time: now.UTC(), Valid: true}.Month()
+	return s.Period().Contains(month) && s.monthIndex(month) < s.TrialMonths
+}
+
+// PriceAt returns the amount charged for month m of the subscription:
+// nothing during the trial, IntroPrice for the intro months and Price
+// after them.
+func (s *Subscription) PriceAt(m MonthDate) int {
+	switch i := s.monthIndex(m); {
+	case i < s.TrialMonths:
+		return 0
+	case i < s.TrialMonths+s.IntroMonths:
+		return s.IntroPrice
+	}
+
+	return s.Price
+}
+
+// monthIndex returns how many months after the start month m is.
+func (s *Subscription) monthIndex(m MonthDate) int {
+	return s.StartDate.MonthsBetween(m) - 1
+}
+
+// DiscountAt returns how much discounts take off the price of month m of
+// the subscription: the sum of those applying to m, at most the price.
+func (s *Subscription) DiscountAt(m MonthDate, discounts []Discount) int {
+	price, off := s.PriceAt(m), 0
+
+	for i := range discounts {
+		if discounts[i].Period().Contains(m) {
+			off += discounts[i].Off(price)
+		}
+	}
+
+	return min(off, price)
+}
+
+// Cost returns the amount charged by the subscription for the months
+// between from and to inclusive.
+func (s *Subscription) Cost(from, to MonthDate) int {
+	return s.charge(from, to, false, nil, nil).Gross
+}
+
+// ProratedCost is Cost charged by the day: a partially covered month costs
+// the share of its days the subscription was active, rounded to the
+// nearest unit. An end date on the first of a month covers the whole
+// month, the way month precision dates are stored.
+func (s *Subscription) ProratedCost(from, to MonthDate) int {
+	return s.charge(from, to, true, nil, nil).Gross
+}
+
+// Charge returns what the subscription is charged for the months of req,
+// prorated when req asks for it, with discounts and pauses, those of the
+// subscription, applied month by month. Paused months cost nothing.
+func (s *Subscription) Charge(req *SumRequest, discounts []Discount, pauses []Pause) Summary {
+	return s.charge(req.StartDate, req.EndDate, req.Prorate, discounts, pauses)
+}
+
+// UserSubscription is a subscription listed for Payer, its owner or one of
+// its members, together with its discounts, pauses and members, so that
+// the payer's charges can be worked out without another lookup.
+type UserSubscription struct {
+	Subscription
+	Payer     uuid.UUID  `db:"payer"`
+	Discounts []Discount `db:"-"`
+	Pauses    []Pause    `db:"-"`
+	// Members is empty when the subscription is not shared.
+	Members []Member `db:"-"`
+}
+
+// Due returns the payer's share of what the subscription is charged for
+// the months of req with its discounts and pauses applied, as Summary
+// counts it for the payer.
+func (u *UserSubscription) Due(req *SumRequest) Summary {
+	due := u.Charge(req, u.Discounts, u.Pauses)
+	if len(u.Members) == 0 {
+		return due
+	}
+
+	return due.Share(u.Members, u.UserID, u.Payer)
+}
+
+func (s *Subscription) charge(from, to MonthDate, prorate bool, discounts []Discount, pauses []Pause) Summary {
+	months := s.overlap(from, to)
+	if months.Empty() {
+		return Summary{}
+	}
+
+	var total Summary
+
+	for month := months.From; !month.Time.After(months.To.Time); month = nextMonth(month) {
+		if PauseAt(month, pauses) != nil {
+			continue
+		}
+
+		gross, off := s.PriceAt(month), s.DiscountAt(month, discounts)
+
+		if prorate {
+			days, inMonth := s.activeDays(month)
+			gross = (2*gross*days + inMonth) / (2 * inMonth)
+			off = (2*off*days + inMonth) / (2 * inMonth)
+		}
+
+		total.Gross += gross
+		total.Discount += off
+	}
+
+	total.Net = total.Gross - total.Discount
+
+	return total
+}
+
+// activeDays returns the number of days of month the subscription was
+// active and the number of days in month.
+func (s *Subscription) activeDays(month MonthDate) (days, inMonth int) {
+	first, last := month.Time, month.Time.AddDate(0, 1, -1)
+
+	if s.StartDate.Time.After(first) {
+		first = s.StartDate.Time
+	}
+
+	if s.EndDate.Valid && s.EndDate.Time.Day() != 1 && s.EndDate.Time.Before(last) {
+		last = s.EndDate.Time
+	}
+
+	return int(last.Sub(first).Hours()/24) + 1, month.Time.AddDate(0, 1, -1).Day()
+}
+
+func (s *Subscription) overlap(from, to MonthDate) MonthRange {
+	return s.Period().Intersect(MonthRange{From: from.Month(), To: to.Month()})
 }
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_PriceAt(t *testing.T) {
+	sub := Subscription{
+		Price:       1000,
+		StartDate:   month(0),
+		TrialMonths: 2,
+		IntroPrice:  500,
+		IntroMonths: 3,
+	}
+
+	want := []int{0, 0, 500, 500, 500, 1000, 1000}
+	for i, price := range want {
+		assert.Equal(t, price, sub.PriceAt(month(i)), "month %d", i)
+	}
+
+	assert.Equal(t, 2*0+3*500+2*1000, sub.Cost(month(0), month(6)))
+	assert.Equal(t, 500+1000, sub.Cost(month(4), month(5)))
+}
+
+func TestSubscription_InTrial(t *testing.T) {
+	sub := Subscription{Price: 1000, StartDate: month(0), TrialMonths: 2}
+
+	assert.True(t, sub.InTrial(month(0).Time))
+	assert.True(t, sub.InTrial(month(1).Time.Add(15*24*time.Hour)))
+	assert.False(t, sub.InTrial(month(2).Time))
+	assert.False(t, sub.InTrial(month(-1).Time))
+
+	sub.EndDate = month(0)
+	assert.False(t, sub.InTrial(month(1).Time), "ended subscriptions are not in trial")
+}
+
+func TestSubscription_MarshalJSON(t *testing.T) {
+	now := time.Now().UTC()
+	sub := Subscription{
+		Price:       1000,
+		StartDate:   MonthDate{Time: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		TrialMonths: 1,
+	}
+
+	b, err := json.Marshal(sub)
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.Equal(t, true, out["in_trial"])
+	assert.Equal(t, float64(1), out["trial_months"])
+	assert.Equal(t, float64(1000), out["price"])
+
+	var back Subscription
+	require.NoError(t, json.Unmarshal(b, &back))
+	assert.Equal(t, sub, back)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventSubCreated     EventType = "subscription.created"
+	EventSubUpdated     EventType = "subscription.updated"
+	EventSubDeleted     EventType = "subscription.deleted"
+	EventSubEndingSoon  EventType = "subscription.ending_soon"
+	EventSubEnded       EventType = "subscription.ended"
+	EventSubLongRunning EventType = "subscription.long_running"
+)
+
+var EventTypes = []EventType{
+	EventSubCreated,
+	EventSubUpdated,
+	EventSubDeleted,
+	EventSubEndingSoon,
+	EventSubEnded,
+	EventSubLongRunning,
+}
+
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+type Webhook struct {
+	ID        uuid.UUID   `json:"id"`
+	URL       string      `json:"url"`
+	Secret    string      `json:"secret,omitempty"`
+	Events    []EventType `json:"events"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+type Delivery struct {
+	ID            uuid.UUID       `json:"id"              db:"id"`
+	WebhookID     uuid.UUID       `json:"webhook_id"      db:"webhook_id"`
+	EventID       int64           `json:"event_id"        db:"event_id"`
+	EventType     EventType       `json:"event_type"      db:"event_type"`
+	Payload       json.RawMessage `json:"payload"         db:"payload"`
+	Status        DeliveryStatus  `json:"status"          db:"status"`
+	Attempts      int             `json:"attempts"        db:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string          `json:"last_error"      db:"last_error"`
+	CreatedAt     time.Time       `json:"created_at"      db:"created_at"`
+	URL           string          `json:"-"               db:"url"`
+	Secret        string          `json:"-"               db:"secret"`
+}
@@ -0,0 +1,81 @@
+package rollup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+// Refresher keeps the spend rollup horizon HorizonMonths ahead of the
+// current month, so that summaries over open ended subscriptions stay
+// answerable from the rollup.
+type Refresher struct {
+	Logger *slog.Logger
+	Config *config.Rollup
+	Store  postgres.RollupAPI
+	Now    func() time.Time
+}
+
+func NewRefresher(logger *slog.Logger, cfg *config.Rollup, store postgres.RollupAPI) *Refresher {
+	return &Refresher{
+		Logger: logger,
+		Config: cfg,
+		Store:  store,
+		Now:    time.Now,
+	}
+}
+
+// Horizon returns the first day of the month months after now.
+func Horizon(now time.Time, months int) time.Time {
+	y, m, _ := now.UTC().Date()
+	return time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (r *Refresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick extends the rollup if this replica wins the advisory lock.
+func (r *Refresher) Tick(ctx context.Context) {
+	release, leader, err := r.Store.TryLock(ctx, r.Config.LockKey)
+	if err != nil {
+		r.Logger.Error(
+			"rollup lock",
+			"error", err,
+		)
+		return
+	}
+
+	if !leader {
+		r.Logger.Debug("rollup lock held by another replica")
+		return
+	}
+	defer release()
+
+	through := Horizon(r.Now(), r.Config.HorizonMonths)
+
+	if err := r.Store.Refresh(ctx, through); err != nil {
+		r.Logger.Error(
+			"rollup refresh",
+			"through", through,
+			"error", err,
+		)
+		return
+	}
+
+	r.Logger.Debug("rollup refreshed", "through", through)
+}
@@ -0,0 +1,72 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	postgres.RollupAPI
+	leader    bool
+	released  bool
+	refreshed []time.Time
+	err       error
+}
+
+func (f *fakeStore) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	return func() { f.released = true }, f.leader, nil
+}
+
+func (f *fakeStore) Refresh(ctx context.Context, through time.Time) error {
+	f.refreshed = append(f.refreshed, through)
+	return f.err
+}
+
+func setup(store *fakeStore) *Refresher {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	refresher := NewRefresher(logger, &config.Rollup{HorizonMonths: 24}, store)
+	refresher.Now = func() time.Time {
+		return time.Date(2024, time.November, 30, 23, 0, 0, 0, time.UTC)
+	}
+
+	return refresher
+}
+
+func TestHorizon(t *testing.T) {
+	now := time.Date(2024, time.November, 30, 23, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC), Horizon(now, 0))
+	assert.Equal(t, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), Horizon(now, 3))
+}
+
+func TestTick(t *testing.T) {
+	store := &fakeStore{leader: true}
+	setup(store).Tick(context.Background())
+
+	assert.Equal(t, []time.Time{time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)}, store.refreshed)
+	assert.True(t, store.released)
+}
+
+func TestTick_NotLeader(t *testing.T) {
+	store := &fakeStore{}
+	setup(store).Tick(context.Background())
+
+	assert.Empty(t, store.refreshed)
+}
+
+func TestTick_RefreshError(t *testing.T) {
+	store := &fakeStore{leader: true, err: errors.New("boom")}
+	setup(store).Tick(context.Background())
+
+	assert.Len(t, store.refreshed, 1)
+	assert.True(t, store.released)
+}
@@ -0,0 +1,25 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type LogNotifier struct {
+	Logger *slog.Logger
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, tx sqlx.ExecerContext, rem *models.Reminder) error {
+	n.Logger.Info(
+		"subscription reminder",
+		"kind", rem.Kind,
+		"subscription_id", rem.Subscription.ID,
+		"user_id", rem.Subscription.UserID,
+		"service_name", rem.Subscription.ServiceName,
+	)
+
+	return nil
+}
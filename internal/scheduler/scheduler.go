@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+// Notifier sends a reminder. Anything it writes to the database goes
+// through tx, the transaction recording the reminder.
+type Notifier interface {
+	Notify(ctx context.Context, tx sqlx.ExecerContext, rem *models.Reminder) error
+}
+
+type Scheduler struct {
+	Logger   *slog.Logger
+	Config   *config.Scheduler
+	Store    postgres.RemindersAPI
+	Notifier Notifier
+}
+
+func New(logger *slog.Logger, cfg *config.Scheduler, store postgres.RemindersAPI, notifier Notifier) *Scheduler {
+	return &Scheduler{
+		Logger:   logger,
+		Config:   cfg,
+		Store:    store,
+		Notifier: notifier,
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs one reminder pass if this replica wins the advisory lock.
+// Replicas that lose simply skip the pass.
+func (s *Scheduler) Tick(ctx context.Context) {
+	release, leader, err := s.Store.TryLock(ctx, s.Config.LockKey)
+	if err != nil {
+		s.Logger.Error(
+			"scheduler lock",
+			"error", err,
+		)
+		return
+	}
+
+	if !leader {
+		s.Logger.Debug("scheduler lock held by another replica")
+		return
+	}
+	defer release()
+
+	s.remind(ctx, models.ReminderEndingSoon, func() ([]models.Subscription, error) {
+		return s.Store.EndingSoon(ctx, s.Config.RemindDays)
+	})
+
+	s.remind(ctx, models.ReminderLongRunning, func() ([]models.Subscription, error) {
+		return s.Store.LongRunning(ctx, s.Config.LongRunningMonths)
+	})
+
+	s.remind(ctx, models.ReminderEnded, func() ([]models.Subscription, error) {
+		return s.Store.Ended(ctx)
+	})
+}
+
+func (s *Scheduler) remind(ctx context.Context, kind models.ReminderKind, find func() ([]models.Subscription, error)) {
+	subs, err := find()
+	if err != nil {
+		s.Logger.Error(
+			"scheduler find subs",
+			"kind", kind,
+			"error", err,
+		)
+		return
+	}
+
+	for _, sub := range subs {
+		rem := &models.Reminder{Kind: kind, Subscription: sub}
+
+		_, err := s.Store.RecordReminder(ctx, rem, func(tx sqlx.ExecerContext) error {
+			return s.Notifier.Notify(ctx, tx, rem)
+		})
+
+		if err != nil {
+			s.Logger.Error(
+				"scheduler reminder",
+				"kind", kind,
+				"subscription_id", sub.ID,
+				"error", err,
+			)
+		}
+	}
+}
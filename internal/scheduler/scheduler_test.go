@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	leader      bool
+	released    bool
+	endingSoon  []models.Subscription
+	longRunning []models.Subscription
+	ended       []models.Subscription
+	recorded    map[uuid.UUID]models.ReminderKind
+}
+
+func (f *fakeStore) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	return func() { f.released = true }, f.leader, nil
+}
+
+func (f *fakeStore) EndingSoon(ctx context.Context, days int) ([]models.Subscription, error) {
+	return f.endingSoon, nil
+}
+
+func (f *fakeStore) LongRunning(ctx context.Context, months int) ([]models.Subscription, error) {
+	return f.longRunning, nil
+}
+
+func (f *fakeStore) Ended(ctx context.Context) ([]models.Subscription, error) {
+	return f.ended, nil
+}
+
+func (f *fakeStore) RecordReminder(
+	ctx context.Context,
+	rem *models.Reminder,
+	notify func(tx sqlx.ExecerContext) error,
+) (bool, error) {
+	if _, ok := f.recorded[rem.Subscription.ID]; ok {
+		return false, nil
+	}
+
+	if err := notify(nil); err != nil {
+		return false, err
+	}
+
+	f.recorded[rem.Subscription.ID] = rem.Kind
+	return true, nil
+}
+
+type fakeNotifier struct {
+	reminders []models.Reminder
+	err       error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, tx sqlx.ExecerContext, rem *models.Reminder) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.reminders = append(f.reminders, *rem)
+	return nil
+}
+
+func setup(store *fakeStore, notifier *fakeNotifier) *Scheduler {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Scheduler{
+		Interval:          time.Hour,
+		RemindDays:        30,
+		LongRunningMonths: 12,
+	}
+
+	return New(logger, cfg, store, notifier)
+}
+
+func defaultSub() models.Subscription {
+	return models.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+	}
+}
+
+func TestTick_NotifiesEveryKind(t *testing.T) {
+	store := &fakeStore{
+		leader:      true,
+		endingSoon:  []models.Subscription{defaultSub()},
+		longRunning: []models.Subscription{defaultSub()},
+		ended:       []models.Subscription{defaultSub()},
+		recorded:    map[uuid.UUID]models.ReminderKind{},
+	}
+	notifier := &fakeNotifier{}
+
+	setup(store, notifier).Tick(context.Background())
+
+	assert.True(t, store.released)
+	assert.Len(t, notifier.reminders, 3)
+	assert.Equal(t, models.ReminderEndingSoon, store.recorded[store.endingSoon[0].ID])
+	assert.Equal(t, models.ReminderLongRunning, store.recorded[store.longRunning[0].ID])
+	assert.Equal(t, models.ReminderEnded, store.recorded[store.ended[0].ID])
+}
+
+func TestTick_NeverNotifiesTwice(t *testing.T) {
+	store := &fakeStore{
+		leader:     true,
+		endingSoon: []models.Subscription{defaultSub()},
+		recorded:   map[uuid.UUID]models.ReminderKind{},
+	}
+	notifier := &fakeNotifier{}
+	scheduler := setup(store, notifier)
+
+	scheduler.Tick(context.Background())
+	scheduler.Tick(context.Background())
+
+	assert.Len(t, notifier.reminders, 1)
+}
+
+func TestTick_FailedNotifyIsRetried(t *testing.T) {
+	store := &fakeStore{
+		leader:     true,
+		endingSoon: []models.Subscription{defaultSub()},
+		recorded:   map[uuid.UUID]models.ReminderKind{},
+	}
+	notifier := &fakeNotifier{err: assert.AnError}
+	scheduler := setup(store, notifier)
+
+	scheduler.Tick(context.Background())
+	assert.Empty(t, store.recorded)
+
+	notifier.err = nil
+	scheduler.Tick(context.Background())
+	assert.Len(t, notifier.reminders, 1)
+}
+
+func TestTick_SkipsWithoutLeadership(t *testing.T) {
+	store := &fakeStore{
+		endingSoon: []models.Subscription{defaultSub()},
+		recorded:   map[uuid.UUID]models.ReminderKind{},
+	}
+	notifier := &fakeNotifier{}
+
+	setup(store, notifier).Tick(context.Background())
+
+	assert.Empty(t, notifier.reminders)
+	assert.False(t, store.released)
+}
@@ -0,0 +1,343 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDB struct {
+	postgres.SubsAPI
+
+	mu        sync.Mutex
+	subs      []models.Subscription
+	discounts []models.Discount
+	pauses    []models.Pause
+	members   []models.Member
+	listCalls int
+	created   *models.Subscription
+}
+
+func (f *fakeDB) ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listCalls++
+
+	wanted := map[uuid.UUID]bool{}
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	subs := []models.UserSubscription{}
+	for _, sub := range f.subs {
+		listed := models.UserSubscription{Subscription: sub}
+		for _, d := range f.discounts {
+			if d.SubscriptionID == sub.ID {
+				listed.Discounts = append(listed.Discounts, d)
+			}
+		}
+		for _, p := range f.pauses {
+			if p.SubscriptionID == sub.ID {
+				listed.Pauses = append(listed.Pauses, p)
+			}
+		}
+		for _, m := range f.members {
+			if m.SubscriptionID == sub.ID {
+				listed.Members = append(listed.Members, m)
+			}
+		}
+
+		payers := []uuid.UUID{sub.UserID}
+		for _, m := range listed.Members {
+			if m.UserID != sub.UserID {
+				payers = append(payers, m.UserID)
+			}
+		}
+		for _, payer := range payers {
+			if wanted[payer] {
+				listed.Payer = payer
+				subs = append(subs, listed)
+			}
+		}
+	}
+	return subs, nil
+}
+
+func (f *fakeDB) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	return models.Summary{Gross: 12000, Discount: 1000, Net: 11000}, nil
+}
+
+func (f *fakeDB) Create(ctx context.Context, sub *models.Subscription) error {
+	sub.ID = uuid.New()
+	f.created = sub
+	return nil
+}
+
+type nopEvaluator struct{}
+
+func (nopEvaluator) Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	return nil, nil
+}
+
+func month(y int, m time.Month) models.MonthDate {
+	return models.MonthDate{Time: time.Date(y, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func setup(t *testing.T, subs ...models.Subscription) (*fakeDB, *Handler) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := &fakeDB{subs: subs}
+
+	resolver := NewResolver(logger, db, nopEvaluator{})
+	resolver.Now = func() time.Time { return time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC) }
+
+	handler, err := NewHandler(resolver)
+	require.NoError(t, err)
+
+	return db, handler
+}
+
+func execute(t *testing.T, handler *Handler, query string) map[string]any {
+	body, _ := json.Marshal(map[string]any{"query": query})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data   map[string]any `json:"data"`
+		Errors []any          `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Empty(t, response.Errors)
+
+	return response.Data
+}
+
+func executeErrors(t *testing.T, handler *Handler, query string) []string {
+	body, _ := json.Marshal(map[string]any{"query": query})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	messages := make([]string, 0, len(response.Errors))
+	for _, e := range response.Errors {
+		messages = append(messages, e.Message)
+	}
+	return messages
+}
+
+func TestUsers_BatchesLookups(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	db, handler := setup(t,
+		models.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: 1000, UserID: alice, StartDate: month(2024, 1)},
+		models.Subscription{ID: uuid.New(), ServiceName: "Spotify", Price: 300, UserID: alice, StartDate: month(2024, 1), EndDate: month(2024, 3)},
+		models.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: 1000, UserID: bob, StartDate: month(2024, 6)},
+	)
+
+	data := execute(t, handler, `{
+		users(ids: ["`+alice.String()+`", "`+bob.String()+`"]) {
+			id
+			subscriptions { serviceName }
+			totals(startDate: "01-2024", endDate: "12-2024") { serviceName total }
+			upcomingCharges(months: 2) { month amount }
+		}
+	}`)
+
+	assert.Equal(t, 1, db.listCalls)
+
+	users := data["users"].([]any)
+	require.Len(t, users, 2)
+
+	first := users[0].(map[string]any)
+	assert.Len(t, first["subscriptions"], 2)
+	assert.Equal(t, []any{
+		map[string]any{"serviceName": "Netflix", "total": float64(12000)},
+		map[string]any{"serviceName": "Spotify", "total": float64(900)},
+	}, first["totals"])
+	assert.Equal(t, []any{
+		map[string]any{"month": "03-2024", "amount": float64(1000)},
+		map[string]any{"month": "03-2024", "amount": float64(300)},
+		map[string]any{"month": "04-2024", "amount": float64(1000)},
+	}, first["upcomingCharges"])
+
+	second := users[1].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{"serviceName": "Netflix", "total": float64(7000)},
+	}, second["totals"])
+}
+
+func TestCreateSubscription(t *testing.T) {
+	db, handler := setup(t)
+	userID := uuid.New()
+
+	data := execute(t, handler, `mutation {
+		createSubscription(input: {
+			serviceName: "Netflix", price: 1000, userId: "`+userID.String()+`", startDate: "01-2024"
+		}) { id serviceName endDate }
+	}`)
+
+	require.NotNil(t, db.created)
+	created := data["createSubscription"].(map[string]any)
+	assert.Equal(t, db.created.ID.String(), created["id"])
+	assert.Nil(t, created["endDate"])
+}
+
+func TestSummary(t *testing.T) {
+	_, handler := setup(t)
+
+	data := execute(t, handler, `{
+		summary(input: {startDate: "01-2024", endDate: "12-2024"})
+		summaryBreakdown(input: {startDate: "01-2024", endDate: "12-2024"}) { gross discount net }
+	}`)
+
+	assert.Equal(t, float64(11000), data["summary"])
+	assert.Equal(t, map[string]any{"gross": float64(12000), "discount": float64(1000), "net": float64(11000)}, data["summaryBreakdown"])
+}
+
+func TestSubscriptions_Status(t *testing.T) {
+	userID := uuid.New()
+	_, handler := setup(t,
+		models.Subscription{ServiceName: "Netflix", Price: 1000, UserID: userID, StartDate: month(2024, time.January), Status: models.StatusActive},
+		models.Subscription{ServiceName: "Spotify", Price: 300, UserID: userID, StartDate: month(2024, time.January), Status: models.StatusCancelled},
+	)
+
+	data := execute(t, handler, `{
+		user(id: "`+userID.String()+`") {
+			subscriptions(status: "cancelled") { serviceName status }
+		}
+	}`)
+
+	user := data["user"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{"serviceName": "Spotify", "status": "cancelled"},
+	}, user["subscriptions"])
+}
+
+func TestUpcomingCharges_Months(t *testing.T) {
+	userID := uuid.New()
+	_, handler := setup(t,
+		models.Subscription{ServiceName: "Netflix", Price: 1000, UserID: userID, StartDate: month(2024, time.January)},
+	)
+
+	for _, months := range []int{0, -1, MaxUpcomingMonths + 1} {
+		errs := executeErrors(t, handler, fmt.Sprintf(`{
+			user(id: "%s") { upcomingCharges(months: %d) { amount } }
+		}`, userID, months))
+		assert.Equal(t, []string{ErrMonths.Error()}, errs, "months %d", months)
+	}
+
+	data := execute(t, handler, fmt.Sprintf(`{
+		user(id: "%s") { upcomingCharges(months: %d) { amount } }
+	}`, userID, MaxUpcomingMonths))
+	user := data["user"].(map[string]any)
+	assert.Len(t, user["upcomingCharges"], MaxUpcomingMonths)
+}
+
+func TestTotals_Overflow(t *testing.T) {
+	userID := uuid.New()
+	_, handler := setup(t,
+		models.Subscription{ServiceName: "Netflix", Price: math.MaxInt32, UserID: userID, StartDate: month(2024, time.January)},
+	)
+
+	errs := executeErrors(t, handler, `{
+		user(id: "`+userID.String()+`") {
+			totals(startDate: "01-2024", endDate: "02-2024") { total }
+		}
+	}`)
+	assert.Equal(t, []string{ErrOverflow.Error()}, errs)
+}
+
+func TestUser_Adjustments(t *testing.T) {
+	userID := uuid.New()
+	sub := models.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: 1000, UserID: userID, StartDate: month(2024, time.January)}
+	db, handler := setup(t, sub)
+
+	// 10% off from March 2024, paused in April 2024.
+	db.discounts = []models.Discount{
+		{SubscriptionID: sub.ID, Kind: models.DiscountPercent, Amount: 10, StartDate: month(2024, time.March)},
+	}
+	db.pauses = []models.Pause{
+		{SubscriptionID: sub.ID, StartDate: month(2024, time.April), EndDate: month(2024, time.April)},
+	}
+
+	data := execute(t, handler, `{
+		user(id: "`+userID.String()+`") {
+			totals(startDate: "01-2024", endDate: "06-2024") { serviceName total }
+			upcomingCharges(months: 3) { month amount }
+		}
+	}`)
+
+	user := data["user"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{"serviceName": "Netflix", "total": float64(2*1000 + 3*900)},
+	}, user["totals"])
+	assert.Equal(t, []any{
+		map[string]any{"month": "03-2024", "amount": float64(900)},
+		map[string]any{"month": "05-2024", "amount": float64(900)},
+	}, user["upcomingCharges"])
+}
+
+func TestUsers_SharedSubscription(t *testing.T) {
+	owner, alice, bob := uuid.New(), uuid.New(), uuid.New()
+	sub := models.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: 1200, UserID: owner, StartDate: month(2024, 1)}
+
+	db, handler := setup(t, sub)
+
+	// Shared in the weights 1:2:1, the owner included.
+	db.members = []models.Member{
+		{SubscriptionID: sub.ID, UserID: owner, Weight: 1},
+		{SubscriptionID: sub.ID, UserID: alice, Weight: 2},
+		{SubscriptionID: sub.ID, UserID: bob, Weight: 1},
+	}
+
+	data := execute(t, handler, `{
+		users(ids: ["`+owner.String()+`", "`+alice.String()+`", "`+bob.String()+`"]) {
+			subscriptions { serviceName }
+			totals(startDate: "01-2024", endDate: "02-2024") { serviceName total }
+			upcomingCharges(months: 1) { month amount }
+		}
+	}`)
+
+	assert.Equal(t, 1, db.listCalls)
+
+	users := data["users"].([]any)
+	require.Len(t, users, 3)
+
+	for i, share := range []int{300, 600, 300} {
+		user := users[i].(map[string]any)
+		assert.Equal(t, []any{map[string]any{"serviceName": "Netflix"}}, user["subscriptions"])
+		assert.Equal(t, []any{
+			map[string]any{"serviceName": "Netflix", "total": float64(2 * share)},
+		}, user["totals"])
+		assert.Equal(t, []any{
+			map[string]any{"month": "03-2024", "amount": float64(share)},
+		}, user["upcomingCharges"])
+	}
+}
@@ -0,0 +1,59 @@
+package gql
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+type Handler struct {
+	resolver *Resolver
+	relay    *relay.Handler
+}
+
+func NewHandler(resolver *Resolver) (*Handler, error) {
+	schema, err := graphql.ParseSchema(Schema, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		resolver: resolver,
+		relay:    &relay.Handler{Schema: schema},
+	}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := withLoader(r.Context(), newSubsLoader(h.resolver.DB))
+	h.relay.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// @Summary GraphQL endpoint
+// @Description Executes a GraphQL query or mutation against subscriptions. See internal/server/gql/schema.graphql for the schema.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body gql.Request true "GraphQL request"
+// @Success 200 {object} gql.Response
+// @Router /graphql [post]
+func (h *Handler) Serve(ctx echo.Context) error {
+	h.ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
+
+type Request struct {
+	Query         string         `json:"query"         example:"{ user(id: \"60601fee-2bf1-4721-ae6f-7636e79a0cba\") { subscriptions { serviceName price } } }"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Response struct {
+	Data   any   `json:"data"`
+	Errors []any `json:"errors,omitempty"`
+}
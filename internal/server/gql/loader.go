@@ -0,0 +1,61 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type loaderKey struct{}
+
+type subsLoader = dataloader.Interface[uuid.UUID, []models.UserSubscription]
+
+// newSubsLoader batches subscription lookups of all users requested while
+// resolving one query into a single ListUsers call. A user gets the
+// subscriptions they own or are a member of.
+func newSubsLoader(db postgres.SubsAPI) subsLoader {
+	batch := func(ctx context.Context, userIDs []uuid.UUID) []*dataloader.Result[[]models.UserSubscription] {
+		results := make([]*dataloader.Result[[]models.UserSubscription], len(userIDs))
+
+		list, err := db.ListUsers(ctx, userIDs)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[[]models.UserSubscription]{Error: err}
+			}
+			return results
+		}
+
+		byUser := make(map[uuid.UUID][]models.UserSubscription, len(userIDs))
+		for _, sub := range list {
+			byUser[sub.Payer] = append(byUser[sub.Payer], sub)
+		}
+
+		for i, userID := range userIDs {
+			subs := byUser[userID]
+			if subs == nil {
+				subs = []models.UserSubscription{}
+			}
+			results[i] = &dataloader.Result[[]models.UserSubscription]{Data: subs}
+		}
+
+		return results
+	}
+
+	return dataloader.NewBatchedLoader(batch)
+}
+
+func withLoader(ctx context.Context, loader subsLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+// loaderFrom returns the request scoped loader, falling back to a fresh one
+// when the resolver runs outside of Handler.
+func loaderFrom(ctx context.Context, db postgres.SubsAPI) subsLoader {
+	if loader, ok := ctx.Value(loaderKey{}).(subsLoader); ok {
+		return loader
+	}
+	return newSubsLoader(db)
+}
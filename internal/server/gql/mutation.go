@@ -0,0 +1,136 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/subs"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type subscriptionInput struct {
+	ServiceName string
+	Category    *string
+	Price       int32
+	UserID      graphql.ID
+	StartDate   string
+	EndDate     *string
+	TrialMonths *int32
+	IntroPrice  *int32
+	IntroMonths *int32
+}
+
+func (in *subscriptionInput) toModel() (*models.Subscription, error) {
+	sub := &models.Subscription{
+		ServiceName: in.ServiceName,
+		Price:       int(in.Price),
+	}
+
+	if in.Category != nil {
+		sub.Category = *in.Category
+	}
+
+	if in.TrialMonths != nil {
+		sub.TrialMonths = int(*in.TrialMonths)
+	}
+
+	if in.IntroPrice != nil {
+		sub.IntroPrice = int(*in.IntroPrice)
+	}
+
+	if in.IntroMonths != nil {
+		sub.IntroMonths = int(*in.IntroMonths)
+	}
+
+	var err error
+	if sub.UserID, err = parseID(in.UserID); err != nil {
+		return nil, err
+	}
+
+	if sub.StartDate, err = models.ParseMonthDate(in.StartDate); err != nil {
+		return nil, err
+	}
+
+	if in.EndDate != nil {
+		if sub.EndDate, err = models.ParseMonthDate(*in.EndDate); err != nil {
+			return nil, err
+		}
+	}
+
+	return sub, nil
+}
+
+func (r *Resolver) evaluateBudgets(ctx context.Context, userID uuid.UUID) {
+	if _, err := r.Budgets.Evaluate(postgres.WithPrimary(ctx), userID); err != nil {
+		logger.FromContext(ctx, r.Logger).ErrorContext(
+			ctx,
+			"evaluate budgets",
+			"user_id", userID,
+			"error", err,
+		)
+	}
+}
+
+func (r *Resolver) CreateSubscription(ctx context.Context, args struct{ Input subscriptionInput }) (*subResolver, error) {
+	sub, err := args.Input.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subs.ValidateSub(sub); err != nil {
+		return nil, r.userError(ctx, err)
+	}
+
+	if err := r.DB.Create(ctx, sub); err != nil {
+		return nil, r.userError(ctx, err)
+	}
+
+	r.evaluateBudgets(ctx, sub.UserID)
+
+	return &subResolver{*sub}, nil
+}
+
+func (r *Resolver) UpdateSubscription(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input subscriptionInput
+}) (*subResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := args.Input.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subs.ValidateSub(sub); err != nil {
+		return nil, r.userError(ctx, err)
+	}
+
+	sub.ID = id
+
+	if err := r.DB.Update(ctx, sub); err != nil {
+		return nil, r.userError(ctx, err)
+	}
+
+	r.evaluateBudgets(ctx, sub.UserID)
+
+	return &subResolver{*sub}, nil
+}
+
+func (r *Resolver) DeleteSubscription(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.DB.Delete(ctx, id); err != nil {
+		return false, r.userError(ctx, err)
+	}
+
+	return true, nil
+}
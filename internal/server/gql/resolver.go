@@ -0,0 +1,365 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/subs"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal    = errors.New("internal server error")
+	ErrInvalidID   = errors.New("invalid id")
+	ErrSubNotFound = errors.New("subscription not found")
+	ErrMonths      = errors.New("months must be between 1 and 36")
+	ErrOverflow    = errors.New("amount exceeds the Int range")
+)
+
+// MaxUpcomingMonths bounds upcomingCharges so one query cannot walk an
+// unbounded number of months.
+const MaxUpcomingMonths = 36
+
+type Resolver struct {
+	Logger  *slog.Logger
+	DB      postgres.SubsAPI
+	Budgets subs.BudgetEvaluator
+	Now     func() time.Time
+}
+
+func NewResolver(logger *slog.Logger, db postgres.SubsAPI, budgets subs.BudgetEvaluator) *Resolver {
+	return &Resolver{
+		Logger:  logger,
+		DB:      db,
+		Budgets: budgets,
+		Now:     time.Now,
+	}
+}
+
+// userError keeps messages of validation errors shared with the REST
+// handlers and hides everything else behind ErrInternal.
+func (r *Resolver) userError(ctx context.Context, err error) error {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		if msg, ok := httpErr.Message.(string); ok {
+			return errors.New(msg)
+		}
+	}
+
+	if errors.Is(err, postgres.ErrNotFound) {
+		return ErrSubNotFound
+	}
+
+	logger.FromContext(ctx, r.Logger).ErrorContext(
+		ctx,
+		"database",
+		"error", err,
+	)
+	return ErrInternal
+}
+
+// toInt32 narrows an amount to the GraphQL Int, which is 32-bit, instead
+// of letting large totals wrap around.
+func toInt32(amount int) (int32, error) {
+	if amount > math.MaxInt32 || amount < math.MinInt32 {
+		return 0, ErrOverflow
+	}
+	return int32(amount), nil
+}
+
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, ErrInvalidID
+	}
+	return parsed, nil
+}
+
+func (r *Resolver) Subscription(ctx context.Context, args struct{ ID graphql.ID }) (*subResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := r.DB.Read(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, r.userError(ctx, err)
+	}
+
+	return &subResolver{*sub}, nil
+}
+
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userResolver{root: r, id: id}, nil
+}
+
+func (r *Resolver) Users(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*userResolver, error) {
+	users := make([]*userResolver, 0, len(args.IDs))
+	for _, rawID := range args.IDs {
+		id, err := parseID(rawID)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, &userResolver{root: r, id: id})
+	}
+
+	return users, nil
+}
+
+type summaryInput struct {
+	ServiceName *string
+	Category    *string
+	UserID      *graphql.ID
+	StartDate   string
+	EndDate     string
+	Prorate     *bool
+	Status      *string
+}
+
+func (r *Resolver) Summary(ctx context.Context, args struct{ Input summaryInput }) (int32, error) {
+	sum, err := r.summary(ctx, &args.Input)
+	if err != nil {
+		return 0, err
+	}
+
+	return toInt32(sum.Net)
+}
+
+func (r *Resolver) SummaryBreakdown(ctx context.Context, args struct{ Input summaryInput }) (*breakdownResolver, error) {
+	sum, err := r.summary(ctx, &args.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &breakdownResolver{sum}, nil
+}
+
+func (r *Resolver) summary(ctx context.Context, input *summaryInput) (models.Summary, error) {
+	req := models.SumRequest{}
+
+	if input.ServiceName != nil {
+		req.ServiceName = *input.ServiceName
+	}
+
+	if input.Category != nil {
+		req.Category = *input.Category
+	}
+
+	if input.UserID != nil {
+		id, err := parseID(*input.UserID)
+		if err != nil {
+			return models.Summary{}, err
+		}
+		req.UserID = id
+	}
+
+	if input.Prorate != nil {
+		req.Prorate = *input.Prorate
+	}
+
+	if input.Status != nil {
+		req.Status = models.Status(*input.Status)
+	}
+
+	var err error
+	if req.StartDate, err = models.ParseMonthDate(input.StartDate); err != nil {
+		return models.Summary{}, err
+	}
+
+	if req.EndDate, err = models.ParseMonthDate(input.EndDate); err != nil {
+		return models.Summary{}, err
+	}
+
+	if err := subs.ValidateSumRequest(&req); err != nil {
+		return models.Summary{}, r.userError(ctx, err)
+	}
+
+	sum, err := r.DB.Summary(ctx, &req)
+	if err != nil {
+		return models.Summary{}, r.userError(ctx, err)
+	}
+
+	return sum, nil
+}
+
+type breakdownResolver struct {
+	sum models.Summary
+}
+
+func (b *breakdownResolver) Gross() (int32, error)    { return toInt32(b.sum.Gross) }
+func (b *breakdownResolver) Discount() (int32, error) { return toInt32(b.sum.Discount) }
+func (b *breakdownResolver) Net() (int32, error)      { return toInt32(b.sum.Net) }
+
+type subResolver struct {
+	sub models.Subscription
+}
+
+func (s *subResolver) ID() graphql.ID      { return graphql.ID(s.sub.ID.String()) }
+func (s *subResolver) ServiceName() string { return s.sub.ServiceName }
+func (s *subResolver) Category() string    { return s.sub.Category }
+func (s *subResolver) Price() int32        { return int32(s.sub.Price) }
+func (s *subResolver) UserID() graphql.ID  { return graphql.ID(s.sub.UserID.String()) }
+func (s *subResolver) StartDate() string   { return s.sub.StartDate.String() }
+func (s *subResolver) TrialMonths() int32  { return int32(s.sub.TrialMonths) }
+func (s *subResolver) IntroPrice() int32   { return int32(s.sub.IntroPrice) }
+func (s *subResolver) IntroMonths() int32  { return int32(s.sub.IntroMonths) }
+func (s *subResolver) InTrial() bool       { return s.sub.InTrial(time.Now()) }
+func (s *subResolver) Status() string      { return string(s.sub.Status) }
+
+func (s *subResolver) EndDate() *string {
+	if !s.sub.EndDate.Valid {
+		return nil
+	}
+
+	end := s.sub.EndDate.String()
+	return &end
+}
+
+type userResolver struct {
+	root *Resolver
+	id   uuid.UUID
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.id.String())
+}
+
+func (u *userResolver) subscriptions(ctx context.Context) ([]models.UserSubscription, error) {
+	list, err := loaderFrom(ctx, u.root.DB).Load(ctx, u.id)()
+	if err != nil {
+		return nil, u.root.userError(ctx, err)
+	}
+	return list, nil
+}
+
+func (u *userResolver) Subscriptions(ctx context.Context, args struct{ Status *string }) ([]*subResolver, error) {
+	var status models.Status
+	if args.Status != nil {
+		status = models.Status(*args.Status)
+		if !status.Valid() {
+			return nil, u.root.userError(ctx, subs.ErrInvalidStatus)
+		}
+	}
+
+	list, err := u.subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*subResolver, 0, len(list))
+	for _, sub := range list {
+		if status != "" && sub.Status != status {
+			continue
+		}
+		resolvers = append(resolvers, &subResolver{sub.Subscription})
+	}
+
+	return resolvers, nil
+}
+
+type serviceTotal struct {
+	serviceName string
+	total       int
+}
+
+func (t *serviceTotal) ServiceName() string   { return t.serviceName }
+func (t *serviceTotal) Total() (int32, error) { return toInt32(t.total) }
+
+func (u *userResolver) Totals(ctx context.Context, args struct {
+	StartDate string
+	EndDate   string
+}) ([]*serviceTotal, error) {
+	var (
+		req models.SumRequest
+		err error
+	)
+
+	if req.StartDate, err = models.ParseMonthDate(args.StartDate); err != nil {
+		return nil, err
+	}
+
+	if req.EndDate, err = models.ParseMonthDate(args.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := subs.ValidateSumRequest(&req); err != nil {
+		return nil, u.root.userError(ctx, err)
+	}
+
+	list, err := u.subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byService := map[string]int{}
+	for _, sub := range list {
+		byService[sub.ServiceName] += sub.Due(&req).Net
+	}
+
+	totals := make([]*serviceTotal, 0, len(byService))
+	for service, total := range byService {
+		totals = append(totals, &serviceTotal{service, total})
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].serviceName < totals[j].serviceName
+	})
+
+	return totals, nil
+}
+
+type charge struct {
+	month  models.MonthDate
+	amount int
+	sub    models.Subscription
+}
+
+func (c *charge) Month() string              { return c.month.String() }
+func (c *charge) Amount() (int32, error)     { return toInt32(c.amount) }
+func (c *charge) Subscription() *subResolver { return &subResolver{c.sub} }
+
+func (u *userResolver) UpcomingCharges(ctx context.Context, args struct{ Months int32 }) ([]*charge, error) {
+	if args.Months < 1 || args.Months > MaxUpcomingMonths {
+		return nil, ErrMonths
+	}
+
+	list, err := u.subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	y, m, _ := u.root.Now().UTC().Date()
+	current := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+
+	charges := []*charge{}
+	for i := 0; i < int(args.Months); i++ {
+		month := models.MonthDate{Time: current.AddDate(0, i, 0), Valid: true}
+		req := models.SumRequest{StartDate: month, EndDate: month}
+
+		for _, sub := range list {
+			if amount := sub.Due(&req).Net; amount > 0 {
+				charges = append(charges, &charge{month, amount, sub.Subscription})
+			}
+		}
+	}
+
+	return charges, nil
+}
@@ -0,0 +1,47 @@
+package budgets
+
+import (
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary List budget alerts
+// @Description Evaluates the user's budgets against the spend of the current month and the projected spend of the next one.
+// @Description Returns budgets that are breached or about to be breached.
+// @Tags budgets
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {array} budgets.BudgetStatusResponse
+// @Failure 400 {object} budgets.ErrorResponse
+// @Failure 500 {object} budgets.ErrorResponse
+// @Router /budgets/alerts/{id} [get]
+func (s *ServerAPI) Alerts(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	statuses, err := s.Evaluator.Evaluate(ctx.Request().Context(), id)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	alerts := []models.BudgetStatus{}
+	for _, status := range statuses {
+		if status.State != models.BudgetOK {
+			alerts = append(alerts, status)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, alerts)
+	return nil
+}
@@ -0,0 +1,40 @@
+package budgets
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal       = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest     = echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrLimitRequired  = echo.NewHTTPError(http.StatusBadRequest, "limit should be positive")
+	ErrUserIDRequired = echo.NewHTTPError(http.StatusBadRequest, "user_id is required")
+	ErrAmbiguousScope = echo.NewHTTPError(http.StatusBadRequest, "budget can limit either a service or a category")
+	ErrInvalidID      = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrBudgetNotFound = echo.NewHTTPError(http.StatusNotFound, "budget not found")
+)
+
+type Evaluator interface {
+	Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
+type ServerAPI struct {
+	Logger    *slog.Logger
+	DB        postgres.BudgetsAPI
+	Evaluator Evaluator
+}
+
+func NewServerAPI(logger *slog.Logger, db postgres.BudgetsAPI, evaluator Evaluator) *ServerAPI {
+	return &ServerAPI{
+		Logger:    logger,
+		DB:        db,
+		Evaluator: evaluator,
+	}
+}
@@ -0,0 +1,204 @@
+package budgets
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func ValidateBudget(budget *models.Budget) error {
+	if budget.Limit <= 0 {
+		return ErrLimitRequired
+	}
+
+	if budget.UserID == uuid.Nil {
+		return ErrUserIDRequired
+	}
+
+	if budget.ServiceName != "" && budget.Category != "" {
+		return ErrAmbiguousScope
+	}
+
+	return nil
+}
+
+// @Summary Create budget
+// @Description Creates a monthly budget for a user. Without service_name and category it limits the overall spend.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param budget body budgets.BudgetRequest true "Budget data"
+// @Success 201 {object} budgets.BudgetResponse
+// @Failure 400 {object} budgets.ErrorResponse
+// @Failure 500 {object} budgets.ErrorResponse
+// @Router /budgets [post]
+func (s *ServerAPI) Create(ctx echo.Context) error {
+	var budget models.Budget
+	if err := ctx.Bind(&budget); err != nil {
+		return ErrBadRequest
+	}
+
+	if err := ValidateBudget(&budget); err != nil {
+		return err
+	}
+
+	if err := s.DB.CreateBudget(ctx.Request().Context(), &budget); err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusCreated, &budget)
+	return nil
+}
+
+// @Summary Get budget
+// @Description Returns budget details by its ID.
+// @Tags budgets
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Success 200 {object} budgets.BudgetResponse
+// @Failure 400 {object} budgets.ErrorResponse
+// @Failure 404 {object} budgets.ErrorResponse
+// @Router /budgets/{id} [get]
+func (s *ServerAPI) Read(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	budget, err := s.DB.ReadBudget(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrBudgetNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, budget)
+	return nil
+}
+
+// @Summary Update budget
+// @Description Updates an existing budget by its ID.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Param budget body budgets.BudgetRequest true "Updated budget data"
+// @Success 200 {object} budgets.BudgetResponse
+// @Failure 400 {object} budgets.ErrorResponse
+// @Failure 404 {object} budgets.ErrorResponse
+// @Failure 500 {object} budgets.ErrorResponse
+// @Router /budgets/{id} [put]
+func (s *ServerAPI) Update(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	var budget models.Budget
+	if err := ctx.Bind(&budget); err != nil {
+		return ErrBadRequest
+	}
+
+	if err := ValidateBudget(&budget); err != nil {
+		return err
+	}
+
+	budget.ID = id
+
+	err = s.DB.UpdateBudget(ctx.Request().Context(), &budget)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrBudgetNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, budget)
+	return nil
+}
+
+// @Summary Delete budget
+// @Description Deletes a budget by its ID.
+// @Tags budgets
+// @Produce json
+// @Param id path string true "Budget ID (UUID)"
+// @Success 200 "Budget successfully deleted"
+// @Failure 400 {object} budgets.ErrorResponse
+// @Failure 404 {object} budgets.ErrorResponse
+// @Failure 500 {object} budgets.ErrorResponse
+// @Router /budgets/{id} [delete]
+func (s *ServerAPI) Delete(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	err = s.DB.DeleteBudget(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrBudgetNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.Response().WriteHeader(http.StatusOK)
+	return nil
+}
+
+// @Summary List user budgets
+// @Description Returns all budgets of a specific user.
+// @Tags budgets
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {array} budgets.BudgetResponse
+// @Failure 400 {object} budgets.ErrorResponse
+// @Failure 500 {object} budgets.ErrorResponse
+// @Router /budgets/list/{id} [get]
+func (s *ServerAPI) List(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	budgets, err := s.DB.ListBudgets(ctx.Request().Context(), id)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, budgets)
+	return nil
+}
@@ -0,0 +1,29 @@
+package budgets
+
+type BudgetRequest struct {
+	UserID      string `json:"user_id"                example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ServiceName string `json:"service_name,omitempty" example:"Netflix"`
+	Category    string `json:"category,omitempty"     example:"entertainment"`
+	Limit       int    `json:"limit"                  example:"1500"`
+}
+
+type BudgetResponse struct {
+	ID          string `json:"id"                     example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	UserID      string `json:"user_id"                example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ServiceName string `json:"service_name,omitempty" example:"Netflix"`
+	Category    string `json:"category,omitempty"     example:"entertainment"`
+	Limit       int    `json:"limit"                  example:"1500"`
+	State       string `json:"state"                  example:"warning"`
+	CreatedAt   string `json:"created_at"             example:"2024-01-01T00:00:00Z"`
+}
+
+type BudgetStatusResponse struct {
+	Budget  BudgetResponse `json:"budget"`
+	Current int            `json:"current" example:"1200"`
+	Next    int            `json:"next"    example:"1700"`
+	State   string         `json:"state"   example:"warning"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message" example:"error description"`
+}
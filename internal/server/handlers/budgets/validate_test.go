@@ -0,0 +1,68 @@
+package budgets
+
+import (
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultBudget() models.Budget {
+	return models.Budget{
+		UserID: uuid.New(),
+		Limit:  1500,
+	}
+}
+
+func TestValidateBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		modifyBudget func(*models.Budget)
+		wantErr      error
+	}{
+		{
+			name:         "valid overall budget",
+			modifyBudget: func(b *models.Budget) {},
+			wantErr:      nil,
+		},
+		{
+			name:         "valid service budget",
+			modifyBudget: func(b *models.Budget) { b.ServiceName = "Netflix" },
+			wantErr:      nil,
+		},
+		{
+			name:         "zero limit",
+			modifyBudget: func(b *models.Budget) { b.Limit = 0 },
+			wantErr:      ErrLimitRequired,
+		},
+		{
+			name:         "missing user id",
+			modifyBudget: func(b *models.Budget) { b.UserID = uuid.Nil },
+			wantErr:      ErrUserIDRequired,
+		},
+		{
+			name: "service and category",
+			modifyBudget: func(b *models.Budget) {
+				b.ServiceName = "Netflix"
+				b.Category = "entertainment"
+			},
+			wantErr: ErrAmbiguousScope,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			budget := defaultBudget()
+			test.modifyBudget(&budget)
+
+			err := ValidateBudget(&budget)
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package discounts
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal          = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest        = echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrInvalidKind       = echo.NewHTTPError(http.StatusBadRequest, "kind should be percent or fixed")
+	ErrAmountRequired    = echo.NewHTTPError(http.StatusBadRequest, "amount should be positive")
+	ErrPercentTooBig     = echo.NewHTTPError(http.StatusBadRequest, "percent amount should be at most 100")
+	ErrStartDateRequired = echo.NewHTTPError(http.StatusBadRequest, "start_date is required")
+	ErrCmpDates          = echo.NewHTTPError(http.StatusBadRequest, "end date should be after start date")
+	ErrInvalidID         = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrSubNotFound       = echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	ErrDiscountNotFound  = echo.NewHTTPError(http.StatusNotFound, "discount not found")
+)
+
+type BudgetEvaluator interface {
+	Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
+type ServerAPI struct {
+	Logger  *slog.Logger
+	DB      postgres.DiscountsAPI
+	Subs    postgres.SubsAPI
+	Budgets BudgetEvaluator
+}
+
+func NewServerAPI(
+	logger *slog.Logger,
+	db postgres.DiscountsAPI,
+	subs postgres.SubsAPI,
+	budgets BudgetEvaluator,
+) *ServerAPI {
+	return &ServerAPI{
+		Logger:  logger,
+		DB:      db,
+		Subs:    subs,
+		Budgets: budgets,
+	}
+}
+
+// evaluateBudgets refreshes the budget states of the owner of subID after a
+// change of its discounts. Failures are logged only: the change itself
+// succeeded.
+func (s *ServerAPI) evaluateBudgets(ctx context.Context, subID uuid.UUID) {
+	ctx = postgres.WithPrimary(ctx)
+
+	sub, err := s.Subs.Read(ctx, subID)
+	if err == nil {
+		_, err = s.Budgets.Evaluate(ctx, sub.UserID)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx, s.Logger).ErrorContext(
+			ctx,
+			"evaluate budgets",
+			"subscription_id", subID,
+			"error", err,
+		)
+	}
+}
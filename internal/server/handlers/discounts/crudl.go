@@ -0,0 +1,236 @@
+package discounts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func ValidateDiscount(discount *models.Discount) error {
+	if !discount.Kind.Valid() {
+		return ErrInvalidKind
+	}
+
+	if discount.Amount <= 0 {
+		return ErrAmountRequired
+	}
+
+	if discount.Kind == models.DiscountPercent && discount.Amount > 100 {
+		return ErrPercentTooBig
+	}
+
+	if discount.StartDate.IsZero() {
+		return ErrStartDateRequired
+	}
+
+	if !discount.EndDate.IsZero() && discount.EndDate.Time.Before(discount.StartDate.Time) {
+		return ErrCmpDates
+	}
+
+	return nil
+}
+
+// @Summary Create discount
+// @Description Adds a discount to a subscription: a percentage of or a fixed amount off every month from start_date to end_date.
+// @Description Discounts applying to the same month add up, but never take off more than the month's price.
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param discount body discounts.DiscountRequest true "Discount data"
+// @Success 201 {object} discounts.DiscountResponse
+// @Failure 400 {object} discounts.ErrorResponse
+// @Failure 404 {object} discounts.ErrorResponse
+// @Failure 500 {object} discounts.ErrorResponse
+// @Router /subs/{id}/discounts [post]
+func (s *ServerAPI) Create(ctx echo.Context) error {
+	subID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	var discount models.Discount
+	if err := ctx.Bind(&discount); err != nil {
+		return ErrBadRequest
+	}
+
+	if err := ValidateDiscount(&discount); err != nil {
+		return err
+	}
+
+	discount.SubscriptionID = subID
+
+	if err := s.DB.CreateDiscount(ctx.Request().Context(), &discount); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrSubNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.evaluateBudgets(ctx.Request().Context(), discount.SubscriptionID)
+
+	ctx.JSON(http.StatusCreated, &discount)
+	return nil
+}
+
+// @Summary Get discount
+// @Description Returns discount details by its ID.
+// @Tags discounts
+// @Produce json
+// @Param id path string true "Discount ID (UUID)"
+// @Success 200 {object} discounts.DiscountResponse
+// @Failure 400 {object} discounts.ErrorResponse
+// @Failure 404 {object} discounts.ErrorResponse
+// @Router /discounts/{id} [get]
+func (s *ServerAPI) Read(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	discount, err := s.DB.ReadDiscount(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrDiscountNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, discount)
+	return nil
+}
+
+// @Summary Update discount
+// @Description Updates the kind, amount and dates of a discount by its ID. The subscription it belongs to does not change.
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param id path string true "Discount ID (UUID)"
+// @Param discount body discounts.DiscountRequest true "Updated discount data"
+// @Success 200 {object} discounts.DiscountResponse
+// @Failure 400 {object} discounts.ErrorResponse
+// @Failure 404 {object} discounts.ErrorResponse
+// @Failure 500 {object} discounts.ErrorResponse
+// @Router /discounts/{id} [put]
+func (s *ServerAPI) Update(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	var discount models.Discount
+	if err := ctx.Bind(&discount); err != nil {
+		return ErrBadRequest
+	}
+
+	if err := ValidateDiscount(&discount); err != nil {
+		return err
+	}
+
+	discount.ID = id
+
+	err = s.DB.UpdateDiscount(ctx.Request().Context(), &discount)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrDiscountNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.evaluateBudgets(ctx.Request().Context(), discount.SubscriptionID)
+
+	ctx.JSON(http.StatusOK, discount)
+	return nil
+}
+
+// @Summary Delete discount
+// @Description Deletes a discount by its ID.
+// @Tags discounts
+// @Produce json
+// @Param id path string true "Discount ID (UUID)"
+// @Success 200 "Discount successfully deleted"
+// @Failure 400 {object} discounts.ErrorResponse
+// @Failure 404 {object} discounts.ErrorResponse
+// @Failure 500 {object} discounts.ErrorResponse
+// @Router /discounts/{id} [delete]
+func (s *ServerAPI) Delete(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	discount, err := s.DB.ReadDiscount(postgres.WithPrimary(ctx.Request().Context()), id)
+	if err == nil {
+		err = s.DB.DeleteDiscount(ctx.Request().Context(), id)
+	}
+
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrDiscountNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.evaluateBudgets(ctx.Request().Context(), discount.SubscriptionID)
+
+	ctx.Response().WriteHeader(http.StatusOK)
+	return nil
+}
+
+// @Summary List subscription discounts
+// @Description Returns all discounts of a subscription.
+// @Tags discounts
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} discounts.DiscountResponse
+// @Failure 400 {object} discounts.ErrorResponse
+// @Failure 500 {object} discounts.ErrorResponse
+// @Router /subs/{id}/discounts [get]
+func (s *ServerAPI) List(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	discounts, err := s.DB.ListDiscounts(ctx.Request().Context(), id)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, discounts)
+	return nil
+}
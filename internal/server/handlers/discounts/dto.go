@@ -0,0 +1,22 @@
+package discounts
+
+type DiscountRequest struct {
+	Kind      string `json:"kind"               example:"percent"`
+	Amount    int    `json:"amount"             example:"20"`
+	StartDate string `json:"start_date"         example:"01-2024"`
+	EndDate   string `json:"end_date,omitempty" example:"06-2024"`
+}
+
+type DiscountResponse struct {
+	ID             string `json:"id"                 example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	SubscriptionID string `json:"subscription_id"    example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Kind           string `json:"kind"               example:"percent"`
+	Amount         int    `json:"amount"             example:"20"`
+	StartDate      string `json:"start_date"         example:"01-2024"`
+	EndDate        string `json:"end_date,omitempty" example:"06-2024"`
+	CreatedAt      string `json:"created_at"         example:"2024-01-01T00:00:00Z"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message" example:"error description"`
+}
@@ -0,0 +1,87 @@
+package discounts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(m time.Month) models.MonthDate {
+	return models.MonthDate{Time: time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func defaultDiscount() models.Discount {
+	return models.Discount{
+		Kind:      models.DiscountPercent,
+		Amount:    20,
+		StartDate: month(time.January),
+	}
+}
+
+func TestValidateDiscount(t *testing.T) {
+	tests := []struct {
+		name           string
+		modifyDiscount func(*models.Discount)
+		wantErr        error
+	}{
+		{
+			name:           "valid open ended percent",
+			modifyDiscount: func(d *models.Discount) {},
+			wantErr:        nil,
+		},
+		{
+			name: "valid fixed",
+			modifyDiscount: func(d *models.Discount) {
+				d.Kind = models.DiscountFixed
+				d.Amount = 5000
+				d.EndDate = month(time.June)
+			},
+			wantErr: nil,
+		},
+		{
+			name:           "unknown kind",
+			modifyDiscount: func(d *models.Discount) { d.Kind = "coupon" },
+			wantErr:        ErrInvalidKind,
+		},
+		{
+			name:           "zero amount",
+			modifyDiscount: func(d *models.Discount) { d.Amount = 0 },
+			wantErr:        ErrAmountRequired,
+		},
+		{
+			name:           "percent over 100",
+			modifyDiscount: func(d *models.Discount) { d.Amount = 101 },
+			wantErr:        ErrPercentTooBig,
+		},
+		{
+			name:           "missing start date",
+			modifyDiscount: func(d *models.Discount) { d.StartDate = models.MonthDate{} },
+			wantErr:        ErrStartDateRequired,
+		},
+		{
+			name: "end before start",
+			modifyDiscount: func(d *models.Discount) {
+				d.StartDate = month(time.June)
+				d.EndDate = month(time.January)
+			},
+			wantErr: ErrCmpDates,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			discount := defaultDiscount()
+			test.modifyDiscount(&discount)
+
+			err := ValidateDiscount(&discount)
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Check reports whether a dependency of the service is usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type ServerAPI struct {
+	Logger  *slog.Logger
+	Timeout time.Duration
+	Checks  []Check
+
+	draining atomic.Bool
+}
+
+func NewServerAPI(logger *slog.Logger, timeout time.Duration, checks ...Check) *ServerAPI {
+	return &ServerAPI{
+		Logger:  logger,
+		Timeout: timeout,
+		Checks:  checks,
+	}
+}
+
+// Drain makes readiness fail from now on, so load balancers stop routing
+// new requests before the server shuts down.
+func (s *ServerAPI) Drain() {
+	s.draining.Store(true)
+}
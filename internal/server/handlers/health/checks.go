@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+)
+
+func DatabaseCheck(db postgres.SubsAPI) Check {
+	return Check{
+		Name: "database",
+		Run:  db.Ping,
+	}
+}
+
+// MigrationCheck fails unless the database schema is exactly at version
+// want and the last migration completed.
+func MigrationCheck(db postgres.SubsAPI, want uint) Check {
+	return Check{
+		Name: "migrations",
+		Run: func(ctx context.Context) error {
+			version, dirty, err := db.MigrationVersion(ctx)
+			if err != nil {
+				return err
+			}
+
+			if dirty {
+				return fmt.Errorf("migration %d is dirty", version)
+			}
+
+			if version != want {
+				return fmt.Errorf("schema version %d, expected %d", version, want)
+			}
+
+			return nil
+		},
+	}
+}
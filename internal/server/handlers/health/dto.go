@@ -0,0 +1,12 @@
+package health
+
+type ProbeResponse struct {
+	Status string                   `json:"status"           example:"ok"`
+	Checks map[string]CheckResponse `json:"checks,omitempty"`
+}
+
+type CheckResponse struct {
+	Status   string `json:"status"          example:"fail"`
+	Duration string `json:"duration"        example:"1.2ms"`
+	Error    string `json:"error,omitempty" example:"schema version 4, expected 5"`
+}
@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDB struct {
+	postgres.SubsAPI
+	pingErr error
+	version uint
+	dirty   bool
+}
+
+func (f *fakeDB) Ping(ctx context.Context) error {
+	return f.pingErr
+}
+
+func (f *fakeDB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return f.version, f.dirty, nil
+}
+
+func probe(t *testing.T, api *ServerAPI, path string) (int, ProbeResponse) {
+	router := echo.New()
+	router.GET("/healthz", api.Live)
+	router.GET("/readyz", api.Ready)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp ProbeResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+
+	return rec.Code, resp
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         *fakeDB
+		wantCode   int
+		wantFailed []string
+	}{
+		{
+			name:     "ready",
+			db:       &fakeDB{version: 5},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "database down",
+			db:         &fakeDB{pingErr: errors.New("connection refused"), version: 5},
+			wantCode:   http.StatusServiceUnavailable,
+			wantFailed: []string{"database"},
+		},
+		{
+			name:       "schema behind",
+			db:         &fakeDB{version: 4},
+			wantCode:   http.StatusServiceUnavailable,
+			wantFailed: []string{"migrations"},
+		},
+		{
+			name:       "dirty migration",
+			db:         &fakeDB{version: 5, dirty: true},
+			wantCode:   http.StatusServiceUnavailable,
+			wantFailed: []string{"migrations"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			api := NewServerAPI(logger, time.Second, DatabaseCheck(test.db), MigrationCheck(test.db, 5))
+
+			code, resp := probe(t, api, "/readyz")
+			assert.Equal(t, test.wantCode, code)
+			require.Len(t, resp.Checks, 2)
+
+			for name, check := range resp.Checks {
+				if assert.Contains(t, []string{"database", "migrations"}, name) {
+					failed := check.Status == StatusFail
+					assert.Equal(t, failed, slices.Contains(test.wantFailed, name), name)
+					assert.Equal(t, failed, check.Error != "", name)
+				}
+			}
+		})
+	}
+}
+
+func TestReady_Timeout(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	slow := Check{
+		Name: "slow",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	code, resp := probe(t, NewServerAPI(logger, 10*time.Millisecond, slow), "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), resp.Checks["slow"].Error)
+}
+
+func TestDrain(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	api := NewServerAPI(logger, time.Second, DatabaseCheck(&fakeDB{}))
+
+	code, _ := probe(t, api, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	api.Drain()
+
+	code, resp := probe(t, api, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDraining, resp.Status)
+
+	code, _ = probe(t, api, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// @Summary Liveness probe
+// @Description Reports that the process is running. It does not touch any dependency.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.ProbeResponse
+// @Router /healthz [get]
+func (s *ServerAPI) Live(ctx echo.Context) error {
+	ctx.JSON(http.StatusOK, &ProbeResponse{Status: StatusOK})
+	return nil
+}
+
+// @Summary Readiness probe
+// @Description Runs every dependency check with a timeout and reports the result of each one.
+// @Description Fails while the server is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.ProbeResponse
+// @Failure 503 {object} health.ProbeResponse
+// @Router /readyz [get]
+func (s *ServerAPI) Ready(ctx echo.Context) error {
+	if s.draining.Load() {
+		ctx.JSON(http.StatusServiceUnavailable, &ProbeResponse{Status: StatusDraining})
+		return nil
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx.Request().Context(), s.Timeout)
+	defer cancel()
+
+	resp := &ProbeResponse{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResponse, len(s.Checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, check := range s.Checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check.Run(checkCtx)
+
+			result := CheckResponse{
+				Status:   StatusOK,
+				Duration: time.Since(start).String(),
+			}
+
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+
+				logger.FromContext(ctx.Request().Context(), s.Logger).WarnContext(
+					ctx.Request().Context(),
+					"readiness check",
+					"check", check.Name,
+					"error", err,
+				)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			resp.Checks[check.Name] = result
+			if err != nil {
+				resp.Status = StatusFail
+			}
+		}(check)
+	}
+
+	wg.Wait()
+
+	code := http.StatusOK
+	if resp.Status != StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(code, resp)
+	return nil
+}
@@ -0,0 +1,87 @@
+package members
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal       = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest     = echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrWeightRequired = echo.NewHTTPError(http.StatusBadRequest, "weight should be positive")
+	ErrRemoveOwner    = echo.NewHTTPError(http.StatusBadRequest, "the owner cannot be removed")
+	ErrInvalidID      = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrNotOwner       = echo.NewHTTPError(http.StatusForbidden, "only the owner can change members")
+	ErrNotMember      = echo.NewHTTPError(http.StatusForbidden, "only the owner and members can list members")
+	ErrSubNotFound    = echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	ErrMemberNotFound = echo.NewHTTPError(http.StatusNotFound, "member not found")
+)
+
+type BudgetEvaluator interface {
+	Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
+type ServerAPI struct {
+	Logger  *slog.Logger
+	DB      postgres.MembersAPI
+	Subs    postgres.SubsAPI
+	Budgets BudgetEvaluator
+}
+
+func NewServerAPI(
+	logger *slog.Logger,
+	db postgres.MembersAPI,
+	subs postgres.SubsAPI,
+	budgets BudgetEvaluator,
+) *ServerAPI {
+	return &ServerAPI{
+		Logger:  logger,
+		DB:      db,
+		Subs:    subs,
+		Budgets: budgets,
+	}
+}
+
+// evaluateBudgets refreshes the budget states of everyone sharing sub after
+// its members changed, as well as of extra users who left it. Failures are
+// logged only: the change itself succeeded.
+func (s *ServerAPI) evaluateBudgets(ctx context.Context, sub *models.Subscription, extra ...uuid.UUID) {
+	ctx = postgres.WithPrimary(ctx)
+
+	users := append([]uuid.UUID{sub.UserID}, extra...)
+
+	members, err := s.DB.ListMembers(ctx, sub.ID)
+	if err != nil {
+		logger.FromContext(ctx, s.Logger).ErrorContext(
+			ctx,
+			"evaluate budgets",
+			"subscription_id", sub.ID,
+			"error", err,
+		)
+	}
+
+	for _, m := range members {
+		if m.UserID != sub.UserID {
+			users = append(users, m.UserID)
+		}
+	}
+
+	for _, userID := range users {
+		if _, err := s.Budgets.Evaluate(ctx, userID); err != nil {
+			logger.FromContext(ctx, s.Logger).ErrorContext(
+				ctx,
+				"evaluate budgets",
+				"subscription_id", sub.ID,
+				"user_id", userID,
+				"error", err,
+			)
+		}
+	}
+}
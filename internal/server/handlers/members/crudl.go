@@ -0,0 +1,211 @@
+package members
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/server/middleware"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func ValidateMember(member *models.Member) error {
+	if member.Weight <= 0 {
+		return ErrWeightRequired
+	}
+
+	return nil
+}
+
+// owned reads the subscription in the id path parameter and checks that the
+// caller, identified by the X-User-ID header, owns it.
+func (s *ServerAPI) owned(ctx echo.Context) (*models.Subscription, error) {
+	sub, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caller, err := uuid.Parse(ctx.Request().Header.Get(middleware.HeaderUserID))
+	if err != nil || caller != sub.UserID {
+		return nil, ErrNotOwner
+	}
+
+	return sub, nil
+}
+
+// read reads the subscription in the id path parameter from the primary.
+func (s *ServerAPI) read(ctx echo.Context) (*models.Subscription, error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	sub, err := s.Subs.Read(postgres.WithPrimary(ctx.Request().Context()), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, ErrSubNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return nil, ErrInternal
+	}
+
+	return sub, nil
+}
+
+// @Summary Set subscription member
+// @Description Shares a subscription with a user or changes the weight of an existing member. Only the owner, passed in the X-User-ID header, may do so.
+// @Description The cost of a shared subscription is split among its members in proportion to their weights; the owner joins with weight 1 when the subscription is shared for the first time.
+// @Tags members
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Owner ID (UUID)"
+// @Param id path string true "Subscription ID (UUID)"
+// @Param user path string true "Member ID (UUID)"
+// @Param member body members.MemberRequest false "Member weight, 1 by default"
+// @Success 200 {object} members.MemberResponse
+// @Failure 400 {object} members.ErrorResponse
+// @Failure 403 {object} members.ErrorResponse
+// @Failure 404 {object} members.ErrorResponse
+// @Failure 500 {object} members.ErrorResponse
+// @Router /subs/{id}/members/{user} [put]
+func (s *ServerAPI) Set(ctx echo.Context) error {
+	sub, err := s.owned(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(ctx.Param("user"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	member := models.Member{Weight: 1}
+	if err := ctx.Bind(&member); err != nil {
+		return ErrBadRequest
+	}
+
+	if err := ValidateMember(&member); err != nil {
+		return err
+	}
+
+	member.SubscriptionID, member.UserID = sub.ID, userID
+
+	if err := s.DB.SetMember(ctx.Request().Context(), &member); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrSubNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.evaluateBudgets(ctx.Request().Context(), sub)
+
+	ctx.JSON(http.StatusOK, &member)
+	return nil
+}
+
+// @Summary Remove subscription member
+// @Description Stops sharing a subscription with a user. Only the owner, passed in the X-User-ID header, may do so, and the owner cannot be removed.
+// @Description Once the last other member is removed the owner pays for the subscription alone again.
+// @Tags members
+// @Produce json
+// @Param X-User-ID header string true "Owner ID (UUID)"
+// @Param id path string true "Subscription ID (UUID)"
+// @Param user path string true "Member ID (UUID)"
+// @Success 200 "Member successfully removed"
+// @Failure 400 {object} members.ErrorResponse
+// @Failure 403 {object} members.ErrorResponse
+// @Failure 404 {object} members.ErrorResponse
+// @Failure 500 {object} members.ErrorResponse
+// @Router /subs/{id}/members/{user} [delete]
+func (s *ServerAPI) Remove(ctx echo.Context) error {
+	sub, err := s.owned(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(ctx.Param("user"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	if userID == sub.UserID {
+		return ErrRemoveOwner
+	}
+
+	if err := s.DB.RemoveMember(ctx.Request().Context(), sub.ID, userID); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrMemberNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.evaluateBudgets(ctx.Request().Context(), sub, userID)
+
+	ctx.Response().WriteHeader(http.StatusOK)
+	return nil
+}
+
+// @Summary List subscription members
+// @Description Returns all members of a subscription with their weights. An unshared subscription has none.
+// @Description Only the owner and the members, passed in the X-User-ID header, may list them.
+// @Tags members
+// @Produce json
+// @Param X-User-ID header string true "Owner or member ID (UUID)"
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} members.MemberResponse
+// @Failure 400 {object} members.ErrorResponse
+// @Failure 403 {object} members.ErrorResponse
+// @Failure 404 {object} members.ErrorResponse
+// @Failure 500 {object} members.ErrorResponse
+// @Router /subs/{id}/members [get]
+func (s *ServerAPI) List(ctx echo.Context) error {
+	sub, err := s.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	caller, err := uuid.Parse(ctx.Request().Header.Get(middleware.HeaderUserID))
+	if err != nil {
+		return ErrNotMember
+	}
+
+	members, err := s.DB.ListMembers(ctx.Request().Context(), sub.ID)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	if caller != sub.UserID && !slices.ContainsFunc(members, func(m models.Member) bool {
+		return m.UserID == caller
+	}) {
+		return ErrNotMember
+	}
+
+	ctx.JSON(http.StatusOK, members)
+	return nil
+}
@@ -0,0 +1,16 @@
+package members
+
+type MemberRequest struct {
+	Weight int `json:"weight" example:"1"`
+}
+
+type MemberResponse struct {
+	SubscriptionID string `json:"subscription_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	UserID         string `json:"user_id"         example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Weight         int    `json:"weight"          example:"1"`
+	CreatedAt      string `json:"created_at"      example:"2024-01-01T00:00:00Z"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message" example:"error description"`
+}
@@ -0,0 +1,33 @@
+package members
+
+import (
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		weight  int
+		wantErr error
+	}{
+		{"default weight", 1, nil},
+		{"heavier member", 3, nil},
+		{"zero weight", 0, ErrWeightRequired},
+		{"negative weight", -1, ErrWeightRequired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateMember(&models.Member{Weight: test.weight})
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
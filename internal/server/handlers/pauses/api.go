@@ -0,0 +1,84 @@
+package pauses
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal          = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest        = echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrOutsidePeriod     = echo.NewHTTPError(http.StatusBadRequest, "pause should be within the subscription period")
+	ErrCmpDates          = echo.NewHTTPError(http.StatusBadRequest, "end date should be after start date")
+	ErrResumeBeforePause = echo.NewHTTPError(http.StatusBadRequest, "resume date should be after the pause start")
+	ErrInvalidID         = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrSubNotFound       = echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	ErrAlreadyPaused     = echo.NewHTTPError(http.StatusConflict, "subscription is already paused in these months")
+	ErrNotPaused         = echo.NewHTTPError(http.StatusConflict, "subscription is not paused")
+	ErrEnded             = echo.NewHTTPError(http.StatusConflict, "subscription is cancelled or expired")
+	ErrReasonTooLong     = echo.NewHTTPError(http.StatusBadRequest, "reason is too long")
+)
+
+type BudgetEvaluator interface {
+	Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
+type ServerAPI struct {
+	Logger  *slog.Logger
+	DB      postgres.PausesAPI
+	Subs    postgres.SubsAPI
+	Status  postgres.StatusAPI
+	Budgets BudgetEvaluator
+}
+
+func NewServerAPI(
+	logger *slog.Logger,
+	db postgres.PausesAPI,
+	subs postgres.SubsAPI,
+	status postgres.StatusAPI,
+	budgets BudgetEvaluator,
+) *ServerAPI {
+	return &ServerAPI{
+		Logger:  logger,
+		DB:      db,
+		Subs:    subs,
+		Status:  status,
+		Budgets: budgets,
+	}
+}
+
+// advance moves sub to the status its pauses call for this month, with
+// reason when set. Failures are logged only: the lifecycle job catches up.
+func (s *ServerAPI) advance(ctx context.Context, sub *models.Subscription, reason string) {
+	if _, err := s.Status.Advance(ctx, thisMonth(), reason, sub.ID); err != nil {
+		logger.FromContext(ctx, s.Logger).ErrorContext(
+			ctx,
+			"advance status",
+			"subscription_id", sub.ID,
+			"error", err,
+		)
+	}
+}
+
+// evaluateBudgets refreshes the budget states of the owner of sub after it
+// was paused or resumed. Failures are logged only: the change itself
+// succeeded.
+func (s *ServerAPI) evaluateBudgets(ctx context.Context, sub *models.Subscription) {
+	ctx = postgres.WithPrimary(ctx)
+
+	if _, err := s.Budgets.Evaluate(ctx, sub.UserID); err != nil {
+		logger.FromContext(ctx, s.Logger).ErrorContext(
+			ctx,
+			"evaluate budgets",
+			"subscription_id", sub.ID,
+			"error", err,
+		)
+	}
+}
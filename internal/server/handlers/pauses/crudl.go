@@ -0,0 +1,261 @@
+package pauses
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxReasonLen is the longest reason accepted for a pause or a resume, in
+// bytes.
+const MaxReasonLen = 500
+
+// ValidatePause checks that pause, with dates truncated to months, lies
+// within the period of sub.
+func ValidatePause(sub *models.Subscription, pause *models.Pause) error {
+	if sub.Status.Final() {
+		return ErrEnded
+	}
+
+	if pause.EndDate.Valid && pause.EndDate.Time.Before(pause.StartDate.Time) {
+		return ErrCmpDates
+	}
+
+	period := sub.Period()
+	if !period.Contains(pause.StartDate) || pause.EndDate.Valid && !period.Contains(pause.EndDate) {
+		return ErrOutsidePeriod
+	}
+
+	return nil
+}
+
+// ValidateResume checks that sub can be resumed in month m, ending pause,
+// the pause covering m.
+func ValidateResume(sub *models.Subscription, pause *models.Pause, m models.MonthDate) error {
+	if sub.Status.Final() {
+		return ErrEnded
+	}
+
+	if pause == nil {
+		return ErrNotPaused
+	}
+
+	if !m.Time.After(pause.StartDate.Time) {
+		return ErrResumeBeforePause
+	}
+
+	if !sub.Period().Contains(m) {
+		return ErrOutsidePeriod
+	}
+
+	return nil
+}
+
+// thisMonth returns the first day of the current month.
+func thisMonth() models.MonthDate {
+	return models.MonthOf(time.Now())
+}
+
+func (s *ServerAPI) readSub(ctx echo.Context) (*models.Subscription, error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	sub, err := s.Subs.Read(postgres.WithPrimary(ctx.Request().Context()), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, ErrSubNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return nil, ErrInternal
+	}
+
+	return sub, nil
+}
+
+// @Summary Pause subscription
+// @Description Stops charging a subscription from start_date, the current month by default, to end_date, both months included.
+// @Description Without end_date the subscription stays paused until it is resumed. Paused months are left out of summaries.
+// @Description A pause starting this month moves the subscription to paused with reason, if given, in its transition history.
+// @Tags pauses
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param pause body pauses.PauseRequest false "Paused months"
+// @Success 201 {object} pauses.PauseResponse
+// @Failure 400 {object} pauses.ErrorResponse
+// @Failure 404 {object} pauses.ErrorResponse
+// @Failure 409 {object} pauses.ErrorResponse
+// @Failure 500 {object} pauses.ErrorResponse
+// @Router /subs/{id}/pause [post]
+func (s *ServerAPI) Pause(ctx echo.Context) error {
+	sub, err := s.readSub(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		models.Pause
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&req); err != nil {
+		return ErrBadRequest
+	}
+
+	if len(req.Reason) > MaxReasonLen {
+		return ErrReasonTooLong
+	}
+
+	pause := req.Pause
+
+	if pause.StartDate.IsZero() {
+		pause.StartDate = thisMonth()
+	}
+
+	pause.StartDate, pause.EndDate = pause.StartDate.Month(), pause.EndDate.Month()
+
+	if err := ValidatePause(sub, &pause); err != nil {
+		return err
+	}
+
+	pause.ID, pause.SubscriptionID = uuid.Nil, sub.ID
+
+	if err := s.DB.CreatePause(ctx.Request().Context(), &pause); err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrNotFound):
+			return ErrSubNotFound
+		case errors.Is(err, postgres.ErrPauseOverlap):
+			return ErrAlreadyPaused
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.advance(ctx.Request().Context(), sub, strings.TrimSpace(req.Reason))
+	s.evaluateBudgets(ctx.Request().Context(), sub)
+
+	ctx.JSON(http.StatusCreated, &pause)
+	return nil
+}
+
+// @Summary Resume subscription
+// @Description Charges a paused subscription again from date, the current month by default, ending the pause covering that month.
+// @Description Resuming this month moves the subscription back to active with reason, if given, in its transition history.
+// @Tags pauses
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param resume body pauses.ResumeRequest false "First month charged again"
+// @Success 200 {object} pauses.PauseResponse
+// @Failure 400 {object} pauses.ErrorResponse
+// @Failure 404 {object} pauses.ErrorResponse
+// @Failure 409 {object} pauses.ErrorResponse
+// @Failure 500 {object} pauses.ErrorResponse
+// @Router /subs/{id}/resume [post]
+func (s *ServerAPI) Resume(ctx echo.Context) error {
+	sub, err := s.readSub(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Date   models.MonthDate `json:"date"`
+		Reason string           `json:"reason"`
+	}
+	if err := ctx.Bind(&req); err != nil {
+		return ErrBadRequest
+	}
+
+	if len(req.Reason) > MaxReasonLen {
+		return ErrReasonTooLong
+	}
+
+	month := req.Date.Month()
+	if month.IsZero() {
+		month = thisMonth()
+	}
+
+	pauses, err := s.DB.ListPauses(postgres.WithPrimary(ctx.Request().Context()), sub.ID)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	pause := models.PauseAt(month, pauses)
+
+	if err := ValidateResume(sub, pause, month); err != nil {
+		return err
+	}
+
+	pause.Resume(month)
+
+	if err := s.DB.UpdatePause(ctx.Request().Context(), pause); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrNotPaused
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.advance(ctx.Request().Context(), sub, strings.TrimSpace(req.Reason))
+	s.evaluateBudgets(ctx.Request().Context(), sub)
+
+	ctx.JSON(http.StatusOK, pause)
+	return nil
+}
+
+// @Summary List subscription pauses
+// @Description Returns all pauses of a subscription, ordered by start date.
+// @Tags pauses
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} pauses.PauseResponse
+// @Failure 400 {object} pauses.ErrorResponse
+// @Failure 500 {object} pauses.ErrorResponse
+// @Router /subs/{id}/pauses [get]
+func (s *ServerAPI) List(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	pauses, err := s.DB.ListPauses(ctx.Request().Context(), id)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, pauses)
+	return nil
+}
@@ -0,0 +1,24 @@
+package pauses
+
+type PauseRequest struct {
+	StartDate string `json:"start_date,omitempty" example:"06-2024"`
+	EndDate   string `json:"end_date,omitempty"   example:"08-2024"`
+	Reason    string `json:"reason,omitempty"     example:"on vacation"`
+}
+
+type ResumeRequest struct {
+	Date   string `json:"date,omitempty"   example:"09-2024"`
+	Reason string `json:"reason,omitempty" example:"back from vacation"`
+}
+
+type PauseResponse struct {
+	ID             string `json:"id"                 example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	SubscriptionID string `json:"subscription_id"    example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate      string `json:"start_date"         example:"06-2024"`
+	EndDate        string `json:"end_date,omitempty" example:"08-2024"`
+	CreatedAt      string `json:"created_at"         example:"2024-01-01T00:00:00Z"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message" example:"error description"`
+}
@@ -0,0 +1,113 @@
+package pauses
+
+import (
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(m time.Month) models.MonthDate {
+	return models.MonthDate{Time: time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func defaultSub() models.Subscription {
+	return models.Subscription{
+		Price:     1000,
+		StartDate: month(time.February),
+		EndDate:   month(time.November),
+	}
+}
+
+func TestValidatePause(t *testing.T) {
+	tests := []struct {
+		name    string
+		pause   models.Pause
+		wantErr error
+	}{
+		{
+			name:    "open ended",
+			pause:   models.Pause{StartDate: month(time.June)},
+			wantErr: nil,
+		},
+		{
+			name:    "whole subscription",
+			pause:   models.Pause{StartDate: month(time.February), EndDate: month(time.November)},
+			wantErr: nil,
+		},
+		{
+			name:    "end before start",
+			pause:   models.Pause{StartDate: month(time.August), EndDate: month(time.June)},
+			wantErr: ErrCmpDates,
+		},
+		{
+			name:    "before subscription start",
+			pause:   models.Pause{StartDate: month(time.January), EndDate: month(time.March)},
+			wantErr: ErrOutsidePeriod,
+		},
+		{
+			name:    "after subscription end",
+			pause:   models.Pause{StartDate: month(time.October), EndDate: month(time.December)},
+			wantErr: ErrOutsidePeriod,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sub := defaultSub()
+
+			err := ValidatePause(&sub, &test.pause)
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateResume(t *testing.T) {
+	pause := &models.Pause{StartDate: month(time.June)}
+
+	tests := []struct {
+		name    string
+		pause   *models.Pause
+		month   models.MonthDate
+		wantErr error
+	}{
+		{"resume later", pause, month(time.September), nil},
+		{"not paused", nil, month(time.September), ErrNotPaused},
+		{"pause start", pause, month(time.June), ErrResumeBeforePause},
+		{"after subscription end", pause, month(time.December), ErrOutsidePeriod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sub := defaultSub()
+
+			err := ValidateResume(&sub, test.pause, test.month)
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidate_Ended(t *testing.T) {
+	pause := &models.Pause{StartDate: month(time.June)}
+
+	for _, status := range []models.Status{models.StatusCancelled, models.StatusExpired} {
+		t.Run(string(status), func(t *testing.T) {
+			sub := defaultSub()
+			sub.Status = status
+
+			assert.Equal(t, ErrEnded, ValidatePause(&sub, &models.Pause{StartDate: month(time.June)}))
+			assert.Equal(t, ErrEnded, ValidateResume(&sub, pause, month(time.September)))
+		})
+	}
+}
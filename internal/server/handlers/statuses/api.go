@@ -0,0 +1,69 @@
+package statuses
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal          = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest        = echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrInvalidID         = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrInvalidReasonCode = echo.NewHTTPError(http.StatusBadRequest, "invalid reason_code")
+	ErrReasonRequired    = echo.NewHTTPError(http.StatusBadRequest, "reason is required for reason_code other")
+	ErrReasonTooLong     = echo.NewHTTPError(http.StatusBadRequest, "reason is too long")
+	ErrCancelBeforeStart = echo.NewHTTPError(http.StatusBadRequest, "effective date should not be before start date")
+	ErrInvalidDate       = echo.NewHTTPError(http.StatusBadRequest, "invalid date")
+	ErrDatesRequired     = echo.NewHTTPError(http.StatusBadRequest, "dates are required")
+	ErrCmpDates          = echo.NewHTTPError(http.StatusBadRequest, "end date should be after start date")
+	ErrSubNotFound       = echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+	ErrTransition        = echo.NewHTTPError(http.StatusConflict, "subscription cannot be cancelled in its status")
+)
+
+type BudgetEvaluator interface {
+	Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
+type ServerAPI struct {
+	Logger  *slog.Logger
+	DB      postgres.StatusAPI
+	Subs    postgres.SubsAPI
+	Budgets BudgetEvaluator
+}
+
+func NewServerAPI(
+	logger *slog.Logger,
+	db postgres.StatusAPI,
+	subs postgres.SubsAPI,
+	budgets BudgetEvaluator,
+) *ServerAPI {
+	return &ServerAPI{
+		Logger:  logger,
+		DB:      db,
+		Subs:    subs,
+		Budgets: budgets,
+	}
+}
+
+// evaluateBudgets refreshes the budget states of the owner of sub after it
+// was cancelled. Failures are logged only: the cancellation itself
+// succeeded.
+func (s *ServerAPI) evaluateBudgets(ctx context.Context, sub *models.Subscription) {
+	ctx = postgres.WithPrimary(ctx)
+
+	if _, err := s.Budgets.Evaluate(ctx, sub.UserID); err != nil {
+		logger.FromContext(ctx, s.Logger).ErrorContext(
+			ctx,
+			"evaluate budgets",
+			"subscription_id", sub.ID,
+			"error", err,
+		)
+	}
+}
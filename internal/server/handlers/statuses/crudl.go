@@ -0,0 +1,205 @@
+package statuses
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxReasonLen is the longest free text reason accepted for a
+// cancellation, in bytes.
+const MaxReasonLen = 500
+
+// ValidateCancel trims the reason of c and checks it against its code and
+// the effective month of c, truncated to months, against the start of
+// sub.
+func ValidateCancel(sub *models.Subscription, c *models.Cancellation) error {
+	if !c.ReasonCode.Valid() {
+		return ErrInvalidReasonCode
+	}
+
+	c.Reason = strings.TrimSpace(c.Reason)
+
+	switch {
+	case c.ReasonCode == models.CancelOther && c.Reason == "":
+		return ErrReasonRequired
+	case len(c.Reason) > MaxReasonLen:
+		return ErrReasonTooLong
+	}
+
+	if c.EffectiveDate.Month().Time.Before(sub.StartDate.Month().Time) {
+		return ErrCancelBeforeStart
+	}
+
+	return nil
+}
+
+// ValidateChurnRequest checks that both months of req are set and in
+// order.
+func ValidateChurnRequest(req *models.ChurnRequest) error {
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return ErrDatesRequired
+	}
+
+	if req.EndDate.Time.Before(req.StartDate.Time) {
+		return ErrCmpDates
+	}
+
+	return nil
+}
+
+// @Summary Cancel subscription
+// @Description Moves an active or paused subscription to cancelled at once, even when effective_date is a later month. It is still charged up to effective_date, the current month by default, unless it ends earlier.
+// @Description reason_code is one of too_expensive, not_using, switched_service, missing_features, technical_issues or other; reason is free text, required for other.
+// @Description Scheduled subscriptions cannot be cancelled, delete them instead. The cancellation is kept for churn reports.
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param cancel body statuses.CancelRequest true "Cancellation"
+// @Success 200 {object} statuses.CancellationResponse
+// @Failure 400 {object} statuses.ErrorResponse
+// @Failure 404 {object} statuses.ErrorResponse
+// @Failure 409 {object} statuses.ErrorResponse
+// @Failure 500 {object} statuses.ErrorResponse
+// @Router /subs/{id}/cancel [post]
+func (s *ServerAPI) Cancel(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	var cancellation models.Cancellation
+	if err := ctx.Bind(&cancellation); err != nil {
+		return ErrBadRequest
+	}
+
+	if cancellation.EffectiveDate.IsZero() {
+		cancellation.EffectiveDate = models.MonthOf(time.Now())
+	}
+
+	sub, err := s.Subs.Read(postgres.WithPrimary(ctx.Request().Context()), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrSubNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	if err := ValidateCancel(sub, &cancellation); err != nil {
+		return err
+	}
+
+	cancellation.ID, cancellation.SubscriptionID = uuid.Nil, sub.ID
+
+	if _, err := s.DB.Cancel(ctx.Request().Context(), &cancellation); err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrNotFound):
+			return ErrSubNotFound
+		case errors.Is(err, postgres.ErrTransition):
+			return ErrTransition
+		case errors.Is(err, postgres.ErrCancelBeforeStart):
+			return ErrCancelBeforeStart
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	s.evaluateBudgets(ctx.Request().Context(), sub)
+
+	ctx.JSON(http.StatusOK, &cancellation)
+	return nil
+}
+
+// @Summary List subscription transitions
+// @Description Returns the status history of a subscription, oldest first.
+// @Tags statuses
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Success 200 {array} statuses.TransitionResponse
+// @Failure 400 {object} statuses.ErrorResponse
+// @Failure 500 {object} statuses.ErrorResponse
+// @Router /subs/{id}/transitions [get]
+func (s *ServerAPI) Transitions(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	transitions, err := s.DB.ListTransitions(ctx.Request().Context(), id)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, transitions)
+	return nil
+}
+
+// @Summary Churn report
+// @Description Reports, for every service and month from start_date to end_date, the subscriptions running in the month,
+// @Description those cancelled with effect in it, by reason code, and their ratio. Cancelled subscriptions deleted since count in their last month only.
+// @Tags statuses
+// @Produce json
+// @Param start_date query string true "First month" example(01-2024)
+// @Param end_date query string true "Last month" example(12-2024)
+// @Param service_name query string false "Service name"
+// @Param category query string false "Category"
+// @Success 200 {array} statuses.ChurnResponse
+// @Failure 400 {object} statuses.ErrorResponse
+// @Failure 500 {object} statuses.ErrorResponse
+// @Router /subs/churn [get]
+func (s *ServerAPI) Churn(ctx echo.Context) error {
+	req := models.ChurnRequest{
+		ServiceName: ctx.QueryParam("service_name"),
+		Category:    ctx.QueryParam("category"),
+	}
+
+	var err error
+	if req.StartDate, err = models.ParseMonthDate(ctx.QueryParam("start_date")); err != nil {
+		return ErrInvalidDate
+	}
+
+	if req.EndDate, err = models.ParseMonthDate(ctx.QueryParam("end_date")); err != nil {
+		return ErrInvalidDate
+	}
+
+	if err := ValidateChurnRequest(&req); err != nil {
+		return err
+	}
+
+	churn, err := s.DB.Churn(ctx.Request().Context(), &req)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, churn)
+	return nil
+}
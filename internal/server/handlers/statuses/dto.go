@@ -0,0 +1,42 @@
+package statuses
+
+type CancelRequest struct {
+	EffectiveDate string `json:"effective_date,omitempty" example:"06-2024"`
+	ReasonCode    string `json:"reason_code"              example:"too_expensive"`
+	Reason        string `json:"reason,omitempty"         example:"price went up twice this year"`
+}
+
+type CancellationResponse struct {
+	ID             string `json:"id"              example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	SubscriptionID string `json:"subscription_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	UserID         string `json:"user_id"         example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ServiceName    string `json:"service_name"    example:"Netflix"`
+	Category       string `json:"category"        example:"entertainment"`
+	StartDate      string `json:"start_date"      example:"01-2024"`
+	EffectiveDate  string `json:"effective_date"  example:"06-2024"`
+	ReasonCode     string `json:"reason_code"     example:"too_expensive"`
+	Reason         string `json:"reason"          example:"price went up twice this year"`
+	CreatedAt      string `json:"created_at"      example:"2024-01-01T00:00:00Z"`
+}
+
+type TransitionResponse struct {
+	ID             string `json:"id"              example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	SubscriptionID string `json:"subscription_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	From           string `json:"from"            example:"active"`
+	To             string `json:"to"              example:"cancelled"`
+	Reason         string `json:"reason"          example:"too_expensive: price went up twice this year"`
+	CreatedAt      string `json:"created_at"      example:"2024-01-01T00:00:00Z"`
+}
+
+type ChurnResponse struct {
+	ServiceName string         `json:"service_name" example:"Netflix"`
+	Month       string         `json:"month"        example:"06-2024"`
+	Active      int            `json:"active"       example:"40"`
+	Cancelled   int            `json:"cancelled"    example:"2"`
+	Rate        float64        `json:"rate"         example:"0.05"`
+	Reasons     map[string]int `json:"reasons"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message" example:"error description"`
+}
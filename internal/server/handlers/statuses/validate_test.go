@@ -0,0 +1,98 @@
+package statuses
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(m time.Month) models.MonthDate {
+	return models.MonthDate{Time: time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func TestValidateCancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		c          models.Cancellation
+		wantReason string
+		wantErr    error
+	}{
+		{
+			name: "code only",
+			c:    models.Cancellation{ReasonCode: models.CancelTooExpensive, EffectiveDate: month(time.June)},
+		},
+		{
+			name:       "trimmed text",
+			c:          models.Cancellation{ReasonCode: models.CancelOther, Reason: "  moved away\n", EffectiveDate: month(time.June)},
+			wantReason: "moved away",
+		},
+		{
+			name:       "start month",
+			c:          models.Cancellation{ReasonCode: models.CancelNotUsing, Reason: "rarely", EffectiveDate: month(time.March)},
+			wantReason: "rarely",
+		},
+		{
+			name:    "missing code",
+			c:       models.Cancellation{Reason: "moved away", EffectiveDate: month(time.June)},
+			wantErr: ErrInvalidReasonCode,
+		},
+		{
+			name:    "unknown code",
+			c:       models.Cancellation{ReasonCode: "bored", EffectiveDate: month(time.June)},
+			wantErr: ErrInvalidReasonCode,
+		},
+		{
+			name:    "other without text",
+			c:       models.Cancellation{ReasonCode: models.CancelOther, Reason: " ", EffectiveDate: month(time.June)},
+			wantErr: ErrReasonRequired,
+		},
+		{
+			name: "text too long",
+			c: models.Cancellation{
+				ReasonCode: models.CancelOther, Reason: strings.Repeat("a", MaxReasonLen+1), EffectiveDate: month(time.June),
+			},
+			wantErr: ErrReasonTooLong,
+		},
+		{
+			name:    "before start",
+			c:       models.Cancellation{ReasonCode: models.CancelNotUsing, EffectiveDate: month(time.February)},
+			wantErr: ErrCancelBeforeStart,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sub := models.Subscription{Price: 1000, StartDate: month(time.March)}
+
+			err := ValidateCancel(&sub, &test.c)
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantReason, test.c.Reason)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateChurnRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.ChurnRequest
+		wantErr error
+	}{
+		{"one month", models.ChurnRequest{StartDate: month(time.May), EndDate: month(time.May)}, nil},
+		{"no end", models.ChurnRequest{StartDate: month(time.May)}, ErrDatesRequired},
+		{"reversed", models.ChurnRequest{StartDate: month(time.May), EndDate: month(time.April)}, ErrCmpDates},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.wantErr, ValidateChurnRequest(&test.req))
+		})
+	}
+}
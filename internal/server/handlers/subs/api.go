@@ -1,11 +1,16 @@
 package subs
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
 	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -13,25 +18,73 @@ var (
 	ErrInternal            = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
 	ErrBadRequest          = echo.NewHTTPError(http.StatusBadRequest, "bad request")
 	ErrNegativePrice       = echo.NewHTTPError(http.StatusBadRequest, "negative price")
+	ErrNegativeTrial       = echo.NewHTTPError(http.StatusBadRequest, "negative trial_months")
+	ErrNegativeIntro       = echo.NewHTTPError(http.StatusBadRequest, "negative intro_price or intro_months")
 	ErrStartDateRequired   = echo.NewHTTPError(http.StatusBadRequest, "start_date is required")
 	ErrDatesRequired       = echo.NewHTTPError(http.StatusBadRequest, "dates are required")
 	ErrCmpDates            = echo.NewHTTPError(http.StatusBadRequest, "end date should be after start date")
 	ErrServiceNameRequired = echo.NewHTTPError(http.StatusBadRequest, "service_name is required")
 	ErrUserIDRequired      = echo.NewHTTPError(http.StatusBadRequest, "user_id is required")
 	ErrInvalidID           = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrInvalidStatus       = echo.NewHTTPError(http.StatusBadRequest, "invalid status")
 	ErrSubNotFound         = echo.NewHTTPError(http.StatusNotFound, "subscription not found")
 )
 
+type BudgetEvaluator interface {
+	Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
 type ServerAPI struct {
-	Logger *slog.Logger
-	Config *config.Config
-	DB     postgres.SubsAPI
+	Logger  *slog.Logger
+	Config  *config.Config
+	DB      postgres.SubsAPI
+	Budgets BudgetEvaluator
 }
 
-func NewServerAPI(logger *slog.Logger, config *config.Config, db postgres.SubsAPI) *ServerAPI {
+func NewServerAPI(
+	logger *slog.Logger,
+	config *config.Config,
+	db postgres.SubsAPI,
+	budgets BudgetEvaluator,
+) *ServerAPI {
 	return &ServerAPI{
-		Logger: logger,
-		Config: config,
-		DB:     db,
+		Logger:  logger,
+		Config:  config,
+		DB:      db,
+		Budgets: budgets,
+	}
+}
+
+// evaluateBudgets refreshes the budget states of the user after a change of
+// their subscriptions. Failures are logged only: the change itself succeeded.
+// Spend is read from the primary, a replica may not have the change yet.
+func (s *ServerAPI) evaluateBudgets(ctx context.Context, userID uuid.UUID) {
+	if _, err := s.Budgets.Evaluate(postgres.WithPrimary(ctx), userID); err != nil {
+		logger.FromContext(ctx, s.Logger).ErrorContext(
+			ctx,
+			"evaluate budgets",
+			"user_id", userID,
+			"error", err,
+		)
 	}
 }
+
+// read loads the subscription from the primary for handlers that need
+// its state before they change it.
+func (s *ServerAPI) read(ctx echo.Context, id uuid.UUID) (*models.Subscription, error) {
+	sub, err := s.DB.Read(postgres.WithPrimary(ctx.Request().Context()), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil, ErrSubNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return nil, ErrInternal
+	}
+
+	return sub, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
 	"github.com/P3rCh1/subs-aggregator/internal/models"
 	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
 	"github.com/google/uuid"
@@ -15,6 +16,14 @@ func ValidateSub(sub *models.Subscription) error {
 		return ErrNegativePrice
 	}
 
+	if sub.TrialMonths < 0 {
+		return ErrNegativeTrial
+	}
+
+	if sub.IntroPrice < 0 || sub.IntroMonths < 0 {
+		return ErrNegativeIntro
+	}
+
 	if sub.StartDate.IsZero() {
 		return ErrStartDateRequired
 	}
@@ -55,13 +64,16 @@ func (s *ServerAPI) Create(ctx echo.Context) error {
 	}
 
 	if err := s.DB.Create(ctx.Request().Context(), &sub); err != nil {
-		s.Logger.Error(
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
 			"database",
 			"error", err,
 		)
 		return ErrInternal
 	}
 
+	s.evaluateBudgets(ctx.Request().Context(), sub.UserID)
+
 	ctx.JSON(http.StatusCreated, &sub)
 	return nil
 }
@@ -87,7 +99,8 @@ func (s *ServerAPI) Read(ctx echo.Context) error {
 			return ErrSubNotFound
 		}
 
-		s.Logger.Error(
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
 			"database",
 			"error", err,
 		)
@@ -99,7 +112,7 @@ func (s *ServerAPI) Read(ctx echo.Context) error {
 }
 
 // @Summary Update subscription
-// @Description Updates an existing subscription by its ID.
+// @Description Updates an existing subscription by its ID. Its status is recomputed from the new dates and pauses unless it is cancelled.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
@@ -123,19 +136,36 @@ func (s *ServerAPI) Update(ctx echo.Context) error {
 
 	sub.ID = id
 
+	if err := ValidateSub(&sub); err != nil {
+		return err
+	}
+
+	// The previous owner is needed as well: a subscription moved to
+	// another user no longer counts against their budgets.
+	old, err := s.read(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	err = s.DB.Update(ctx.Request().Context(), &sub)
 	if err != nil {
 		if errors.Is(err, postgres.ErrNotFound) {
 			return ErrSubNotFound
 		}
 
-		s.Logger.Error(
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
 			"database",
 			"error", err,
 		)
 		return ErrInternal
 	}
 
+	s.evaluateBudgets(ctx.Request().Context(), sub.UserID)
+	if old.UserID != sub.UserID {
+		s.evaluateBudgets(ctx.Request().Context(), old.UserID)
+	}
+
 	ctx.JSON(http.StatusOK, sub)
 	return nil
 }
@@ -156,28 +186,37 @@ func (s *ServerAPI) Delete(ctx echo.Context) error {
 		return ErrInvalidID
 	}
 
+	old, err := s.read(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	err = s.DB.Delete(ctx.Request().Context(), id)
 	if err != nil {
 		if errors.Is(err, postgres.ErrNotFound) {
 			return ErrSubNotFound
 		}
 
-		s.Logger.Error(
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
 			"database",
 			"error", err,
 		)
 		return ErrInternal
 	}
 
+	s.evaluateBudgets(ctx.Request().Context(), old.UserID)
+
 	ctx.Response().WriteHeader(http.StatusOK)
 	return nil
 }
 
 // @Summary List user subscriptions
-// @Description Returns all subscriptions for a specific user.
+// @Description Returns all subscriptions for a specific user, only those in status when it is given.
 // @Tags subscriptions
 // @Produce json
 // @Param id path string true "User ID (UUID)"
+// @Param status query string false "Subscription status" Enums(scheduled, active, paused, cancelled, expired)
 // @Success 200 {array} subs.SubscriptionResponse
 // @Failure 400 {object} subs.ErrorResponse
 // @Failure 500 {object} subs.ErrorResponse
@@ -188,15 +227,31 @@ func (s *ServerAPI) List(ctx echo.Context) error {
 		return ErrInvalidID
 	}
 
+	status := models.Status(ctx.QueryParam("status"))
+	if status != "" && !status.Valid() {
+		return ErrInvalidStatus
+	}
+
 	subs, err := s.DB.List(ctx.Request().Context(), id)
 	if err != nil {
-		s.Logger.Error(
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
 			"database",
 			"error", err,
 		)
 		return ErrInternal
 	}
 
+	if status != "" {
+		matched := []models.Subscription{}
+		for _, sub := range subs {
+			if sub.Status == status {
+				matched = append(matched, sub)
+			}
+		}
+		subs = matched
+	}
+
 	ctx.JSON(http.StatusOK, subs)
 	return nil
 }
@@ -1,39 +1,64 @@
 package subs
 
 type CreateSubscriptionRequest struct {
-	ServiceName string `json:"service_name"       example:"Netflix"`
-	Price       int    `json:"price,omitempty"    example:"1000"`
-	UserID      string `json:"user_id"            example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   string `json:"start_date"         example:"01-2024"`
-	EndDate     string `json:"end_date,omitempty" example:"12-2024"`
+	ServiceName string `json:"service_name"           example:"Netflix"`
+	Category    string `json:"category,omitempty"     example:"entertainment"`
+	Price       int    `json:"price,omitempty"        example:"1000"`
+	UserID      string `json:"user_id"                example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate   string `json:"start_date"             example:"01-2024"`
+	EndDate     string `json:"end_date,omitempty"     example:"12-2024"`
+	TrialMonths int    `json:"trial_months,omitempty" example:"1"`
+	IntroPrice  int    `json:"intro_price,omitempty"  example:"500"`
+	IntroMonths int    `json:"intro_months,omitempty" example:"3"`
 }
 
 type SubscriptionResponse struct {
-	ID          string `json:"id"                 example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	ServiceName string `json:"service_name"       example:"Netflix"`
-	Price       int    `json:"price,omitempty"    example:"1000"`
-	UserID      string `json:"user_id"            example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   string `json:"start_date"         example:"01-2024"`
-	EndDate     string `json:"end_date,omitempty" example:"12-2024"`
+	ID          string `json:"id"                     example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ServiceName string `json:"service_name"           example:"Netflix"`
+	Category    string `json:"category,omitempty"     example:"entertainment"`
+	Price       int    `json:"price,omitempty"        example:"1000"`
+	UserID      string `json:"user_id"                example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate   string `json:"start_date"             example:"01-2024"`
+	EndDate     string `json:"end_date,omitempty"     example:"12-2024"`
+	TrialMonths int    `json:"trial_months,omitempty" example:"1"`
+	IntroPrice  int    `json:"intro_price,omitempty"  example:"500"`
+	IntroMonths int    `json:"intro_months,omitempty" example:"3"`
+	InTrial     bool   `json:"in_trial"               example:"false"`
+	Status      string `json:"status,omitempty"       example:"active"`
 }
 
 type UpdateSubscriptionRequest struct {
-	ServiceName string `json:"service_name"       example:"Netflix Premium"`
-	Price       int    `json:"price,omitempty"    example:"1500"`
-	UserID      string `json:"user_id"            example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   string `json:"start_date"         example:"01-2024"`
-	EndDate     string `json:"end_date,omitempty" example:"12-2024"`
+	ServiceName string `json:"service_name"           example:"Netflix Premium"`
+	Category    string `json:"category,omitempty"     example:"entertainment"`
+	Price       int    `json:"price,omitempty"        example:"1500"`
+	UserID      string `json:"user_id"                example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate   string `json:"start_date"             example:"01-2024"`
+	EndDate     string `json:"end_date,omitempty"     example:"12-2024"`
+	TrialMonths int    `json:"trial_months,omitempty" example:"1"`
+	IntroPrice  int    `json:"intro_price,omitempty"  example:"500"`
+	IntroMonths int    `json:"intro_months,omitempty" example:"3"`
 }
 
 type SummaryRequest struct {
 	ServiceName string `json:"service_name,omitempty" example:"Netflix"`
+	Category    string `json:"category,omitempty"     example:"entertainment"`
 	UserID      string `json:"user_id,omitempty"      example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
 	StartDate   string `json:"start_date"             example:"01-2024"`
 	EndDate     string `json:"end_date"               example:"12-2024"`
+	Status      string `json:"status,omitempty"       example:"active"`
+	Prorate     bool   `json:"prorate,omitempty"      example:"false"`
+	Breakdown   bool   `json:"breakdown,omitempty"    example:"false"`
 }
 
 type SummaryResponse struct {
-	Summary int `json:"summary" example:"12000"`
+	Summary   int               `json:"summary"             example:"11000"`
+	Breakdown *SummaryBreakdown `json:"breakdown,omitempty"`
+}
+
+type SummaryBreakdown struct {
+	Gross    int `json:"gross"    example:"12000"`
+	Discount int `json:"discount" example:"1000"`
+	Net      int `json:"net"      example:"11000"`
 }
 
 type ErrorResponse struct {
@@ -57,9 +57,17 @@ func (m *MockDB) List(ctx context.Context, userID uuid.UUID) ([]models.Subscript
 	return args.Get(0).([]models.Subscription), args.Error(1)
 }
 
-func (m *MockDB) Summary(ctx context.Context, req *models.SumRequest) (int, error) {
+func (m *MockDB) ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserSubscription), args.Error(1)
+}
+
+func (m *MockDB) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
 	args := m.Called(ctx, req)
-	return args.Int(0), args.Error(1)
+	return args.Get(0).(models.Summary), args.Error(1)
 }
 
 func (m *MockDB) Close() error {
@@ -67,6 +75,31 @@ func (m *MockDB) Close() error {
 	return args.Error(0)
 }
 
+func (m *MockDB) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockDB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(uint), args.Bool(1), args.Error(2)
+}
+
+type nopEvaluator struct{}
+
+func (nopEvaluator) Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	return nil, nil
+}
+
+type recordingEvaluator struct {
+	users []uuid.UUID
+}
+
+func (r *recordingEvaluator) Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	r.users = append(r.users, userID)
+	return nil, nil
+}
+
 func defaultSub() models.Subscription {
 	return models.Subscription{
 		ServiceName: "Netflix",
@@ -94,12 +127,16 @@ func defaultSumRequest() models.SumRequest {
 }
 
 func setup() (*MockDB, *echo.Echo) {
+	return setupWith(nopEvaluator{})
+}
+
+func setupWith(budgets BudgetEvaluator) (*MockDB, *echo.Echo) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	cfg := &config.Config{}
 
 	mockDB := &MockDB{}
 
-	api := NewServerAPI(logger, cfg, mockDB)
+	api := NewServerAPI(logger, cfg, mockDB, budgets)
 
 	e := echo.New()
 	e.POST("/subs", api.Create)
@@ -207,6 +244,8 @@ func TestUpdate_Success(t *testing.T) {
 	sub := defaultSub()
 	sub.ServiceName = "Netflix Updated"
 
+	old := sub
+	mockDB.On("Read", mock.Anything, id).Return(&old, nil)
 	mockDB.On("Update", mock.Anything, mock.AnythingOfType("*models.Subscription")).
 		Return(nil)
 
@@ -225,8 +264,7 @@ func TestUpdate_NotFound(t *testing.T) {
 
 	id := uuid.New()
 
-	mockDB.On("Update", mock.Anything, mock.AnythingOfType("*models.Subscription")).
-		Return(postgres.ErrNotFound)
+	mockDB.On("Read", mock.Anything, id).Return(nil, postgres.ErrNotFound)
 
 	body, _ := json.Marshal(defaultSub())
 	rec := httptest.NewRecorder()
@@ -236,13 +274,56 @@ func TestUpdate_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 	mockDB.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
 }
 
-func TestDelete_Success(t *testing.T) {
+func TestUpdate_Reassign(t *testing.T) {
+	budgets := &recordingEvaluator{}
+	mockDB, e := setupWith(budgets)
+
+	id := uuid.New()
+	old := defaultSub()
+	sub := defaultSub()
+
+	mockDB.On("Read", mock.Anything, id).Return(&old, nil)
+	mockDB.On("Update", mock.Anything, mock.AnythingOfType("*models.Subscription")).
+		Return(nil)
+
+	body, _ := json.Marshal(sub)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/subs/"+id.String(), bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.ElementsMatch(t, []uuid.UUID{old.UserID, sub.UserID}, budgets.users)
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdate_Validation(t *testing.T) {
 	mockDB, e := setup()
 
+	sub := defaultSub()
+	sub.StartDate = models.MonthDate{}
+
+	body, _ := json.Marshal(sub)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/subs/"+uuid.NewString(), bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockDB.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDelete_Success(t *testing.T) {
+	budgets := &recordingEvaluator{}
+	mockDB, e := setupWith(budgets)
+
 	id := uuid.New()
+	sub := defaultSub()
 
+	mockDB.On("Read", mock.Anything, id).Return(&sub, nil)
 	mockDB.On("Delete", mock.Anything, id).Return(nil)
 
 	rec := httptest.NewRecorder()
@@ -250,6 +331,7 @@ func TestDelete_Success(t *testing.T) {
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []uuid.UUID{sub.UserID}, budgets.users)
 	mockDB.AssertExpectations(t)
 }
 
@@ -280,13 +362,44 @@ func TestList_Success(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestList_Status(t *testing.T) {
+	mockDB, e := setup()
+
+	userID := uuid.New()
+
+	subs := []models.Subscription{defaultSub(), defaultSub()}
+	subs[0].Status = models.StatusActive
+	subs[1].Status = models.StatusCancelled
+
+	mockDB.On("List", mock.Anything, userID).Return(subs, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/subs/list/"+userID.String()+"?status=cancelled", nil)
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response []models.Subscription
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, subs[1:], response)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/subs/list/"+userID.String()+"?status=gone", nil)
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockDB.AssertExpectations(t)
+}
+
 func TestSummary_Success(t *testing.T) {
 	mockDB, e := setup()
 
 	req := defaultSumRequest()
 	expectedSum := 18000
 
-	mockDB.On("Summary", mock.Anything, &req).Return(expectedSum, nil)
+	mockDB.On("Summary", mock.Anything, &req).Return(models.Undiscounted(expectedSum), nil)
 
 	body, _ := json.Marshal(req)
 	rec := httptest.NewRecorder()
@@ -300,6 +413,35 @@ func TestSummary_Success(t *testing.T) {
 	var response map[string]int
 	json.Unmarshal(rec.Body.Bytes(), &response)
 	assert.Equal(t, expectedSum, response["summary"])
+	assert.NotContains(t, rec.Body.String(), "breakdown")
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestSummary_Breakdown(t *testing.T) {
+	mockDB, e := setup()
+
+	req := defaultSumRequest()
+	sum := models.Summary{Gross: 12000, Discount: 1000, Net: 11000}
+
+	mockDB.On("Summary", mock.Anything, &req).Return(sum, nil)
+
+	body, _ := json.Marshal(struct {
+		models.SumRequest
+		Breakdown bool `json:"breakdown"`
+	}{req, true})
+	rec := httptest.NewRecorder()
+	reqHttp := httptest.NewRequest(http.MethodPost, "/subs/summary", bytes.NewReader(body))
+	reqHttp.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	e.ServeHTTP(rec, reqHttp)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response SummaryResponse
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, 11000, response.Summary)
+	assert.Equal(t, &SummaryBreakdown{Gross: 12000, Discount: 1000, Net: 11000}, response.Breakdown)
 
 	mockDB.AssertExpectations(t)
 }
@@ -327,6 +469,23 @@ func TestSummary_InvalidDates(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestSummary_InvalidStatus(t *testing.T) {
+	_, e := setup()
+
+	invalidReq := defaultSumRequest()
+	invalidReq.Status = "gone"
+
+	body, _ := json.Marshal(invalidReq)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/subs/summary", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestInternalError(t *testing.T) {
 	mockDB, e := setup()
 
@@ -3,10 +3,14 @@ package subs
 import (
 	"net/http"
 
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
 	"github.com/P3rCh1/subs-aggregator/internal/models"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/P3rCh1/subs-aggregator/internal/server/handlers/subs")
+
 func ValidateSumRequest(sr *models.SumRequest) error {
 	if sr.StartDate.IsZero() || sr.EndDate.IsZero() {
 		return ErrDatesRequired
@@ -16,12 +20,19 @@ func ValidateSumRequest(sr *models.SumRequest) error {
 		return ErrCmpDates
 	}
 
+	if sr.Status != "" && !sr.Status.Valid() {
+		return ErrInvalidStatus
+	}
+
 	return nil
 }
 
 // @Summary Calculate total payments
 // @Description Calculates the total amount spent on subscriptions within a date range.
-// @Description Both start_date and end_date are required; user_id and service_name are optional filters.
+// @Description Both start_date and end_date are required; user_id, service_name, category and status are optional filters.
+// @Description status counts only the subscriptions currently in it: scheduled, active, paused, cancelled or expired.
+// @Description Dates accept MM-YYYY, YYYY-MM, YYYY-MM-DD or RFC 3339; prorate charges partial months by the day.
+// @Description The summary is net of discounts; breakdown adds the gross, discount and net amounts.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
@@ -31,26 +42,43 @@ func ValidateSumRequest(sr *models.SumRequest) error {
 // @Failure 500 {object} subs.ErrorResponse
 // @Router /subs/summary [post]
 func (s *ServerAPI) Summary(ctx echo.Context) error {
-	var r models.SumRequest
+	_, bind := tracer.Start(ctx.Request().Context(), "subs.Summary.bind")
+
+	var r struct {
+		models.SumRequest
+		Breakdown bool `json:"breakdown"`
+	}
 	if err := ctx.Bind(&r); err != nil {
+		bind.End()
 		return ErrBadRequest
 	}
 
-	if err := ValidateSumRequest(&r); err != nil {
+	bind.End()
+
+	if err := ValidateSumRequest(&r.SumRequest); err != nil {
 		return err
 	}
 
-	sum, err := s.DB.Summary(ctx.Request().Context(), &r)
+	sum, err := s.DB.Summary(ctx.Request().Context(), &r.SumRequest)
 	if err != nil {
-		s.Logger.Error(
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
 			"database",
 			"error", err,
 		)
 		return ErrInternal
 	}
 
-	ctx.JSON(http.StatusOK, map[string]int{
-		"summary": sum,
-	})
+	resp := &SummaryResponse{Summary: sum.Net}
+
+	if r.Breakdown {
+		resp.Breakdown = &SummaryBreakdown{
+			Gross:    sum.Gross,
+			Discount: sum.Discount,
+			Net:      sum.Net,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resp)
 	return nil
 }
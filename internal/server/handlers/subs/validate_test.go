@@ -25,6 +25,21 @@ func TestValidate(t *testing.T) {
 			modifySub: func(s *models.Subscription) { s.Price = -100 },
 			wantErr:   ErrNegativePrice,
 		},
+		{
+			name:      "negative trial",
+			modifySub: func(s *models.Subscription) { s.TrialMonths = -1 },
+			wantErr:   ErrNegativeTrial,
+		},
+		{
+			name:      "negative intro price",
+			modifySub: func(s *models.Subscription) { s.IntroPrice, s.IntroMonths = -1, 3 },
+			wantErr:   ErrNegativeIntro,
+		},
+		{
+			name:      "trial with free intro months",
+			modifySub: func(s *models.Subscription) { s.TrialMonths, s.IntroMonths = 1, 2 },
+			wantErr:   nil,
+		},
 		{
 			name:      "missing service name",
 			modifySub: func(s *models.Subscription) { s.ServiceName = "" },
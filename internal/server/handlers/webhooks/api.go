@@ -0,0 +1,34 @@
+package webhooks
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrInternal         = echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	ErrBadRequest       = echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	ErrInvalidURL       = echo.NewHTTPError(http.StatusBadRequest, "url must be an absolute http(s) url")
+	ErrSecretRequired   = echo.NewHTTPError(http.StatusBadRequest, "secret is required")
+	ErrEventsRequired   = echo.NewHTTPError(http.StatusBadRequest, "events are required")
+	ErrUnknownEvent     = echo.NewHTTPError(http.StatusBadRequest, "unknown event type")
+	ErrInvalidStatus    = echo.NewHTTPError(http.StatusBadRequest, "invalid delivery status")
+	ErrInvalidID        = echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	ErrWebhookNotFound  = echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	ErrDeliveryNotFound = echo.NewHTTPError(http.StatusNotFound, "delivery not found")
+)
+
+type ServerAPI struct {
+	Logger *slog.Logger
+	DB     postgres.WebhooksAPI
+}
+
+func NewServerAPI(logger *slog.Logger, db postgres.WebhooksAPI) *ServerAPI {
+	return &ServerAPI{
+		Logger: logger,
+		DB:     db,
+	}
+}
@@ -0,0 +1,128 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func ValidateWebhook(hook *models.Webhook) error {
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	if hook.Secret == "" {
+		return ErrSecretRequired
+	}
+
+	if len(hook.Events) == 0 {
+		return ErrEventsRequired
+	}
+
+	for _, event := range hook.Events {
+		if !event.Valid() {
+			return ErrUnknownEvent
+		}
+	}
+
+	return nil
+}
+
+// @Summary Register webhook
+// @Description Registers an endpoint that receives signed subscription lifecycle events.
+// @Description Every request carries an X-Webhook-Signature header with the HMAC-SHA256 of the body keyed by the secret.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body webhooks.CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} webhooks.WebhookResponse
+// @Failure 400 {object} webhooks.ErrorResponse
+// @Failure 500 {object} webhooks.ErrorResponse
+// @Router /webhooks [post]
+func (s *ServerAPI) Create(ctx echo.Context) error {
+	var hook models.Webhook
+	if err := ctx.Bind(&hook); err != nil {
+		return ErrBadRequest
+	}
+
+	if err := ValidateWebhook(&hook); err != nil {
+		return err
+	}
+
+	if err := s.DB.CreateWebhook(ctx.Request().Context(), &hook); err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	hook.Secret = ""
+
+	ctx.JSON(http.StatusCreated, &hook)
+	return nil
+}
+
+// @Summary List webhooks
+// @Description Returns all registered webhooks. Secrets are never returned.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} webhooks.WebhookResponse
+// @Failure 500 {object} webhooks.ErrorResponse
+// @Router /webhooks [get]
+func (s *ServerAPI) List(ctx echo.Context) error {
+	hooks, err := s.DB.ListWebhooks(ctx.Request().Context())
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, hooks)
+	return nil
+}
+
+// @Summary Delete webhook
+// @Description Deletes a webhook together with its delivery history.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 "Webhook successfully deleted"
+// @Failure 400 {object} webhooks.ErrorResponse
+// @Failure 404 {object} webhooks.ErrorResponse
+// @Failure 500 {object} webhooks.ErrorResponse
+// @Router /webhooks/{id} [delete]
+func (s *ServerAPI) Delete(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	err = s.DB.DeleteWebhook(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.Response().WriteHeader(http.StatusOK)
+	return nil
+}
@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary List webhook deliveries
+// @Description Returns delivery attempts of a webhook, newest first.
+// @Description Use status=dead to inspect the dead-letter queue.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID (UUID)"
+// @Param status query string false "Delivery status" Enums(pending, delivered, dead)
+// @Success 200 {array} webhooks.DeliveryResponse
+// @Failure 400 {object} webhooks.ErrorResponse
+// @Failure 500 {object} webhooks.ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (s *ServerAPI) Deliveries(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	status := models.DeliveryStatus(ctx.QueryParam("status"))
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return ErrInvalidStatus
+	}
+
+	deliveries, err := s.DB.ListDeliveries(ctx.Request().Context(), id, status)
+	if err != nil {
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+	return nil
+}
+
+// @Summary Redeliver webhook event
+// @Description Resets a delivery to pending with a fresh attempt budget, e.g. to replay a dead letter.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID (UUID)"
+// @Success 202 "Delivery scheduled"
+// @Failure 400 {object} webhooks.ErrorResponse
+// @Failure 404 {object} webhooks.ErrorResponse
+// @Failure 500 {object} webhooks.ErrorResponse
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (s *ServerAPI) Redeliver(ctx echo.Context) error {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	err = s.DB.Redeliver(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return ErrDeliveryNotFound
+		}
+
+		logger.FromContext(ctx.Request().Context(), s.Logger).ErrorContext(
+			ctx.Request().Context(),
+			"database",
+			"error", err,
+		)
+		return ErrInternal
+	}
+
+	ctx.Response().WriteHeader(http.StatusAccepted)
+	return nil
+}
@@ -0,0 +1,31 @@
+package webhooks
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"    example:"https://crm.example.com/hooks/subs"`
+	Secret string   `json:"secret" example:"s3cr3t"`
+	Events []string `json:"events" example:"subscription.created,subscription.deleted"`
+}
+
+type WebhookResponse struct {
+	ID        string   `json:"id"         example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	URL       string   `json:"url"        example:"https://crm.example.com/hooks/subs"`
+	Events    []string `json:"events"     example:"subscription.created,subscription.deleted"`
+	CreatedAt string   `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+type DeliveryResponse struct {
+	ID            string `json:"id"              example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	WebhookID     string `json:"webhook_id"      example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	EventID       int64  `json:"event_id"        example:"42"`
+	EventType     string `json:"event_type"      example:"subscription.created"`
+	Payload       any    `json:"payload"`
+	Status        string `json:"status"          example:"dead"`
+	Attempts      int    `json:"attempts"        example:"8"`
+	NextAttemptAt string `json:"next_attempt_at" example:"2024-01-01T00:00:00Z"`
+	LastError     string `json:"last_error"      example:"unexpected status 500"`
+	CreatedAt     string `json:"created_at"      example:"2024-01-01T00:00:00Z"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message" example:"error description"`
+}
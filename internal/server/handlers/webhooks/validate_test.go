@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultWebhook() models.Webhook {
+	return models.Webhook{
+		URL:    "https://crm.example.com/hooks",
+		Secret: "secret",
+		Events: []models.EventType{models.EventSubCreated, models.EventSubDeleted},
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name          string
+		modifyWebhook func(*models.Webhook)
+		wantErr       error
+	}{
+		{
+			name:          "valid data",
+			modifyWebhook: func(w *models.Webhook) {},
+			wantErr:       nil,
+		},
+		{
+			name:          "relative url",
+			modifyWebhook: func(w *models.Webhook) { w.URL = "/hooks" },
+			wantErr:       ErrInvalidURL,
+		},
+		{
+			name:          "unsupported scheme",
+			modifyWebhook: func(w *models.Webhook) { w.URL = "ftp://crm.example.com/hooks" },
+			wantErr:       ErrInvalidURL,
+		},
+		{
+			name:          "missing secret",
+			modifyWebhook: func(w *models.Webhook) { w.Secret = "" },
+			wantErr:       ErrSecretRequired,
+		},
+		{
+			name:          "missing events",
+			modifyWebhook: func(w *models.Webhook) { w.Events = nil },
+			wantErr:       ErrEventsRequired,
+		},
+		{
+			name: "unknown event",
+			modifyWebhook: func(w *models.Webhook) {
+				w.Events = append(w.Events, "subscription.renamed")
+			},
+			wantErr: ErrUnknownEvent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hook := defaultWebhook()
+			test.modifyWebhook(&hook)
+
+			err := ValidateWebhook(&hook)
+
+			if test.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.wantErr, err)
+			}
+		})
+	}
+}
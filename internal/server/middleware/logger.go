@@ -4,10 +4,11 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
 	"github.com/labstack/echo/v4"
 )
 
-func Logger(logger *slog.Logger) echo.MiddlewareFunc {
+func Logger(base *slog.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
 			start := time.Now()
@@ -29,7 +30,7 @@ func Logger(logger *slog.Logger) echo.MiddlewareFunc {
 				attributes = append(attributes, "error", err.Error())
 			}
 
-			logger.Info("request completed", attributes...)
+			logger.FromContext(ctx.Request().Context(), base).InfoContext(ctx.Request().Context(), "request completed", attributes...)
 
 			return err
 		}
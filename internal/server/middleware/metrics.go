@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+func Metrics(m *metrics.Metrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+
+			err := next(ctx)
+
+			route := ctx.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			labels := []string{route, ctx.Request().Method, strconv.Itoa(status(ctx, err))}
+
+			m.HTTPRequests.WithLabelValues(labels...).Inc()
+			m.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// status returns the code the client receives: handler errors are written
+// by the echo error handler only after the middleware chain returns.
+func status(ctx echo.Context, err error) int {
+	if err == nil {
+		return ctx.Response().Status
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderReadPrimary lets a client that has just written ask for reads from
+// the primary instead of a possibly lagging replica.
+const HeaderReadPrimary = "X-Read-Primary"
+
+func ReadPrimary() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if primary, _ := strconv.ParseBool(ctx.Request().Header.Get(HeaderReadPrimary)); primary {
+				req := ctx.Request()
+				ctx.SetRequest(req.WithContext(postgres.WithPrimary(req.Context())))
+			}
+
+			return next(ctx)
+		}
+	}
+}
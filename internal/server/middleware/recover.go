@@ -6,15 +6,17 @@ import (
 
 	"log/slog"
 
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
 	"github.com/labstack/echo/v4"
 )
 
-func Recover(logger *slog.Logger) echo.MiddlewareFunc {
+func Recover(base *slog.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			defer func() {
 				if r := recover(); r != nil {
-					logger.Error(
+					logger.FromContext(c.Request().Context(), base).ErrorContext(
+						c.Request().Context(),
 						"panic recovered",
 						"error", r,
 						"path", c.Path(),
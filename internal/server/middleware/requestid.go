@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderUserID is set by the gateway in front of the service to the id of
+// the authenticated user.
+const HeaderUserID = "X-User-ID"
+
+const maxRequestIDLen = 128
+
+// RequestID accepts the X-Request-ID of the caller or generates a new one,
+// echoes it in the response and stores a request-scoped logger carrying
+// request_id, route and user in the request context.
+func RequestID(base *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+
+			ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			route := ctx.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			attributes := []any{
+				"request_id", id,
+				"route", route,
+			}
+
+			if user, err := uuid.Parse(req.Header.Get(HeaderUserID)); err == nil {
+				attributes = append(attributes, "user", user)
+			}
+
+			ctx.SetRequest(req.WithContext(logger.WithContext(req.Context(), base.With(attributes...))))
+
+			return next(ctx)
+		}
+	}
+}
+
+// validRequestID rejects ids that are empty, too long or contain anything
+// but printable ASCII, so a caller cannot forge log lines through them.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
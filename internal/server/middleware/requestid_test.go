@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		header   string
+		user     string
+		wantID   string
+		wantUser any
+	}{
+		{name: "accepted", header: "req-42", user: userID.String(), wantID: "req-42", wantUser: userID.String()},
+		{name: "generated", header: ""},
+		{name: "forged line rejected", header: "id\nlevel=ERROR", user: "admin"},
+		{name: "too long rejected", header: strings.Repeat("x", 129)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			base := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			router := echo.New()
+			router.Use(RequestID(base))
+			router.GET("/subs/:id", func(ctx echo.Context) error {
+				logger.FromContext(ctx.Request().Context(), nil).Error("database")
+				return ctx.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/subs/1", nil)
+			req.Header.Set(echo.HeaderXRequestID, test.header)
+			req.Header.Set(HeaderUserID, test.user)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(echo.HeaderXRequestID)
+			if test.wantID != "" {
+				assert.Equal(t, test.wantID, id)
+			} else {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			}
+
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			assert.Equal(t, id, record["request_id"])
+			assert.Equal(t, "/subs/:id", record["route"])
+			assert.Equal(t, test.wantUser, record["user"])
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	subsv1 "github.com/P3rCh1/subs-aggregator/pkg/api/subs/v1"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toProto(sub *models.Subscription) *subsv1.Subscription {
+	return &subsv1.Subscription{
+		Id:          sub.ID.String(),
+		ServiceName: sub.ServiceName,
+		Category:    sub.Category,
+		Price:       int64(sub.Price),
+		UserId:      sub.UserID.String(),
+		StartDate:   sub.StartDate.String(),
+		EndDate:     sub.EndDate.String(),
+		TrialMonths: int64(sub.TrialMonths),
+		IntroPrice:  int64(sub.IntroPrice),
+		IntroMonths: int64(sub.IntroMonths),
+		InTrial:     sub.InTrial(time.Now()),
+		Status:      string(sub.Status),
+	}
+}
+
+func fromProto(pb *subsv1.Subscription) (*models.Subscription, error) {
+	if pb == nil {
+		return nil, status.Error(codes.InvalidArgument, "subscription is required")
+	}
+
+	sub := &models.Subscription{
+		ServiceName: pb.ServiceName,
+		Category:    pb.Category,
+		Price:       int(pb.Price),
+		TrialMonths: int(pb.TrialMonths),
+		IntroPrice:  int(pb.IntroPrice),
+		IntroMonths: int(pb.IntroMonths),
+	}
+
+	var err error
+	if sub.UserID, err = parseOptionalID(pb.UserId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	if sub.StartDate, err = models.ParseMonthDate(pb.StartDate); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if sub.EndDate, err = models.ParseMonthDate(pb.EndDate); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return sub, nil
+}
+
+func fromProtoSummary(pb *subsv1.SummaryRequest) (*models.SumRequest, error) {
+	req := &models.SumRequest{
+		ServiceName: pb.ServiceName,
+		Category:    pb.Category,
+		Prorate:     pb.Prorate,
+		Status:      models.Status(pb.Status),
+	}
+
+	var err error
+	if req.UserID, err = parseOptionalID(pb.UserId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	if req.StartDate, err = models.ParseMonthDate(pb.StartDate); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.EndDate, err = models.ParseMonthDate(pb.EndDate); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return req, nil
+}
+
+func parseOptionalID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/subs"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	subsv1 "github.com/P3rCh1/subs-aggregator/pkg/api/subs/v1"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInternal    = status.Error(codes.Internal, "internal server error")
+	ErrInvalidID   = status.Error(codes.InvalidArgument, "invalid id")
+	ErrSubNotFound = status.Error(codes.NotFound, "subscription not found")
+)
+
+type Server struct {
+	subsv1.UnimplementedSubscriptionServiceServer
+
+	Logger  *slog.Logger
+	DB      postgres.SubsAPI
+	Budgets subs.BudgetEvaluator
+}
+
+func NewServer(logger *slog.Logger, db postgres.SubsAPI, budgets subs.BudgetEvaluator) *Server {
+	return &Server{
+		Logger:  logger,
+		DB:      db,
+		Budgets: budgets,
+	}
+}
+
+// Register creates a grpc.Server with the subscription, health and
+// reflection services. The returned health server lets the caller flip
+// the serving status during shutdown.
+func Register(server *Server, opts ...grpc.ServerOption) (*grpc.Server, *health.Server) {
+	opts = append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(readPrimary)}, opts...)
+
+	grpcServer := grpc.NewServer(opts...)
+	healthServer := health.NewServer()
+
+	subsv1.RegisterSubscriptionServiceServer(grpcServer, server)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	healthServer.SetServingStatus(subsv1.SubscriptionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return grpcServer, healthServer
+}
+
+// MetadataReadPrimary is the gRPC counterpart of the X-Read-Primary header.
+const MetadataReadPrimary = "x-read-primary"
+
+// readPrimary routes the reads of a call to the primary when the client
+// sets MetadataReadPrimary to true.
+func readPrimary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataReadPrimary); len(values) > 0 {
+		if primary, _ := strconv.ParseBool(values[0]); primary {
+			ctx = postgres.WithPrimary(ctx)
+		}
+	}
+
+	return handler(ctx, req)
+}
+
+// fromHTTPError converts validation errors shared with the REST handlers.
+func fromHTTPError(err error) error {
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		return ErrInternal
+	}
+
+	msg, _ := httpErr.Message.(string)
+
+	switch httpErr.Code {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, msg)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, msg)
+	default:
+		return ErrInternal
+	}
+}
+
+func (s *Server) databaseError(err error) error {
+	if errors.Is(err, postgres.ErrNotFound) {
+		return ErrSubNotFound
+	}
+
+	s.Logger.Error(
+		"database",
+		"error", err,
+	)
+	return ErrInternal
+}
+
+func (s *Server) evaluateBudgets(ctx context.Context, userID uuid.UUID) {
+	if _, err := s.Budgets.Evaluate(postgres.WithPrimary(ctx), userID); err != nil {
+		s.Logger.Error(
+			"evaluate budgets",
+			"user_id", userID,
+			"error", err,
+		)
+	}
+}
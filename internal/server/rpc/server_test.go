@@ -0,0 +1,254 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	subsv1 "github.com/P3rCh1/subs-aggregator/pkg/api/subs/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeDB struct {
+	mu   sync.Mutex
+	subs map[uuid.UUID]models.Subscription
+	sum  models.Summary
+}
+
+func (f *fakeDB) Close() error { return nil }
+
+func (f *fakeDB) Create(ctx context.Context, sub *models.Subscription) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub.ID = uuid.New()
+	f.subs[sub.ID] = *sub
+	return nil
+}
+
+func (f *fakeDB) Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub, ok := f.subs[id]
+	if !ok {
+		return nil, postgres.ErrNotFound
+	}
+	return &sub, nil
+}
+
+func (f *fakeDB) Update(ctx context.Context, sub *models.Subscription) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subs[sub.ID]; !ok {
+		return postgres.ErrNotFound
+	}
+	f.subs[sub.ID] = *sub
+	return nil
+}
+
+func (f *fakeDB) Delete(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subs[id]; !ok {
+		return postgres.ErrNotFound
+	}
+	delete(f.subs, id)
+	return nil
+}
+
+func (f *fakeDB) List(ctx context.Context, userID uuid.UUID) ([]models.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	subs := []models.Subscription{}
+	for _, sub := range f.subs {
+		if sub.UserID == userID {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (f *fakeDB) ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error) {
+	subs := []models.UserSubscription{}
+	for _, userID := range userIDs {
+		list, _ := f.List(ctx, userID)
+		for _, sub := range list {
+			subs = append(subs, models.UserSubscription{Subscription: sub})
+		}
+	}
+	return subs, nil
+}
+
+func (f *fakeDB) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	return f.sum, nil
+}
+
+func (f *fakeDB) Ping(ctx context.Context) error { return nil }
+
+func (f *fakeDB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return postgres.SchemaVersion, false, nil
+}
+
+type nopEvaluator struct{}
+
+func (nopEvaluator) Evaluate(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	return nil, nil
+}
+
+func setup(t *testing.T) (*fakeDB, *grpc.ClientConn) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db := &fakeDB{subs: map[uuid.UUID]models.Subscription{}}
+
+	server, _ := Register(NewServer(logger, db, nopEvaluator{}))
+	listener := bufconn.Listen(1 << 20)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return db, conn
+}
+
+func defaultSub() *subsv1.Subscription {
+	return &subsv1.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserId:      uuid.NewString(),
+		StartDate:   "01-2024",
+	}
+}
+
+func TestCRUDL(t *testing.T) {
+	_, conn := setup(t)
+	client := subsv1.NewSubscriptionServiceClient(conn)
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, &subsv1.CreateRequest{Subscription: defaultSub()})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Id)
+	assert.Equal(t, "01-2024", created.StartDate)
+	assert.Empty(t, created.EndDate)
+
+	read, err := client.Read(ctx, &subsv1.ReadRequest{Id: created.Id})
+	require.NoError(t, err)
+	assert.Equal(t, created.ServiceName, read.ServiceName)
+
+	read.ServiceName = "Netflix Premium"
+	read.EndDate = "12-2024"
+	updated, err := client.Update(ctx, &subsv1.UpdateRequest{Subscription: read})
+	require.NoError(t, err)
+	assert.Equal(t, "Netflix Premium", updated.ServiceName)
+	assert.Equal(t, "12-2024", updated.EndDate)
+
+	list, err := client.List(ctx, &subsv1.ListRequest{UserId: created.UserId})
+	require.NoError(t, err)
+	assert.Len(t, list.Subscriptions, 1)
+
+	_, err = client.Delete(ctx, &subsv1.DeleteRequest{Id: created.Id})
+	require.NoError(t, err)
+
+	_, err = client.Read(ctx, &subsv1.ReadRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreate_Validation(t *testing.T) {
+	_, conn := setup(t)
+	client := subsv1.NewSubscriptionServiceClient(conn)
+
+	sub := defaultSub()
+	sub.Price = -1
+
+	_, err := client.Create(context.Background(), &subsv1.CreateRequest{Subscription: sub})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "negative price", status.Convert(err).Message())
+
+	sub = defaultSub()
+	sub.StartDate = "01/2024"
+
+	_, err = client.Create(context.Background(), &subsv1.CreateRequest{Subscription: sub})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestList_Status(t *testing.T) {
+	db, conn := setup(t)
+	client := subsv1.NewSubscriptionServiceClient(conn)
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, &subsv1.CreateRequest{Subscription: defaultSub()})
+	require.NoError(t, err)
+
+	id := uuid.MustParse(created.Id)
+	sub := db.subs[id]
+	sub.Status = models.StatusPaused
+	db.subs[id] = sub
+
+	list, err := client.List(ctx, &subsv1.ListRequest{UserId: created.UserId, Status: "paused"})
+	require.NoError(t, err)
+	require.Len(t, list.Subscriptions, 1)
+	assert.Equal(t, "paused", list.Subscriptions[0].Status)
+
+	list, err = client.List(ctx, &subsv1.ListRequest{UserId: created.UserId, Status: "active"})
+	require.NoError(t, err)
+	assert.Empty(t, list.Subscriptions)
+
+	_, err = client.List(ctx, &subsv1.ListRequest{UserId: created.UserId, Status: "gone"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSummary(t *testing.T) {
+	db, conn := setup(t)
+	client := subsv1.NewSubscriptionServiceClient(conn)
+	db.sum = models.Summary{Gross: 20000, Discount: 2000, Net: 18000}
+
+	resp, err := client.Summary(context.Background(), &subsv1.SummaryRequest{
+		StartDate: "01-2024",
+		EndDate:   "12-2024",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(18000), resp.Summary)
+	assert.Equal(t, int64(20000), resp.Gross)
+	assert.Equal(t, int64(2000), resp.Discount)
+
+	_, err = client.Summary(context.Background(), &subsv1.SummaryRequest{
+		StartDate: "12-2024",
+		EndDate:   "01-2024",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHealth(t *testing.T) {
+	_, conn := setup(t)
+	client := healthpb.NewHealthClient(conn)
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: subsv1.SubscriptionService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/server/handlers/subs"
+	subsv1 "github.com/P3rCh1/subs-aggregator/pkg/api/subs/v1"
+	"github.com/google/uuid"
+)
+
+func (s *Server) Create(ctx context.Context, req *subsv1.CreateRequest) (*subsv1.Subscription, error) {
+	sub, err := fromProto(req.Subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subs.ValidateSub(sub); err != nil {
+		return nil, fromHTTPError(err)
+	}
+
+	if err := s.DB.Create(ctx, sub); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	s.evaluateBudgets(ctx, sub.UserID)
+
+	return toProto(sub), nil
+}
+
+func (s *Server) Read(ctx context.Context, req *subsv1.ReadRequest) (*subsv1.Subscription, error) {
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	sub, err := s.DB.Read(ctx, id)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	return toProto(sub), nil
+}
+
+func (s *Server) Update(ctx context.Context, req *subsv1.UpdateRequest) (*subsv1.Subscription, error) {
+	sub, err := fromProto(req.Subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.ID, err = uuid.Parse(req.Subscription.Id); err != nil {
+		return nil, ErrInvalidID
+	}
+
+	if err := subs.ValidateSub(sub); err != nil {
+		return nil, fromHTTPError(err)
+	}
+
+	if err := s.DB.Update(ctx, sub); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	s.evaluateBudgets(ctx, sub.UserID)
+
+	return toProto(sub), nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *subsv1.DeleteRequest) (*subsv1.DeleteResponse, error) {
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	if err := s.DB.Delete(ctx, id); err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	return &subsv1.DeleteResponse{}, nil
+}
+
+func (s *Server) List(ctx context.Context, req *subsv1.ListRequest) (*subsv1.ListResponse, error) {
+	id, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	st := models.Status(req.Status)
+	if st != "" && !st.Valid() {
+		return nil, fromHTTPError(subs.ErrInvalidStatus)
+	}
+
+	list, err := s.DB.List(ctx, id)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	resp := &subsv1.ListResponse{
+		Subscriptions: make([]*subsv1.Subscription, 0, len(list)),
+	}
+
+	for i := range list {
+		if st != "" && list[i].Status != st {
+			continue
+		}
+		resp.Subscriptions = append(resp.Subscriptions, toProto(&list[i]))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) Summary(ctx context.Context, req *subsv1.SummaryRequest) (*subsv1.SummaryResponse, error) {
+	sumReq, err := fromProtoSummary(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subs.ValidateSumRequest(sumReq); err != nil {
+		return nil, fromHTTPError(err)
+	}
+
+	sum, err := s.DB.Summary(ctx, sumReq)
+	if err != nil {
+		return nil, s.databaseError(err)
+	}
+
+	return &subsv1.SummaryResponse{
+		Summary:  int64(sum.Net),
+		Gross:    int64(sum.Gross),
+		Discount: int64(sum.Discount),
+	}, nil
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+// subsDB keeps subscriptions in a map. It is meant for demos and tests:
+// nothing survives a restart and no outbox events are written. Nothing
+// moves statuses along either, so they are worked out on every read.
+type subsDB struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]models.Subscription
+}
+
+func NewSubsAPI() postgres.SubsAPI {
+	return &subsDB{subs: map[uuid.UUID]models.Subscription{}}
+}
+
+func (s *subsDB) Close() error {
+	return nil
+}
+
+func (s *subsDB) Create(ctx context.Context, sub *models.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub.ID = uuid.New()
+	sub.Status = sub.StatusAt(models.MonthOf(time.Now()), nil)
+	s.subs[sub.ID] = *sub
+
+	return nil
+}
+
+func (s *subsDB) Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sub, ok := s.subs[id]
+	if !ok {
+		return nil, postgres.ErrNotFound
+	}
+
+	sub = current(sub, models.MonthOf(time.Now()))
+	return &sub, nil
+}
+
+func (s *subsDB) Update(ctx context.Context, sub *models.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.subs[sub.ID]
+	if !ok {
+		return postgres.ErrNotFound
+	}
+
+	sub.Status = old.Status
+	sub.Status = sub.Restatus(models.MonthOf(time.Now()), nil)
+	s.subs[sub.ID] = *sub
+
+	return nil
+}
+
+func (s *subsDB) Delete(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[id]; !ok {
+		return postgres.ErrNotFound
+	}
+
+	delete(s.subs, id)
+
+	return nil
+}
+
+func (s *subsDB) List(ctx context.Context, userID uuid.UUID) ([]models.Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	month := models.MonthOf(time.Now())
+
+	subs := []models.Subscription{}
+	for _, sub := range s.subs {
+		if sub.UserID == userID {
+			subs = append(subs, current(sub, month))
+		}
+	}
+
+	return subs, nil
+}
+
+// ListUsers lists owned subscriptions without discounts, pauses and
+// members: they live in PostgreSQL only.
+func (s *subsDB) ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	month := models.MonthOf(time.Now())
+
+	subs := []models.UserSubscription{}
+	for _, sub := range s.subs {
+		if wanted[sub.UserID] {
+			subs = append(subs, models.UserSubscription{Subscription: current(sub, month), Payer: sub.UserID})
+		}
+	}
+
+	return subs, nil
+}
+
+// Summary applies the same filters as the SQL backends. Discounts live in
+// PostgreSQL only, so the total is never discounted.
+func (s *subsDB) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	month := models.MonthOf(time.Now())
+
+	var total models.Summary
+
+	for _, sub := range s.subs {
+		if !sub.Period().Overlaps(req.Period()) {
+			continue
+		}
+
+		if req.ServiceName != "" && sub.ServiceName != req.ServiceName {
+			continue
+		}
+
+		if req.Category != "" && sub.Category != req.Category {
+			continue
+		}
+
+		if req.UserID != uuid.Nil && sub.UserID != req.UserID {
+			continue
+		}
+
+		if req.Status != "" && current(sub, month).Status != req.Status {
+			continue
+		}
+
+		total = total.Add(sub.Charge(req, nil, nil))
+	}
+
+	return total, nil
+}
+
+// current returns sub with the status it has in month: a cancellation
+// stands, any other status follows its dates.
+func current(sub models.Subscription, month models.MonthDate) models.Subscription {
+	sub.Status = sub.Restatus(month, nil)
+	return sub
+}
+
+func (s *subsDB) Ping(ctx context.Context) error {
+	return nil
+}
+
+// MigrationVersion reports the expected version: there is no schema to
+// migrate.
+func (s *subsDB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return postgres.SchemaVersion, false, nil
+}
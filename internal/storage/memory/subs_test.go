@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsAPI(t *testing.T) {
+	storagetest.RunSubsAPI(t, func(t *testing.T) postgres.SubsAPI {
+		return NewSubsAPI()
+	})
+}
+
+func TestSubsAPI_CurrentStatus(t *testing.T) {
+	ctx := context.Background()
+	db := NewSubsAPI().(*subsDB)
+
+	// Stored as active when it was created, it ended in 2020.
+	expired := models.Subscription{
+		ID: uuid.New(), ServiceName: "Netflix", Price: 1000, UserID: uuid.New(),
+		StartDate: storagetest.Month(2020, time.January), EndDate: storagetest.Month(2020, time.June), Status: models.StatusActive,
+	}
+	cancelled := expired
+	cancelled.ID, cancelled.Status = uuid.New(), models.StatusCancelled
+	db.subs[expired.ID], db.subs[cancelled.ID] = expired, cancelled
+
+	read, err := db.Read(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusExpired, read.Status)
+
+	read, err = db.Read(ctx, cancelled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, read.Status, "a cancellation stands")
+
+	list, err := db.List(ctx, expired.UserID)
+	require.NoError(t, err)
+	statuses := map[uuid.UUID]models.Status{}
+	for _, sub := range list {
+		statuses[sub.ID] = sub.Status
+	}
+	assert.Equal(t, map[uuid.UUID]models.Status{
+		expired.ID:   models.StatusExpired,
+		cancelled.ID: models.StatusCancelled,
+	}, statuses)
+
+	req := &models.SumRequest{StartDate: storagetest.Month(2020, time.January), EndDate: storagetest.Month(2020, time.December)}
+
+	req.Status = models.StatusActive
+	sum, err := db.Summary(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, sum.Net)
+
+	req.Status = models.StatusExpired
+	sum, err = db.Summary(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 6000, sum.Net)
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type BudgetsAPI interface {
+	CreateBudget(ctx context.Context, budget *models.Budget) error
+	ReadBudget(ctx context.Context, id uuid.UUID) (*models.Budget, error)
+	UpdateBudget(ctx context.Context, budget *models.Budget) error
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+	ListBudgets(ctx context.Context, userID uuid.UUID) ([]models.Budget, error)
+	SetBudgetState(ctx context.Context, id uuid.UUID, state models.BudgetState) error
+}
+
+type budgetsDB struct {
+	db *sqlx.DB
+}
+
+func NewBudgetsAPI(db *sqlx.DB) BudgetsAPI {
+	return &budgetsDB{db}
+}
+
+func (b *budgetsDB) CreateBudget(ctx context.Context, budget *models.Budget) error {
+	const query = `
+		INSERT INTO budgets (user_id, service_name, category, monthly_limit)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, state, created_at
+	`
+
+	if err := b.db.QueryRowContext(
+		ctx,
+		query,
+		budget.UserID, budget.ServiceName, budget.Category, budget.Limit,
+	).Scan(&budget.ID, &budget.State, &budget.CreatedAt); err != nil {
+		return fmt.Errorf("insert budget fail: %w", err)
+	}
+
+	return nil
+}
+
+func (b *budgetsDB) ReadBudget(ctx context.Context, id uuid.UUID) (*models.Budget, error) {
+	const query = `
+		SELECT * FROM budgets
+		WHERE id = $1
+	`
+
+	var budget models.Budget
+	if err := b.db.GetContext(ctx, &budget, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("read budget fail: %w", err)
+	}
+
+	return &budget, nil
+}
+
+func (b *budgetsDB) UpdateBudget(ctx context.Context, budget *models.Budget) error {
+	const query = `
+		UPDATE budgets
+		SET user_id = $1, service_name = $2, category = $3, monthly_limit = $4
+		WHERE id = $5
+		RETURNING state, created_at
+	`
+
+	if err := b.db.QueryRowContext(
+		ctx,
+		query,
+		budget.UserID, budget.ServiceName, budget.Category, budget.Limit, budget.ID,
+	).Scan(&budget.State, &budget.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("update budget fail: %w", err)
+	}
+
+	return nil
+}
+
+func (b *budgetsDB) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		DELETE FROM budgets WHERE id = $1
+	`
+
+	res, err := b.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete budget fail: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (b *budgetsDB) ListBudgets(ctx context.Context, userID uuid.UUID) ([]models.Budget, error) {
+	const query = `
+		SELECT * FROM budgets
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	budgets := []models.Budget{}
+	if err := b.db.SelectContext(ctx, &budgets, query, userID); err != nil {
+		return nil, fmt.Errorf("list budgets fail: %w", err)
+	}
+
+	return budgets, nil
+}
+
+func (b *budgetsDB) SetBudgetState(ctx context.Context, id uuid.UUID, state models.BudgetState) error {
+	const query = `
+		UPDATE budgets SET state = $1
+		WHERE id = $2
+	`
+
+	if _, err := b.db.ExecContext(ctx, query, state, id); err != nil {
+		return fmt.Errorf("set budget state fail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+)
+
+// churnQuery counts, per service and month between $1 and $2, the
+// subscriptions running and those cancelled with effect in that month, by
+// reason code. Cancelled subscriptions deleted since run, as their
+// cancellations record, from their start to their effective month. The
+// first %s holds the filters on subscriptions, the other two the same
+// filters on cancellations.
+const churnQuery = `
+	WITH months AS (
+		SELECT m::date AS month
+		FROM generate_series($1::timestamp, $2::timestamp, INTERVAL '1 month') m
+	),
+	running AS (
+		SELECT service_name, month, count(*) AS active
+		FROM (
+			SELECT service_name, month
+			FROM months
+			JOIN subscriptions ON period @> month
+			WHERE %s
+			UNION ALL
+			SELECT service_name, month
+			FROM months
+			JOIN subscription_cancellations c ON month BETWEEN c.start_date AND c.effective_date
+			WHERE NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.id = c.subscription_id) AND %s
+		) r
+		GROUP BY service_name, month
+	),
+	cancelled AS (
+		SELECT service_name, effective_date AS month, reason_code, count(*) AS cancelled
+		FROM subscription_cancellations c
+		WHERE %s
+		GROUP BY service_name, effective_date, reason_code
+	)
+	SELECT
+		COALESCE(r.service_name, c.service_name) AS service_name,
+		COALESCE(r.month, c.month) AS month,
+		COALESCE(r.active, 0) AS active,
+		COALESCE(c.reason_code, '') AS reason_code,
+		COALESCE(c.cancelled, 0) AS cancelled
+	FROM running r
+	FULL JOIN cancelled c ON c.service_name = r.service_name AND c.month = r.month
+	ORDER BY service_name, month, reason_code
+`
+
+func (s *statusDB) Churn(ctx context.Context, req *models.ChurnRequest) ([]models.Churn, error) {
+	from, to := req.StartDate.Month().Time, req.EndDate.Month().Time
+
+	conds, args := summaryFilters(nil, []any{from, to}, &models.SumRequest{
+		ServiceName: req.ServiceName,
+		Category:    req.Category,
+	})
+
+	query := fmt.Sprintf(
+		churnQuery,
+		strings.Join(append([]string{"TRUE"}, conds...), " AND "),
+		strings.Join(append([]string{"TRUE"}, conds...), " AND "),
+		strings.Join(append([]string{"effective_date BETWEEN $1 AND $2"}, conds...), " AND "),
+	)
+
+	var rows []struct {
+		ServiceName string              `db:"service_name"`
+		Month       models.MonthDate    `db:"month"`
+		Active      int                 `db:"active"`
+		ReasonCode  models.CancelReason `db:"reason_code"`
+		Cancelled   int                 `db:"cancelled"`
+	}
+	if err := s.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("churn fail: %w", err)
+	}
+
+	// Rows come one per reason code, ordered by service and month.
+	churn := []models.Churn{}
+	for _, row := range rows {
+		last := len(churn) - 1
+		if last < 0 || churn[last].ServiceName != row.ServiceName || !churn[last].Month.Time.Equal(row.Month.Time) {
+			churn = append(churn, models.Churn{
+				ServiceName: row.ServiceName,
+				Month:       row.Month,
+				Active:      row.Active,
+				Reasons:     map[models.CancelReason]int{},
+			})
+			last++
+		}
+
+		if row.ReasonCode == "" {
+			continue
+		}
+
+		c := &churn[last]
+		c.Cancelled += row.Cancelled
+		c.Reasons[row.ReasonCode] += row.Cancelled
+	}
+
+	for i := range churn {
+		if churn[i].Active > 0 {
+			churn[i].Rate = float64(churn[i].Cancelled) / float64(churn[i].Active)
+		}
+	}
+
+	return churn, nil
+}
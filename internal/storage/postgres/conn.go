@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// Open connects to PostgreSQL and configures the pool. While the database
+// is not reachable yet it retries with exponential backoff until
+// cfg.StartupTimeout runs out, logging every failed attempt to base.
+func Open(ctx context.Context, base *slog.Logger, cfg *config.Postgres) (*sqlx.DB, error) {
+	db, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.StartupTimeout)
+	defer cancel()
+
+	err = retry(ctx, cfg.RetryBackoff, cfg.RetryMaxBackoff, func() error {
+		err := db.PingContext(ctx)
+		if err != nil {
+			logger.FromContext(ctx, base).WarnContext(
+				ctx,
+				"postgres is not ready",
+				"error", err,
+			)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping postgres fail: %w", err)
+	}
+
+	return db, nil
+}
+
+// open creates the pool without connecting.
+func open(cfg *config.Postgres) (*sqlx.DB, error) {
+	db, err := sqlx.Open("postgres", dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("open postgres fail: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return db, nil
+}
+
+// retry calls fn until it succeeds or ctx is done, sleeping base, 2*base,
+// ... up to limit between attempts. It returns the last error of fn.
+func retry(ctx context.Context, base, limit time.Duration, fn func() error) error {
+	delay := base
+
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > limit {
+			delay = limit
+		}
+	}
+}
+
+func dsn(cfg *config.Postgres) string {
+	params := []string{
+		"host=" + quote(cfg.Host),
+		"port=" + quote(cfg.Port),
+		"user=" + quote(cfg.User),
+		"password=" + quote(cfg.Password),
+		"dbname=" + quote(cfg.DB),
+		"sslmode=" + quote(cfg.SSLMode),
+	}
+
+	if cfg.ApplicationName != "" {
+		params = append(params, "application_name="+quote(cfg.ApplicationName))
+	}
+
+	// Unknown keys are sent to the server as run-time parameters.
+	if cfg.StatementTimeout > 0 {
+		params = append(params, fmt.Sprintf("statement_timeout=%d", cfg.StatementTimeout.Milliseconds()))
+	}
+
+	return strings.Join(params, " ")
+}
+
+// quote escapes a connection string value as described in the libpq docs.
+func quote(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + value + "'"
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDSN(t *testing.T) {
+	cfg := &config.Postgres{
+		Host:             "postgres",
+		Port:             "5432",
+		User:             "subs",
+		Password:         `it's a \secret`,
+		DB:               "subs",
+		SSLMode:          "disable",
+		ApplicationName:  "subs aggregator",
+		StatementTimeout: 30 * time.Second,
+	}
+
+	assert.Equal(t,
+		`host='postgres' port='5432' user='subs' password='it\'s a \\secret' dbname='subs' sslmode='disable' `+
+			`application_name='subs aggregator' statement_timeout=30000`,
+		dsn(cfg),
+	)
+
+	cfg.ApplicationName = ""
+	cfg.StatementTimeout = 0
+	assert.NotContains(t, dsn(cfg), "application_name")
+	assert.NotContains(t, dsn(cfg), "statement_timeout")
+}
+
+func TestRetry(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		attempts := 0
+		err := retry(context.Background(), time.Millisecond, 2*time.Millisecond, func() error {
+			attempts++
+			if attempts < 3 {
+				return errDown
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up at deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		attempts := 0
+		err := retry(ctx, time.Millisecond, 4*time.Millisecond, func() error {
+			attempts++
+			return errDown
+		})
+
+		assert.ErrorIs(t, err, errDown)
+		assert.Greater(t, attempts, 1)
+	})
+}
@@ -6,79 +6,104 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
-	"github.com/P3rCh1/subs-aggregator/internal/config"
 	"github.com/P3rCh1/subs-aggregator/internal/models"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var ErrNotFound = errors.New("not found")
 
+// subColumns are the columns of models.Subscription. Queries list them
+// instead of using * so that columns without a field, such as the
+// generated period, do not break scanning.
+const subColumns = "id, service_name, category, price, user_id, start_date, end_date, trial_months, intro_price, intro_months, status"
+
 type SubsAPI interface {
 	io.Closer
 	Create(ctx context.Context, sub *models.Subscription) error
 	Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error)
 	Update(ctx context.Context, sub *models.Subscription) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// List returns the subscriptions userID owns or is a member of.
 	List(ctx context.Context, userID uuid.UUID) ([]models.Subscription, error)
-	Summary(ctx context.Context, req *models.SumRequest) (int, error)
+	// ListUsers returns the subscriptions every user of userIDs owns or is
+	// a member of, once per such user, with their discounts, pauses and
+	// members.
+	ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error)
+	Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error)
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
 }
 
 type subsDB struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	reads *ReplicaSet
 }
 
-func NewSubsAPI(cfg *config.Postgres) (SubsAPI, error) {
-	info := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DB, cfg.SSLMode,
-	)
-
-	db, err := sqlx.Connect("postgres", info)
-	if err != nil {
-		return nil, fmt.Errorf("connect postgres fail: %w", err)
-	}
+func NewSubsAPI(db *sqlx.DB) SubsAPI {
+	return NewReplicatedSubsAPI(db, NewReplicaSet(db, nil))
+}
 
-	if err := db.Ping(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("ping postgres fail: %w", err)
+// NewReplicatedSubsAPI sends writes to primary and Read, List, ListUsers
+// and Summary to the replica chosen by reads.
+func NewReplicatedSubsAPI(primary *sqlx.DB, reads *ReplicaSet) SubsAPI {
+	return &subsDB{
+		db:    primary,
+		reads: reads,
 	}
-
-	return &subsDB{db}, nil
 }
 
 func (s *subsDB) Close() error {
 	return s.db.Close()
 }
 
-func (s *subsDB) Create(ctx context.Context, sub *models.Subscription) error {
-	const query = `
-		INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id
-	`
+// insertQuery inserts a subscription and returns its ID.
+const insertQuery = `
+	INSERT INTO subscriptions (
+		service_name, category, price, user_id, start_date, end_date, trial_months, intro_price, intro_months,
+		status
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id
+`
 
-	if err := s.db.QueryRowContext(
-		ctx,
-		query,
-		sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
-	).Scan(&sub.ID); err != nil {
-		return fmt.Errorf("insert sub fail: %w", err)
-	}
+func (s *subsDB) Create(ctx context.Context, sub *models.Subscription) (err error) {
+	ctx, span := startSpan(ctx, "Create", insertQuery)
+	rows := 0
+	defer func() { endSpan(span, rows, err) }()
 
-	return nil
+	sub.Status = sub.StatusAt(models.MonthOf(time.Now()), nil)
+
+	return withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if err := tx.QueryRowContext(
+			ctx,
+			insertQuery,
+			sub.ServiceName, sub.Category, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+			sub.TrialMonths, sub.IntroPrice, sub.IntroMonths, sub.Status,
+		).Scan(&sub.ID); err != nil {
+			return fmt.Errorf("insert sub fail: %w", err)
+		}
+
+		rows = 1
+		return writeEvent(ctx, tx, models.EventSubCreated, sub)
+	})
 }
 
-func (s *subsDB) Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+func (s *subsDB) Read(ctx context.Context, id uuid.UUID) (_ *models.Subscription, err error) {
 	const query = `
-		SELECT * FROM subscriptions
+		SELECT ` + subColumns + ` FROM subscriptions
 		WHERE id = $1
 	`
 
+	ctx, span := startSpan(ctx, "Read", query)
+	rows := 0
+	defer func() { endSpan(span, rows, err) }()
+
 	var sub models.Subscription
-	if err := s.db.GetContext(ctx, &sub, query, id); err != nil {
+	if err := s.reads.Reader(ctx).GetContext(ctx, &sub, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
@@ -86,70 +111,210 @@ func (s *subsDB) Read(ctx context.Context, id uuid.UUID) (*models.Subscription,
 		return nil, fmt.Errorf("read sub fail: %w", err)
 	}
 
+	rows = 1
 	return &sub, nil
 }
 
-func (s *subsDB) Update(ctx context.Context, sub *models.Subscription) error {
-	const query = `
+const (
+	// updateQuery writes the fields of a subscription and its status.
+	updateQuery = `
 		UPDATE subscriptions
-		SET service_name = $1, price = $2, user_id = $3, start_date = $4, end_date = $5
-		WHERE id = $6
+		SET service_name = $1, category = $2, price = $3, user_id = $4, start_date = $5, end_date = $6,
+			trial_months = $7, intro_price = $8, intro_months = $9, status = $10
+		WHERE id = $11
 	`
 
-	res, err := s.db.ExecContext(
-		ctx,
-		query,
-		sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate, sub.ID,
-	)
+	// lockStatus locks subscription $1 for an update and returns the
+	// status it has before it.
+	lockStatus = `
+		SELECT status FROM subscriptions
+		WHERE id = $1
+		FOR UPDATE
+	`
 
-	if err != nil {
-		return fmt.Errorf("update sub fail: %w", err)
-	}
+	// subPauses lists the pauses of subscription $1 for recomputing its
+	// status.
+	subPauses = `
+		SELECT ` + pauseColumns + ` FROM subscription_pauses
+		WHERE subscription_id = $1
+	`
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("database error: %w", err)
-	}
+	// updateReason is the reason of the transitions made by edits.
+	updateReason = "subscription updated"
+)
 
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+// Update recomputes the status of sub from its new dates, so that e.g. an
+// expired subscription whose end date moves to the future is active again.
+func (s *subsDB) Update(ctx context.Context, sub *models.Subscription) (err error) {
+	ctx, span := startSpan(ctx, "Update", updateQuery)
+	rows := 0
+	defer func() { endSpan(span, rows, err) }()
+
+	return withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &sub.Status, lockStatus, sub.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("lock sub fail: %w", err)
+		}
+
+		pauses := []models.Pause{}
+		if err := tx.SelectContext(ctx, &pauses, subPauses, sub.ID); err != nil {
+			return fmt.Errorf("list pauses fail: %w", err)
+		}
+
+		from := sub.Status
+		sub.Status = sub.Restatus(models.MonthOf(time.Now()), pauses)
+
+		// A shared subscription keeps its owner among the members.
+		if _, err := tx.ExecContext(ctx, moveOwner, sub.ID, sub.UserID); err != nil {
+			return fmt.Errorf("move owner fail: %w", err)
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			updateQuery,
+			sub.ServiceName, sub.Category, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+			sub.TrialMonths, sub.IntroPrice, sub.IntroMonths, sub.Status, sub.ID,
+		); err != nil {
+			return fmt.Errorf("update sub fail: %w", err)
+		}
+
+		rows = 1
+
+		if sub.Status != from {
+			if _, err := recordTransition(ctx, tx, sub.ID, from, sub.Status, updateReason); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, unshare, sub.ID); err != nil {
+			return fmt.Errorf("unshare sub fail: %w", err)
+		}
 
-	return nil
+		return writeEvent(ctx, tx, models.EventSubUpdated, sub)
+	})
 }
 
-func (s *subsDB) Delete(ctx context.Context, id uuid.UUID) error {
+func (s *subsDB) Delete(ctx context.Context, id uuid.UUID) (err error) {
 	const query = `
 		DELETE FROM subscriptions WHERE id = $1
-	`
+		RETURNING ` + subColumns
 
-	res, err := s.db.ExecContext(ctx, query, id)
+	ctx, span := startSpan(ctx, "Delete", query)
+	rows := 0
+	defer func() { endSpan(span, rows, err) }()
 
-	if err != nil {
-		return fmt.Errorf("delete sub fail: %w", err)
-	}
+	return withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		var sub models.Subscription
+		if err := tx.GetContext(ctx, &sub, query, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("database error: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+			return fmt.Errorf("delete sub fail: %w", err)
+		}
 
-	return nil
+		rows = 1
+		return writeEvent(ctx, tx, models.EventSubDeleted, &sub)
+	})
 }
 
-func (s *subsDB) List(ctx context.Context, userID uuid.UUID) ([]models.Subscription, error) {
+func (s *subsDB) List(ctx context.Context, userID uuid.UUID) (_ []models.Subscription, err error) {
 	const query = `
-		SELECT * FROM subscriptions
+		SELECT ` + subColumns + ` FROM subscriptions
 		WHERE user_id = $1
+			OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = $1)
 	`
+
 	subs := []models.Subscription{}
-	if err := s.db.SelectContext(ctx, &subs, query, userID); err != nil {
+
+	ctx, span := startSpan(ctx, "List", query)
+	defer func() { endSpan(span, len(subs), err) }()
+
+	if err := s.reads.Reader(ctx).SelectContext(ctx, &subs, query, userID); err != nil {
 		return nil, fmt.Errorf("list subs fail: %w", err)
 	}
 
 	return subs, nil
 }
+
+func (s *subsDB) ListUsers(ctx context.Context, userIDs []uuid.UUID) (_ []models.UserSubscription, err error) {
+	const query = `
+		SELECT ` + subColumns + `, user_id AS payer FROM subscriptions
+		WHERE user_id = ANY($1)
+		UNION ALL
+		SELECT ` + subColumns + `, m.payer FROM subscriptions
+		JOIN (SELECT subscription_id, user_id AS payer FROM subscription_members) m
+			ON m.subscription_id = subscriptions.id
+		WHERE m.payer = ANY($1) AND m.payer <> subscriptions.user_id
+	`
+
+	subs := []models.UserSubscription{}
+
+	ctx, span := startSpan(ctx, "ListUsers", query)
+	defer func() { endSpan(span, len(subs), err) }()
+
+	reader := s.reads.Reader(ctx)
+
+	if err := reader.SelectContext(ctx, &subs, query, pq.Array(userIDs)); err != nil {
+		return nil, fmt.Errorf("list users subs fail: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(subs))
+	for i := range subs {
+		ids[i] = subs[i].ID
+	}
+
+	var discounts []models.Discount
+	if err := reader.SelectContext(ctx, &discounts, listDiscountsQuery, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("list users discounts fail: %w", err)
+	}
+
+	var pauses []models.Pause
+	if err := reader.SelectContext(ctx, &pauses, listPausesQuery, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("list users pauses fail: %w", err)
+	}
+
+	var members []models.Member
+	if err := reader.SelectContext(ctx, &members, summaryMembersQuery, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("list users members fail: %w", err)
+	}
+
+	discountsBySub := bySub(discounts, func(d models.Discount) uuid.UUID { return d.SubscriptionID })
+	pausesBySub := bySub(pauses, func(p models.Pause) uuid.UUID { return p.SubscriptionID })
+	membersBySub := bySub(members, func(m models.Member) uuid.UUID { return m.SubscriptionID })
+
+	for i := range subs {
+		subs[i].Discounts = discountsBySub[subs[i].ID]
+		subs[i].Pauses = pausesBySub[subs[i].ID]
+		subs[i].Members = membersBySub[subs[i].ID]
+	}
+
+	return subs, nil
+}
+
+// listDiscountsQuery selects the discounts of the subscriptions $1.
+const listDiscountsQuery = `
+	SELECT ` + discountColumns + `
+	FROM discounts
+	WHERE subscription_id = ANY($1)
+`
+
+// listPausesQuery selects the pauses of the subscriptions $1.
+const listPausesQuery = `
+	SELECT ` + pauseColumns + `
+	FROM subscription_pauses
+	WHERE subscription_id = ANY($1)
+`
+
+// bySub groups items by the subscription key returns for them.
+func bySub[T any](items []T, key func(T) uuid.UUID) map[uuid.UUID][]T {
+	grouped := make(map[uuid.UUID][]T)
+	for _, item := range items {
+		grouped[key(item)] = append(grouped[key(item)], item)
+	}
+
+	return grouped
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// discountColumns lists the columns of models.Discount, leaving out the
+// generated period.
+const discountColumns = "id, subscription_id, kind, amount, start_date, end_date, created_at"
+
+type DiscountsAPI interface {
+	// CreateDiscount returns ErrNotFound when the subscription of discount
+	// does not exist.
+	CreateDiscount(ctx context.Context, discount *models.Discount) error
+	ReadDiscount(ctx context.Context, id uuid.UUID) (*models.Discount, error)
+	// UpdateDiscount changes the kind, amount and dates of a discount. Its
+	// subscription never changes.
+	UpdateDiscount(ctx context.Context, discount *models.Discount) error
+	DeleteDiscount(ctx context.Context, id uuid.UUID) error
+	ListDiscounts(ctx context.Context, subID uuid.UUID) ([]models.Discount, error)
+}
+
+type discountsDB struct {
+	db *sqlx.DB
+}
+
+func NewDiscountsAPI(db *sqlx.DB) DiscountsAPI {
+	return &discountsDB{db}
+}
+
+func (d *discountsDB) CreateDiscount(ctx context.Context, discount *models.Discount) error {
+	const query = `
+		INSERT INTO discounts (subscription_id, kind, amount, start_date, end_date)
+		SELECT id, $2::varchar, $3::integer, $4::date, $5::date FROM subscriptions
+		WHERE id = $1
+		RETURNING id, created_at
+	`
+
+	if err := d.db.QueryRowContext(
+		ctx,
+		query,
+		discount.SubscriptionID, discount.Kind, discount.Amount, discount.StartDate, discount.EndDate,
+	).Scan(&discount.ID, &discount.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("insert discount fail: %w", err)
+	}
+
+	return nil
+}
+
+func (d *discountsDB) ReadDiscount(ctx context.Context, id uuid.UUID) (*models.Discount, error) {
+	const query = `
+		SELECT ` + discountColumns + ` FROM discounts
+		WHERE id = $1
+	`
+
+	var discount models.Discount
+	if err := d.db.GetContext(ctx, &discount, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("read discount fail: %w", err)
+	}
+
+	return &discount, nil
+}
+
+func (d *discountsDB) UpdateDiscount(ctx context.Context, discount *models.Discount) error {
+	const query = `
+		UPDATE discounts
+		SET kind = $1, amount = $2, start_date = $3, end_date = $4
+		WHERE id = $5
+		RETURNING subscription_id, created_at
+	`
+
+	if err := d.db.QueryRowContext(
+		ctx,
+		query,
+		discount.Kind, discount.Amount, discount.StartDate, discount.EndDate, discount.ID,
+	).Scan(&discount.SubscriptionID, &discount.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("update discount fail: %w", err)
+	}
+
+	return nil
+}
+
+func (d *discountsDB) DeleteDiscount(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		DELETE FROM discounts WHERE id = $1
+	`
+
+	res, err := d.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete discount fail: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (d *discountsDB) ListDiscounts(ctx context.Context, subID uuid.UUID) ([]models.Discount, error) {
+	const query = `
+		SELECT ` + discountColumns + ` FROM discounts
+		WHERE subscription_id = $1
+		ORDER BY start_date, created_at
+	`
+
+	discounts := []models.Discount{}
+	if err := d.db.SelectContext(ctx, &discounts, query, subID); err != nil {
+		return nil, fmt.Errorf("list discounts fail: %w", err)
+	}
+
+	return discounts, nil
+}
@@ -0,0 +1,119 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountsAPI(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewDiscountsAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	discount := &models.Discount{
+		SubscriptionID: sub.ID,
+		Kind:           models.DiscountPercent,
+		Amount:         20,
+		StartDate:      storagetest.Month(2024, time.March),
+	}
+	require.NoError(t, db.CreateDiscount(ctx, discount))
+	assert.NotEqual(t, uuid.Nil, discount.ID)
+
+	read, err := db.ReadDiscount(ctx, discount.ID)
+	require.NoError(t, err)
+	assert.Equal(t, discount.Kind, read.Kind)
+	assert.Equal(t, discount.StartDate, read.StartDate)
+	assert.False(t, read.EndDate.Valid)
+
+	discount.Kind, discount.Amount = models.DiscountFixed, 300
+	discount.EndDate = storagetest.Month(2024, time.May)
+	discount.SubscriptionID = uuid.New()
+	require.NoError(t, db.UpdateDiscount(ctx, discount))
+	assert.Equal(t, sub.ID, discount.SubscriptionID, "subscription must not change")
+
+	list, err := db.ListDiscounts(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 300, list[0].Amount)
+
+	err = db.CreateDiscount(ctx, &models.Discount{
+		SubscriptionID: uuid.New(), Kind: models.DiscountFixed, Amount: 1, StartDate: sub.StartDate,
+	})
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+
+	require.NoError(t, subs.Delete(ctx, sub.ID))
+	_, err = db.ReadDiscount(ctx, discount.ID)
+	assert.ErrorIs(t, err, postgres.ErrNotFound, "discounts go with their subscription")
+	assert.ErrorIs(t, db.DeleteDiscount(ctx, discount.ID), postgres.ErrNotFound)
+}
+
+func TestSummary_Discounts(t *testing.T) {
+	conn := pgtest.DB(t)
+	ctx := context.Background()
+	subs := postgres.NewSubsAPI(conn)
+	discounts := postgres.NewDiscountsAPI(conn)
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Day(2024, time.January, 16),
+		TrialMonths: 1,
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	all := []models.Discount{
+		{Kind: models.DiscountPercent, Amount: 50, StartDate: storagetest.Month(2024, time.January), EndDate: storagetest.Month(2024, time.April)},
+		{Kind: models.DiscountFixed, Amount: 300, StartDate: storagetest.Month(2024, time.March)},
+		{Kind: models.DiscountFixed, Amount: 400, StartDate: storagetest.Month(2024, time.April), EndDate: storagetest.Month(2024, time.April)},
+	}
+	for i := range all {
+		all[i].SubscriptionID = sub.ID
+		require.NoError(t, discounts.CreateDiscount(ctx, &all[i]))
+	}
+
+	check := func(t *testing.T, db postgres.SubsAPI) {
+		for _, prorate := range []bool{false, true} {
+			req := &models.SumRequest{
+				StartDate: storagetest.Month(2024, time.January),
+				EndDate:   storagetest.Month(2024, time.December),
+				Prorate:   prorate,
+			}
+
+			got, err := db.Summary(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, sub.Charge(req, all, nil), got, "prorate %v", prorate)
+		}
+	}
+
+	// Whole months: 0, 500, 800, 1000 and 300 for the rest of the year.
+	want := models.Summary{Gross: 11000, Discount: 500 + 800 + 1000 + 8*300}
+	want.Net = want.Gross - want.Discount
+	assert.Equal(t, want, sub.Charge(&models.SumRequest{
+		StartDate: storagetest.Month(2024, time.January),
+		EndDate:   storagetest.Month(2024, time.December),
+	}, all, nil))
+
+	t.Run("raw", func(t *testing.T) { check(t, subs) })
+
+	require.NoError(t, postgres.NewRollupAPI(conn).Refresh(ctx, horizon))
+	t.Run("rollup", func(t *testing.T) { check(t, subs) })
+}
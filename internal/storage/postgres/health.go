@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SchemaVersion is the migration version this binary expects. Bump it
+// together with every new file in migrations.
+const SchemaVersion uint = 15
+
+func (s *subsDB) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping fail: %w", err)
+	}
+
+	return nil
+}
+
+// MigrationVersion reads the version recorded by golang-migrate. dirty is
+// true when the last migration failed halfway. A database that was never
+// migrated reports version 0.
+func (s *subsDB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	const query = `
+		SELECT version, dirty FROM schema_migrations
+		LIMIT 1
+	`
+
+	var (
+		version uint
+		dirty   bool
+	)
+
+	if err := s.db.QueryRowContext(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("read migration version fail: %w", err)
+	}
+
+	return version, dirty, nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TryAdvisoryLock takes a session level advisory lock on a dedicated
+// connection. It reports false without blocking when another session
+// already holds key. The returned release func unlocks and frees the connection.
+func TryAdvisoryLock(ctx context.Context, db *sqlx.DB, key int64) (func(), bool, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("get connection fail: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("advisory lock fail: %w", err)
+	}
+
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}
+
+	return release, true, nil
+}
+
+// AdvisoryLock waits for a session level advisory lock on a dedicated
+// connection until ctx is done. The returned release func unlocks and
+// frees the connection.
+func AdvisoryLock(ctx context.Context, db *sqlx.DB, key int64) (func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get connection fail: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("advisory lock fail: %w", err)
+	}
+
+	release := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}
+
+	return release, nil
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type MembersAPI interface {
+	// SetMember adds member to its subscription or changes its weight.
+	// Sharing a subscription for the first time adds its owner with
+	// weight 1 too. It returns ErrNotFound when the subscription does not
+	// exist.
+	SetMember(ctx context.Context, member *models.Member) error
+	// RemoveMember removes a member. Removing the last member but the
+	// owner makes the subscription unshared again.
+	RemoveMember(ctx context.Context, subID, userID uuid.UUID) error
+	ListMembers(ctx context.Context, subID uuid.UUID) ([]models.Member, error)
+}
+
+const (
+	// moveOwner hands the member row of the owner of subscription $1 to
+	// the user $2 taking it over. A row $2 had as a member already is kept.
+	moveOwner = `
+		WITH old AS (
+			DELETE FROM subscription_members m
+			USING subscriptions s
+			WHERE s.id = $1 AND m.subscription_id = s.id AND m.user_id = s.user_id AND s.user_id <> $2
+			RETURNING m.subscription_id, m.weight, m.created_at
+		)
+		INSERT INTO subscription_members (subscription_id, user_id, weight, created_at)
+		SELECT subscription_id, $2, weight, created_at FROM old
+		ON CONFLICT (subscription_id, user_id) DO NOTHING
+	`
+
+	// unshare drops the owner of subscription $1 once nobody else is left.
+	unshare = `
+		DELETE FROM subscription_members m
+		USING subscriptions s
+		WHERE m.subscription_id = $1 AND s.id = m.subscription_id AND m.user_id = s.user_id
+			AND NOT EXISTS (
+				SELECT 1 FROM subscription_members o
+				WHERE o.subscription_id = $1 AND o.user_id <> s.user_id
+			)
+	`
+)
+
+type membersDB struct {
+	db *sqlx.DB
+}
+
+func NewMembersAPI(db *sqlx.DB) MembersAPI {
+	return &membersDB{db}
+}
+
+func (m *membersDB) SetMember(ctx context.Context, member *models.Member) error {
+	const query = `
+		WITH sub AS (
+			SELECT id, user_id FROM subscriptions
+			WHERE id = $1
+		), owner AS (
+			INSERT INTO subscription_members (subscription_id, user_id)
+			SELECT id, user_id FROM sub
+			WHERE user_id <> $2
+			ON CONFLICT DO NOTHING
+		)
+		INSERT INTO subscription_members (subscription_id, user_id, weight)
+		SELECT id, $2, $3::integer FROM sub
+		ON CONFLICT (subscription_id, user_id) DO UPDATE SET weight = EXCLUDED.weight
+		RETURNING created_at
+	`
+
+	if err := m.db.QueryRowContext(
+		ctx,
+		query,
+		member.SubscriptionID, member.UserID, member.Weight,
+	).Scan(&member.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("set member fail: %w", err)
+	}
+
+	return nil
+}
+
+func (m *membersDB) RemoveMember(ctx context.Context, subID, userID uuid.UUID) error {
+	const remove = `
+		DELETE FROM subscription_members
+		WHERE subscription_id = $1 AND user_id = $2
+	`
+
+	return withTx(ctx, m.db, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, remove, subID, userID)
+		if err != nil {
+			return fmt.Errorf("remove member fail: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("database error: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, unshare, subID); err != nil {
+			return fmt.Errorf("unshare sub fail: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (m *membersDB) ListMembers(ctx context.Context, subID uuid.UUID) ([]models.Member, error) {
+	const query = `
+		SELECT subscription_id, user_id, weight, created_at FROM subscription_members
+		WHERE subscription_id = $1
+		ORDER BY created_at, user_id
+	`
+
+	members := []models.Member{}
+	if err := m.db.SelectContext(ctx, &members, query, subID); err != nil {
+		return nil, fmt.Errorf("list members fail: %w", err)
+	}
+
+	return members, nil
+}
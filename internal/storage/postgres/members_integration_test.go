@@ -0,0 +1,153 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMembersAPI(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewMembersAPI(conn)
+	ctx := context.Background()
+
+	owner, member := uuid.New(), uuid.New()
+
+	sub := &models.Subscription{
+		ServiceName: "Spotify",
+		Price:       300,
+		UserID:      owner,
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	require.NoError(t, db.SetMember(ctx, &models.Member{SubscriptionID: sub.ID, UserID: member, Weight: 1}))
+	require.NoError(t, db.SetMember(ctx, &models.Member{SubscriptionID: sub.ID, UserID: member, Weight: 2}))
+
+	list, err := db.ListMembers(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2, "sharing adds the owner")
+	weights := map[uuid.UUID]int{list[0].UserID: list[0].Weight, list[1].UserID: list[1].Weight}
+	assert.Equal(t, map[uuid.UUID]int{owner: 1, member: 2}, weights)
+
+	shared, err := subs.List(ctx, member)
+	require.NoError(t, err)
+	require.Len(t, shared, 1, "members see shared subscriptions")
+	assert.Equal(t, sub.ID, shared[0].ID)
+
+	err = db.SetMember(ctx, &models.Member{SubscriptionID: uuid.New(), UserID: member, Weight: 1})
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+
+	require.NoError(t, db.RemoveMember(ctx, sub.ID, member))
+	list, err = db.ListMembers(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list, "the owner leaves with the last member")
+	assert.ErrorIs(t, db.RemoveMember(ctx, sub.ID, member), postgres.ErrNotFound)
+
+	require.NoError(t, db.SetMember(ctx, &models.Member{SubscriptionID: sub.ID, UserID: member, Weight: 1}))
+	require.NoError(t, subs.Delete(ctx, sub.ID))
+	list, err = db.ListMembers(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list, "members go with their subscription")
+}
+
+func TestMembersAPI_Reassign(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewMembersAPI(conn)
+	ctx := context.Background()
+
+	owner, member, buyer := uuid.New(), uuid.New(), uuid.New()
+
+	sub := &models.Subscription{
+		ServiceName: "Spotify",
+		Price:       300,
+		UserID:      owner,
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+	require.NoError(t, db.SetMember(ctx, &models.Member{SubscriptionID: sub.ID, UserID: member, Weight: 2}))
+
+	weights := func() map[uuid.UUID]int {
+		list, err := db.ListMembers(ctx, sub.ID)
+		require.NoError(t, err)
+
+		got := map[uuid.UUID]int{}
+		for _, m := range list {
+			got[m.UserID] = m.Weight
+		}
+		return got
+	}
+
+	sub.UserID = buyer
+	require.NoError(t, subs.Update(ctx, sub))
+	assert.Equal(t, map[uuid.UUID]int{buyer: 1, member: 2}, weights(), "the owner's row moves to the new owner")
+
+	sub.UserID = member
+	require.NoError(t, subs.Update(ctx, sub))
+	assert.Empty(t, weights(), "a member taking over is left alone with it")
+}
+
+func TestSummary_Members(t *testing.T) {
+	conn := pgtest.DB(t)
+	ctx := context.Background()
+	subs := postgres.NewSubsAPI(conn)
+	members := postgres.NewMembersAPI(conn)
+	discounts := postgres.NewDiscountsAPI(conn)
+
+	owner, member, other := uuid.New(), uuid.New(), uuid.New()
+
+	family := &models.Subscription{
+		ServiceName: "Spotify",
+		Price:       300,
+		UserID:      owner,
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	own := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      owner,
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, family))
+	require.NoError(t, subs.Create(ctx, own))
+
+	require.NoError(t, members.SetMember(ctx, &models.Member{SubscriptionID: family.ID, UserID: member, Weight: 2}))
+	require.NoError(t, discounts.CreateDiscount(ctx, &models.Discount{
+		SubscriptionID: family.ID, Kind: models.DiscountFixed, Amount: 30, StartDate: family.StartDate,
+	}))
+
+	check := func(t *testing.T, db postgres.SubsAPI) {
+		for _, prorate := range []bool{false, true} {
+			summary := func(user uuid.UUID) models.Summary {
+				got, err := db.Summary(ctx, &models.SumRequest{
+					UserID:    user,
+					StartDate: storagetest.Month(2024, time.January),
+					EndDate:   storagetest.Month(2024, time.December),
+					Prorate:   prorate,
+				})
+				require.NoError(t, err)
+				return got
+			}
+
+			// The family plan costs 3600 - 360 a year, a third of it is the owner's.
+			assert.Equal(t, models.Summary{Gross: 1200 + 12000, Discount: 120, Net: 1080 + 12000}, summary(owner), "prorate %v", prorate)
+			assert.Equal(t, models.Summary{Gross: 2400, Discount: 240, Net: 2160}, summary(member), "prorate %v", prorate)
+			assert.Equal(t, models.Summary{}, summary(other), "prorate %v", prorate)
+		}
+	}
+
+	t.Run("raw", func(t *testing.T) { check(t, subs) })
+
+	require.NoError(t, postgres.NewRollupAPI(conn).Refresh(ctx, horizon))
+	t.Run("rollup", func(t *testing.T) { check(t, subs) })
+}
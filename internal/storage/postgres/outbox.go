@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+func withTx(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx fail: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logger.FromContext(ctx, slog.Default()).ErrorContext(
+				ctx,
+				"rollback tx",
+				"error", rbErr,
+			)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx fail: %w", err)
+	}
+
+	return nil
+}
+
+func writeEvent(ctx context.Context, tx sqlx.ExecerContext, eventType models.EventType, sub *models.Subscription) error {
+	const query = `
+		INSERT INTO outbox (event_type, payload)
+		VALUES ($1, $2)
+	`
+
+	payload, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("marshal event payload fail: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, eventType, payload); err != nil {
+		return fmt.Errorf("write outbox event fail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrPauseOverlap is returned when a subscription is already paused in
+// some month of a new or changed pause.
+var ErrPauseOverlap = errors.New("pause overlaps another one")
+
+// pauseColumns lists the columns of models.Pause, leaving out the generated
+// period.
+const pauseColumns = "id, subscription_id, start_date, end_date, created_at"
+
+type PausesAPI interface {
+	// CreatePause returns ErrNotFound when the subscription of pause does
+	// not exist and ErrPauseOverlap when it is paused in any month of
+	// pause already.
+	CreatePause(ctx context.Context, pause *models.Pause) error
+	// UpdatePause changes the dates of a pause, checked for overlaps like
+	// in CreatePause. Its subscription never changes.
+	UpdatePause(ctx context.Context, pause *models.Pause) error
+	ListPauses(ctx context.Context, subID uuid.UUID) ([]models.Pause, error)
+}
+
+type pausesDB struct {
+	db *sqlx.DB
+}
+
+func NewPausesAPI(db *sqlx.DB) PausesAPI {
+	return &pausesDB{db}
+}
+
+func (p *pausesDB) CreatePause(ctx context.Context, pause *models.Pause) error {
+	const (
+		lock = `
+			SELECT id FROM subscriptions
+			WHERE id = $1
+			FOR UPDATE
+		`
+		insert = `
+			INSERT INTO subscription_pauses (subscription_id, start_date, end_date)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at
+		`
+	)
+
+	return withTx(ctx, p.db, func(tx *sqlx.Tx) error {
+		var subID uuid.UUID
+		if err := tx.GetContext(ctx, &subID, lock, pause.SubscriptionID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("lock sub fail: %w", err)
+		}
+
+		if err := checkPauseOverlap(ctx, tx, pause); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(
+			ctx,
+			insert,
+			pause.SubscriptionID, pause.StartDate, pause.EndDate,
+		).Scan(&pause.ID, &pause.CreatedAt); err != nil {
+			return fmt.Errorf("insert pause fail: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pausesDB) UpdatePause(ctx context.Context, pause *models.Pause) error {
+	const (
+		lock = `
+			SELECT p.subscription_id FROM subscription_pauses p
+			JOIN subscriptions s ON s.id = p.subscription_id
+			WHERE p.id = $1
+			FOR UPDATE OF s
+		`
+		update = `
+			UPDATE subscription_pauses
+			SET start_date = $1, end_date = $2
+			WHERE id = $3
+			RETURNING created_at
+		`
+	)
+
+	return withTx(ctx, p.db, func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &pause.SubscriptionID, lock, pause.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("lock sub fail: %w", err)
+		}
+
+		if err := checkPauseOverlap(ctx, tx, pause); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(
+			ctx,
+			update,
+			pause.StartDate, pause.EndDate, pause.ID,
+		).Scan(&pause.CreatedAt); err != nil {
+			return fmt.Errorf("update pause fail: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// checkPauseOverlap returns ErrPauseOverlap when another pause of the
+// subscription of pause shares a month with it. The subscription row must
+// be locked, so that concurrent pauses are checked one after another.
+func checkPauseOverlap(ctx context.Context, tx *sqlx.Tx, pause *models.Pause) error {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM subscription_pauses
+			WHERE subscription_id = $1 AND id <> $2 AND period && $3::daterange
+		)
+	`
+
+	var overlaps bool
+	if err := tx.GetContext(ctx, &overlaps, query, pause.SubscriptionID, pause.ID, pause.Period()); err != nil {
+		return fmt.Errorf("check pause overlap fail: %w", err)
+	}
+
+	if overlaps {
+		return ErrPauseOverlap
+	}
+
+	return nil
+}
+
+func (p *pausesDB) ListPauses(ctx context.Context, subID uuid.UUID) ([]models.Pause, error) {
+	const query = `
+		SELECT ` + pauseColumns + ` FROM subscription_pauses
+		WHERE subscription_id = $1
+		ORDER BY start_date
+	`
+
+	pauses := []models.Pause{}
+	if err := p.db.SelectContext(ctx, &pauses, query, subID); err != nil {
+		return nil, fmt.Errorf("list pauses fail: %w", err)
+	}
+
+	return pauses, nil
+}
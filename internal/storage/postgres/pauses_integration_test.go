@@ -0,0 +1,113 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPausesAPI(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewPausesAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Gym",
+		Price:       3000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	summer := &models.Pause{SubscriptionID: sub.ID, StartDate: storagetest.Month(2024, time.June)}
+	require.NoError(t, db.CreatePause(ctx, summer))
+	assert.NotEqual(t, uuid.Nil, summer.ID)
+
+	err := db.CreatePause(ctx, &models.Pause{SubscriptionID: sub.ID, StartDate: storagetest.Month(2025, time.January)})
+	assert.ErrorIs(t, err, postgres.ErrPauseOverlap, "an open pause covers every later month")
+
+	summer.Resume(storagetest.Month(2024, time.September))
+	require.NoError(t, db.UpdatePause(ctx, summer))
+	require.NoError(t, db.CreatePause(ctx, &models.Pause{SubscriptionID: sub.ID, StartDate: storagetest.Month(2025, time.January)}))
+
+	list, err := db.ListPauses(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, storagetest.Month(2024, time.August), list[0].EndDate)
+
+	err = db.CreatePause(ctx, &models.Pause{SubscriptionID: uuid.New(), StartDate: sub.StartDate})
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+	assert.ErrorIs(t, db.UpdatePause(ctx, &models.Pause{ID: uuid.New(), StartDate: sub.StartDate}), postgres.ErrNotFound)
+
+	require.NoError(t, subs.Delete(ctx, sub.ID))
+	list, err = db.ListPauses(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list, "pauses go with their subscription")
+}
+
+func TestSummary_Pauses(t *testing.T) {
+	conn := pgtest.DB(t)
+	ctx := context.Background()
+	subs := postgres.NewSubsAPI(conn)
+	discounts := postgres.NewDiscountsAPI(conn)
+	pauses := postgres.NewPausesAPI(conn)
+
+	sub := &models.Subscription{
+		ServiceName: "Gym",
+		Price:       3000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Day(2024, time.January, 16),
+		TrialMonths: 1,
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	discount := models.Discount{
+		SubscriptionID: sub.ID, Kind: models.DiscountFixed, Amount: 500, StartDate: storagetest.Month(2024, time.May),
+	}
+	require.NoError(t, discounts.CreateDiscount(ctx, &discount))
+
+	all := []models.Pause{
+		{StartDate: storagetest.Month(2024, time.June), EndDate: storagetest.Month(2024, time.August)},
+		{StartDate: storagetest.Month(2024, time.December)},
+	}
+	for i := range all {
+		all[i].SubscriptionID = sub.ID
+		require.NoError(t, pauses.CreatePause(ctx, &all[i]))
+	}
+
+	check := func(t *testing.T, db postgres.SubsAPI) {
+		for _, prorate := range []bool{false, true} {
+			req := &models.SumRequest{
+				StartDate: storagetest.Month(2024, time.January),
+				EndDate:   storagetest.Month(2024, time.December),
+				Prorate:   prorate,
+			}
+
+			got, err := db.Summary(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, sub.Charge(req, []models.Discount{discount}, all), got, "prorate %v", prorate)
+		}
+	}
+
+	// Charged February to May and September to November, discounted from May.
+	want := models.Summary{Gross: 7 * 3000, Discount: 4 * 500}
+	want.Net = want.Gross - want.Discount
+	assert.Equal(t, want, sub.Charge(&models.SumRequest{
+		StartDate: storagetest.Month(2024, time.January),
+		EndDate:   storagetest.Month(2024, time.December),
+	}, []models.Discount{discount}, all))
+
+	t.Run("raw", func(t *testing.T) { check(t, subs) })
+
+	require.NoError(t, postgres.NewRollupAPI(conn).Refresh(ctx, horizon))
+	t.Run("rollup", func(t *testing.T) { check(t, subs) })
+}
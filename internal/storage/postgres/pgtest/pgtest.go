@@ -0,0 +1,211 @@
+// Package pgtest runs tests against a real PostgreSQL. It uses the server
+// from POSTGRES_TEST_DSN when set and otherwise starts an embedded one.
+// Every test gets its own schema with all migrations applied.
+package pgtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/migrations"
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// EnvDSN points the tests at an existing server instead of an embedded one.
+const EnvDSN = "POSTGRES_TEST_DSN"
+
+var (
+	dsn         string
+	unavailable string
+	schemas     atomic.Int64
+)
+
+// Main starts the server, runs the tests and stops the server. Call it
+// from TestMain. When no server can be started the tests using DB are
+// skipped rather than failed.
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	if dsn = os.Getenv(EnvDSN); dsn != "" {
+		return m.Run()
+	}
+
+	stop, err := startEmbedded()
+	if err != nil {
+		unavailable = fmt.Sprintf("postgres unavailable (set %s to use an existing server): %s", EnvDSN, err)
+		return m.Run()
+	}
+	defer stop()
+
+	return m.Run()
+}
+
+func startEmbedded() (func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	runtimeDir, err := os.MkdirTemp("", "pgtest")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := embeddedpostgres.DefaultConfig().
+		Port(uint32(port)).
+		RuntimePath(runtimeDir).
+		Logger(io.Discard)
+
+	server := embeddedpostgres.NewDatabase(cfg)
+	if err := server.Start(); err != nil {
+		os.RemoveAll(runtimeDir)
+		return nil, err
+	}
+
+	dsn = cfg.GetConnectionURL() + "?sslmode=disable"
+
+	return func() {
+		server.Stop()
+		os.RemoveAll(runtimeDir)
+	}, nil
+}
+
+// DB returns a connection whose search_path starts with a new schema
+// holding the migrated tables. The schema is dropped when the test ends.
+func DB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db := EmptyDB(t)
+	migrate(t, db)
+
+	return db
+}
+
+// Pool is DB for the pgx driver: a pool on the same fresh, migrated schema.
+func Pool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	db := DB(t)
+
+	var schema string
+	if err := db.Get(&schema, "SELECT current_schema()"); err != nil {
+		t.Fatalf("current schema: %s", err)
+	}
+
+	pool, err := pgxpool.New(context.Background(), withSearchPath(dsn, schema+",public"))
+	if err != nil {
+		t.Fatalf("connect pool: %s", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// EmptyDB is DB without migrations, for testing the migrations themselves.
+func EmptyDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	if unavailable != "" {
+		t.Skip(unavailable)
+	}
+
+	admin, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect: %s", err)
+	}
+	defer admin.Close()
+
+	schema := fmt.Sprintf("pgtest_%d_%d", os.Getpid(), schemas.Add(1))
+
+	// Extensions are database wide: keep them in public, which stays on the
+	// search path of every test schema.
+	mustExec(t, admin, `CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`)
+	mustExec(t, admin, "CREATE SCHEMA "+schema)
+
+	db, err := sqlx.Connect("postgres", withSearchPath(dsn, schema+",public"))
+	if err != nil {
+		t.Fatalf("connect to schema: %s", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+
+		cleanup, err := sqlx.Connect("postgres", dsn)
+		if err != nil {
+			t.Errorf("drop schema %s: %s", schema, err)
+			return
+		}
+		defer cleanup.Close()
+
+		mustExec(t, cleanup, "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	return db
+}
+
+// migrate applies every up migration and records the version the way
+// golang-migrate does.
+func migrate(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+
+	files, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("find migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		query, err := fs.ReadFile(migrations.FS, file)
+		if err != nil {
+			t.Fatalf("read %s: %s", file, err)
+		}
+
+		if _, err := db.Exec(string(query)); err != nil {
+			t.Fatalf("apply %s: %s", file, err)
+		}
+	}
+
+	version, err := strconv.Atoi(strings.SplitN(files[len(files)-1], "_", 2)[0])
+	if err != nil {
+		t.Fatalf("parse migration version: %s", err)
+	}
+
+	mustExec(t, db, "CREATE TABLE schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	mustExec(t, db, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", version)
+}
+
+func withSearchPath(dsn, searchPath string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		query := u.Query()
+		query.Set("search_path", searchPath)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	return dsn + " search_path=" + searchPath
+}
+
+func mustExec(t *testing.T, db *sqlx.DB, query string, args ...any) {
+	t.Helper()
+
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("%s: %s", query, err)
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// OpenPool is Open for the pgx driver. Statements are prepared once per
+// connection and cached, up to cfg.StatementCache of them; 0 turns the
+// cache off.
+func OpenPool(ctx context.Context, base *slog.Logger, cfg *config.Postgres) (*pgxpool.Pool, error) {
+	poolCfg, err := poolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres pool fail: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.StartupTimeout)
+	defer cancel()
+
+	err = retry(ctx, cfg.RetryBackoff, cfg.RetryMaxBackoff, func() error {
+		err := pool.Ping(ctx)
+		if err != nil {
+			logger.FromContext(ctx, base).WarnContext(
+				ctx,
+				"postgres is not ready",
+				"error", err,
+			)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping postgres fail: %w", err)
+	}
+
+	return pool, nil
+}
+
+func poolConfig(cfg *config.Postgres) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres config fail: %w", err)
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(cfg.MaxOpenConns)
+	}
+
+	poolCfg.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolCfg.MaxConnIdleTime = cfg.ConnMaxIdleTime
+
+	poolCfg.ConnConfig.StatementCacheCapacity = cfg.StatementCache
+	if cfg.StatementCache == 0 {
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
+	}
+
+	return poolCfg, nil
+}
+
+// PoolDB serves pool through database/sql with the pgx driver, so that
+// the stores written against sqlx run on it too. Closing the result leaves
+// the pool open.
+func PoolDB(pool *pgxpool.Pool) *sqlx.DB {
+	return sqlx.NewDb(stdlib.OpenDBFromPool(pool), "pgx")
+}
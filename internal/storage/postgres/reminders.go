@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type RemindersAPI interface {
+	TryLock(ctx context.Context, key int64) (func(), bool, error)
+	EndingSoon(ctx context.Context, days int) ([]models.Subscription, error)
+	LongRunning(ctx context.Context, months int) ([]models.Subscription, error)
+	Ended(ctx context.Context) ([]models.Subscription, error)
+	RecordReminder(ctx context.Context, rem *models.Reminder, notify func(tx sqlx.ExecerContext) error) (bool, error)
+}
+
+type remindersDB struct {
+	db *sqlx.DB
+}
+
+func NewRemindersAPI(db *sqlx.DB) RemindersAPI {
+	return &remindersDB{db}
+}
+
+func (r *remindersDB) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	return TryAdvisoryLock(ctx, r.db, key)
+}
+
+// EndingSoon returns subscriptions whose last paid day, the last day of
+// their end month, is within days from today and that were not reminded
+// about for that end date yet.
+func (r *remindersDB) EndingSoon(ctx context.Context, days int) ([]models.Subscription, error) {
+	const query = `
+		SELECT ` + subColumns + ` FROM subscriptions s
+		WHERE (date_trunc('month', s.end_date) + INTERVAL '1 month - 1 day')::date
+			BETWEEN CURRENT_DATE AND CURRENT_DATE + $1::int
+		AND NOT EXISTS (
+			SELECT 1 FROM reminders r
+			WHERE r.subscription_id = s.id AND r.kind = 'ending_soon' AND r.period = s.end_date
+		)
+	`
+
+	subs := []models.Subscription{}
+	if err := r.db.SelectContext(ctx, &subs, query, days); err != nil {
+		return nil, fmt.Errorf("select ending subs fail: %w", err)
+	}
+
+	return subs, nil
+}
+
+// LongRunning returns still active subscriptions that started at least
+// months ago and were not reminded about for that start date yet.
+func (r *remindersDB) LongRunning(ctx context.Context, months int) ([]models.Subscription, error) {
+	const query = `
+		SELECT ` + subColumns + ` FROM subscriptions s
+		WHERE s.start_date <= CURRENT_DATE - make_interval(months => $1)
+		AND s.period @> date_trunc('month', CURRENT_DATE)::date
+		AND NOT EXISTS (
+			SELECT 1 FROM reminders r
+			WHERE r.subscription_id = s.id AND r.kind = 'long_running' AND r.period = s.start_date
+		)
+	`
+
+	subs := []models.Subscription{}
+	if err := r.db.SelectContext(ctx, &subs, query, months); err != nil {
+		return nil, fmt.Errorf("select long running subs fail: %w", err)
+	}
+
+	return subs, nil
+}
+
+// Ended returns subscriptions whose last paid month was the previous one.
+func (r *remindersDB) Ended(ctx context.Context) ([]models.Subscription, error) {
+	const query = `
+		SELECT ` + subColumns + ` FROM subscriptions s
+		WHERE s.end_date >= date_trunc('month', CURRENT_DATE) - interval '1 month'
+		AND s.end_date < date_trunc('month', CURRENT_DATE)
+		AND NOT EXISTS (
+			SELECT 1 FROM reminders r
+			WHERE r.subscription_id = s.id AND r.kind = 'ended' AND r.period = s.end_date
+		)
+	`
+
+	subs := []models.Subscription{}
+	if err := r.db.SelectContext(ctx, &subs, query); err != nil {
+		return nil, fmt.Errorf("select ended subs fail: %w", err)
+	}
+
+	return subs, nil
+}
+
+// RecordReminder stores the reminder and calls notify with the same
+// transaction, so a failed notification is retried on the next run while
+// a delivered one is never sent twice, and whatever notify writes with tx
+// is committed together with the reminder. It reports false when the
+// reminder already exists.
+func (r *remindersDB) RecordReminder(
+	ctx context.Context,
+	rem *models.Reminder,
+	notify func(tx sqlx.ExecerContext) error,
+) (bool, error) {
+	const query = `
+		INSERT INTO reminders (subscription_id, kind, period)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	period := rem.Subscription.EndDate
+	if rem.Kind == models.ReminderLongRunning {
+		period = rem.Subscription.StartDate
+	}
+
+	recorded := false
+	err := withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, rem.Subscription.ID, rem.Kind, period)
+		if err != nil {
+			return fmt.Errorf("insert reminder fail: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("database error: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return nil
+		}
+
+		if err := notify(tx); err != nil {
+			return fmt.Errorf("notify fail: %w", err)
+		}
+
+		recorded = true
+		return nil
+	})
+
+	return recorded, err
+}
@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemindersAPI_EndingSoon(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewRemindersAPI(conn)
+	ctx := context.Background()
+
+	thisMonth := models.MonthOf(time.Now())
+	create := func(end models.MonthDate) *models.Subscription {
+		sub := &models.Subscription{
+			ServiceName: "Netflix",
+			Price:       1000,
+			UserID:      uuid.New(),
+			StartDate:   models.MonthDate{Time: thisMonth.Time.AddDate(-1, 0, 0), Valid: true},
+			EndDate:     end,
+		}
+		require.NoError(t, subs.Create(ctx, sub))
+		return sub
+	}
+
+	ending := create(thisMonth)
+	create(models.MonthDate{Time: thisMonth.Time.AddDate(0, 3, 0), Valid: true})
+
+	found, err := db.EndingSoon(ctx, 30)
+	require.NoError(t, err)
+	require.Len(t, found, 1, "a subscription ending this month is paid until the last day of it")
+	assert.Equal(t, ending.ID, found[0].ID)
+}
+
+func TestRemindersAPI_RecordReminder(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	hooks := postgres.NewWebhooksAPI(conn)
+	db := postgres.NewRemindersAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   models.MonthOf(time.Now()),
+		EndDate:     models.MonthOf(time.Now()),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	events := func() int {
+		var n int
+		require.NoError(t, conn.GetContext(ctx, &n, `SELECT count(*) FROM outbox WHERE event_type = $1`, models.ReminderEndingSoon.Event()))
+		return n
+	}
+
+	rem := &models.Reminder{Kind: models.ReminderEndingSoon, Subscription: *sub}
+	publish := func(tx sqlx.ExecerContext) error {
+		return hooks.PublishEvent(ctx, tx, rem.Kind.Event(), &rem.Subscription)
+	}
+
+	_, err := db.RecordReminder(ctx, rem, func(tx sqlx.ExecerContext) error {
+		require.NoError(t, publish(tx))
+		return assert.AnError
+	})
+	require.Error(t, err)
+	assert.Zero(t, events(), "the event rolls back with the reminder")
+
+	recorded, err := db.RecordReminder(ctx, rem, publish)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Equal(t, 1, events())
+
+	recorded, err = db.RecordReminder(ctx, rem, publish)
+	require.NoError(t, err)
+	assert.False(t, recorded)
+	assert.Equal(t, 1, events(), "a recorded reminder is never published again")
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jmoiron/sqlx"
+)
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that reads made with it go to the primary. Use
+// it when a caller must see its own writes despite replication lag.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+type replica struct {
+	addr    string
+	db      *sqlx.DB
+	close   func() error
+	healthy atomic.Bool
+}
+
+// ReplicaSet routes reads across the healthy read replicas in round-robin
+// order and falls back to the primary when none of them is healthy.
+type ReplicaSet struct {
+	// Timeout bounds each replica ping, so one hanging replica neither
+	// stalls the checks of the others nor stays in rotation.
+	Timeout time.Duration
+
+	primary  *sqlx.DB
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// DefaultReplicaTimeout is the Timeout of a new ReplicaSet.
+const DefaultReplicaTimeout = time.Second
+
+// NewReplicaSet wraps primary and replicas, keyed by address. Replicas
+// receive no reads until the first Check marks them healthy.
+func NewReplicaSet(primary *sqlx.DB, replicas map[string]*sqlx.DB) *ReplicaSet {
+	set := &ReplicaSet{Timeout: DefaultReplicaTimeout, primary: primary}
+	for addr, db := range replicas {
+		set.replicas = append(set.replicas, &replica{addr: addr, db: db, close: db.Close})
+	}
+
+	return set
+}
+
+// OpenReplicaSet connects to every replica listed in cfg with the driver,
+// credentials and pool settings of the primary. Unreachable replicas do
+// not fail startup: they stay out of rotation until they pass a check.
+func OpenReplicaSet(ctx context.Context, primary *sqlx.DB, cfg *config.Postgres) (*ReplicaSet, error) {
+	set := &ReplicaSet{Timeout: cfg.ReplicaTimeout, primary: primary}
+
+	for _, addr := range cfg.Replicas {
+		replicaCfg := *cfg
+		replicaCfg.Host, replicaCfg.Port = addr, cfg.Port
+
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			replicaCfg.Host, replicaCfg.Port = host, port
+		}
+
+		replica, err := openReplica(ctx, addr, &replicaCfg)
+		if err != nil {
+			set.Close()
+			return nil, fmt.Errorf("replica %s: %w", addr, err)
+		}
+
+		set.replicas = append(set.replicas, replica)
+	}
+
+	set.Check(ctx)
+
+	return set, nil
+}
+
+// openReplica creates the pool of one replica without connecting.
+func openReplica(ctx context.Context, addr string, cfg *config.Postgres) (*replica, error) {
+	if cfg.Driver != "pgx" {
+		db, err := open(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &replica{addr: addr, db: db, close: db.Close}, nil
+	}
+
+	poolCfg, err := poolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("open postgres pool fail: %w", err)
+	}
+
+	db := PoolDB(pool)
+
+	return &replica{addr: addr, db: db, close: func() error {
+		err := db.Close()
+		pool.Close()
+		return err
+	}}, nil
+}
+
+// Reader returns the connection pool a read made with ctx should use.
+func (r *ReplicaSet) Reader(ctx context.Context) *sqlx.DB {
+	if usePrimary(ctx) || len(r.replicas) == 0 {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		replica := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if replica.healthy.Load() {
+			return replica.db
+		}
+	}
+
+	return r.primary
+}
+
+// Check pings every replica and updates its place in the rotation. It
+// returns the errors of the replicas taken out.
+func (r *ReplicaSet) Check(ctx context.Context) error {
+	var errs []error
+
+	for _, replica := range r.replicas {
+		err := r.ping(ctx, replica)
+		replica.healthy.Store(err == nil)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("replica %s: %w", replica.addr, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *ReplicaSet) ping(ctx context.Context, replica *replica) error {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	return replica.db.PingContext(ctx)
+}
+
+// Run checks the replicas every interval until ctx is done.
+func (r *ReplicaSet) Run(ctx context.Context, logger *slog.Logger, interval time.Duration) {
+	if len(r.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := r.Check(ctx); err != nil {
+			logger.Warn(
+				"read replica unhealthy",
+				"error", err,
+			)
+		}
+	}
+}
+
+// Close closes the replica pools. The primary is owned by the caller.
+func (r *ReplicaSet) Close() error {
+	var errs []error
+	for _, replica := range r.replicas {
+		errs = append(errs, replica.close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pingDriver connects successfully only to the data source names "up"
+// and "hang"; pings of the latter block until their context is done.
+type pingDriver struct{}
+
+type pingConn struct {
+	hang bool
+}
+
+func (pingDriver) Open(name string) (driver.Conn, error) {
+	switch name {
+	case "up":
+		return pingConn{}, nil
+	case "hang":
+		return pingConn{hang: true}, nil
+	}
+	return nil, errors.New("connection refused")
+}
+
+func (c pingConn) Ping(ctx context.Context) error {
+	if c.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (pingConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (pingConn) Close() error                              { return nil }
+func (pingConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func init() {
+	sql.Register("ping", pingDriver{})
+}
+
+func pingDB(t *testing.T, name string) *sqlx.DB {
+	db, err := sqlx.Open("ping", name)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestReplicaSet_Reader(t *testing.T) {
+	primary := pingDB(t, "up")
+	healthy := pingDB(t, "up")
+	down := pingDB(t, "down")
+
+	set := NewReplicaSet(primary, map[string]*sqlx.DB{
+		"replica-1:5432": healthy,
+		"replica-2:5432": down,
+	})
+	ctx := context.Background()
+
+	assert.Same(t, primary, set.Reader(ctx), "replicas are unchecked")
+
+	err := set.Check(ctx)
+	assert.ErrorContains(t, err, "replica-2:5432")
+
+	for i := 0; i < 4; i++ {
+		assert.Same(t, healthy, set.Reader(ctx))
+	}
+
+	assert.Same(t, primary, set.Reader(WithPrimary(ctx)))
+}
+
+func TestReplicaSet_Failover(t *testing.T) {
+	primary := pingDB(t, "up")
+	set := NewReplicaSet(primary, map[string]*sqlx.DB{
+		"replica-1:5432": pingDB(t, "down"),
+		"replica-2:5432": pingDB(t, "down"),
+	})
+	ctx := context.Background()
+
+	assert.Error(t, set.Check(ctx))
+	assert.Same(t, primary, set.Reader(ctx))
+}
+
+func TestReplicaSet_Timeout(t *testing.T) {
+	primary := pingDB(t, "up")
+	healthy := pingDB(t, "up")
+	set := NewReplicaSet(primary, map[string]*sqlx.DB{
+		"replica-1:5432": healthy,
+		"replica-2:5432": pingDB(t, "hang"),
+	})
+	set.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := set.Check(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	for i := 0; i < 4; i++ {
+		assert.Same(t, healthy, set.Reader(context.Background()))
+	}
+}
+
+func TestReplicaSet_NoReplicas(t *testing.T) {
+	primary := pingDB(t, "up")
+	set := NewReplicaSet(primary, nil)
+
+	assert.NoError(t, set.Check(context.Background()))
+	assert.Same(t, primary, set.Reader(context.Background()))
+}
+
+func TestOpenReplicaSet_Pgx(t *testing.T) {
+	primary := pingDB(t, "up")
+	cfg := &config.Postgres{
+		Port:           "5432",
+		User:           "subs",
+		DB:             "subs",
+		SSLMode:        "disable",
+		Driver:         "pgx",
+		Replicas:       []string{"127.0.0.1:1"},
+		ReplicaTimeout: time.Second,
+	}
+
+	set, err := OpenReplicaSet(context.Background(), primary, cfg)
+	require.NoError(t, err, "unreachable replicas do not fail startup")
+	t.Cleanup(func() { set.Close() })
+
+	require.Len(t, set.replicas, 1)
+	assert.Equal(t, "pgx", set.replicas[0].db.DriverName())
+	assert.False(t, set.replicas[0].healthy.Load())
+	assert.Same(t, primary, set.Reader(context.Background()))
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// RollupAPI maintains spend_rollup, the monthly totals per user, service
+// and category that Summary reads instead of raw subscriptions. Triggers
+// keep materialised months current on every write; Refresh and Rebuild
+// move the horizon for open ended subscriptions.
+type RollupAPI interface {
+	TryLock(ctx context.Context, key int64) (func(), bool, error)
+	// Through returns the last materialised month, or false before the
+	// first refresh.
+	Through(ctx context.Context) (time.Time, bool, error)
+	// Refresh materialises the months after the current horizon up to
+	// through. It builds the whole rollup when none exists yet.
+	Refresh(ctx context.Context, through time.Time) error
+	// Rebuild discards the rollup and recomputes it up to through.
+	Rebuild(ctx context.Context, through time.Time) error
+	// Verify compares every materialised month with the raw subscriptions.
+	Verify(ctx context.Context) ([]models.RollupDiff, error)
+}
+
+type rollupDB struct {
+	db *sqlx.DB
+}
+
+func NewRollupAPI(db *sqlx.DB) RollupAPI {
+	return &rollupDB{db}
+}
+
+func (r *rollupDB) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	return TryAdvisoryLock(ctx, r.db, key)
+}
+
+func (r *rollupDB) Through(ctx context.Context) (time.Time, bool, error) {
+	var through sql.NullTime
+	if err := r.db.GetContext(ctx, &through, "SELECT through FROM spend_rollup_state"); err != nil {
+		return time.Time{}, false, fmt.Errorf("rollup state fail: %w", err)
+	}
+
+	return through.Time, through.Valid, nil
+}
+
+func (r *rollupDB) Refresh(ctx context.Context, through time.Time) error {
+	through = firstOfMonth(through)
+
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		current, err := lockRollup(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if current.Valid && !current.Time.Before(through) {
+			return nil
+		}
+
+		var from *time.Time
+		if current.Valid {
+			next := current.Time.AddDate(0, 1, 0)
+			from = &next
+		}
+
+		if err := materialise(ctx, tx, from, through); err != nil {
+			return err
+		}
+
+		return setThrough(ctx, tx, through)
+	})
+}
+
+func (r *rollupDB) Rebuild(ctx context.Context, through time.Time) error {
+	through = firstOfMonth(through)
+
+	return withTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if _, err := lockRollup(ctx, tx); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM spend_rollup"); err != nil {
+			return fmt.Errorf("clear rollup fail: %w", err)
+		}
+
+		if err := materialise(ctx, tx, nil, through); err != nil {
+			return err
+		}
+
+		return setThrough(ctx, tx, through)
+	})
+}
+
+func (r *rollupDB) Verify(ctx context.Context) ([]models.RollupDiff, error) {
+	const query = `
+		WITH raw AS (
+			SELECT m::date AS month, user_id, service_name, category, SUM(subscription_price(subscriptions, m::date)) AS total
+			FROM subscriptions, spend_rollup_state st,
+				generate_series(lower(period)::timestamp, LEAST(end_date, st.through)::timestamp, INTERVAL '1 month') m
+			WHERE st.through IS NOT NULL
+			GROUP BY 1, 2, 3, 4
+		)
+		SELECT month, user_id, service_name, category,
+			COALESCE(raw.total, 0) AS want, COALESCE(r.total, 0) AS got
+		FROM raw FULL JOIN spend_rollup r USING (month, user_id, service_name, category)
+		WHERE COALESCE(raw.total, 0) <> COALESCE(r.total, 0)
+		ORDER BY month, user_id, service_name, category
+	`
+
+	diffs := []models.RollupDiff{}
+	if err := r.db.SelectContext(ctx, &diffs, query); err != nil {
+		return nil, fmt.Errorf("verify rollup fail: %w", err)
+	}
+
+	return diffs, nil
+}
+
+// lockRollup blocks subscription writes until tx ends, so that the
+// triggers of concurrent writes apply on top of the recomputed rows
+// instead of being lost or counted twice.
+func lockRollup(ctx context.Context, tx *sqlx.Tx) (sql.NullTime, error) {
+	var current sql.NullTime
+
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE spend_rollup IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return current, fmt.Errorf("lock rollup fail: %w", err)
+	}
+
+	if err := tx.GetContext(ctx, &current, "SELECT through FROM spend_rollup_state FOR UPDATE"); err != nil {
+		return current, fmt.Errorf("rollup state fail: %w", err)
+	}
+
+	return current, nil
+}
+
+// materialise adds the charges of every subscription for the months from
+// from (or its start) to through inclusive.
+func materialise(ctx context.Context, tx *sqlx.Tx, from *time.Time, through time.Time) error {
+	const query = `
+		INSERT INTO spend_rollup AS r (month, user_id, service_name, category, total)
+		SELECT m::date, user_id, service_name, category, SUM(subscription_price(subscriptions, m::date))
+		FROM subscriptions,
+			generate_series(
+				GREATEST(lower(period), $1::date)::timestamp,
+				LEAST(end_date, $2::date)::timestamp,
+				INTERVAL '1 month'
+			) m
+		GROUP BY 1, 2, 3, 4
+		ON CONFLICT (month, user_id, service_name, category)
+		DO UPDATE SET total = r.total + EXCLUDED.total
+	`
+
+	if _, err := tx.ExecContext(ctx, query, from, through); err != nil {
+		return fmt.Errorf("materialise rollup fail: %w", err)
+	}
+
+	return nil
+}
+
+func setThrough(ctx context.Context, tx *sqlx.Tx, through time.Time) error {
+	const query = `
+		UPDATE spend_rollup_state
+		SET through = $1, refreshed_at = now()
+	`
+
+	if _, err := tx.ExecContext(ctx, query, through); err != nil {
+		return fmt.Errorf("update rollup state fail: %w", err)
+	}
+
+	return nil
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
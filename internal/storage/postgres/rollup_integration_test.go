@@ -0,0 +1,181 @@
+package postgres_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var horizon = time.Date(2030, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+func TestSubsAPI_Rollup(t *testing.T) {
+	storagetest.RunStore(t, func(t *testing.T) storagetest.Store {
+		conn := pgtest.DB(t)
+		require.NoError(t, postgres.NewRollupAPI(conn).Refresh(context.Background(), horizon))
+		return newStore(conn)
+	})
+}
+
+func TestRollup_MatchesRaw(t *testing.T) {
+	conn := pgtest.DB(t)
+	db := postgres.NewSubsAPI(conn)
+	rollup := postgres.NewRollupAPI(conn)
+	ctx := context.Background()
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	services := []string{"Netflix", "Spotify", "YouTube"}
+	categories := []string{"", "video", "music"}
+
+	randomSub := func() models.Subscription {
+		sub := models.Subscription{
+			ServiceName: services[rnd.IntN(len(services))],
+			Category:    categories[rnd.IntN(len(categories))],
+			Price:       rnd.IntN(2000),
+			UserID:      users[rnd.IntN(len(users))],
+			StartDate:   storagetest.Month(2020+rnd.IntN(8), time.Month(1+rnd.IntN(12))),
+		}
+		if rnd.IntN(3) > 0 {
+			sub.EndDate = models.MonthDate{Time: sub.StartDate.Time.AddDate(0, rnd.IntN(60), 0), Valid: true}
+		}
+		return sub
+	}
+
+	subs := map[uuid.UUID]models.Subscription{}
+
+	// Half of the data predates the rollup, half goes through triggers.
+	create := func(n int) {
+		for range n {
+			sub := randomSub()
+			require.NoError(t, db.Create(ctx, &sub))
+			subs[sub.ID] = sub
+		}
+	}
+
+	create(50)
+	require.NoError(t, rollup.Refresh(ctx, horizon))
+	create(50)
+
+	for id, sub := range subs {
+		switch rnd.IntN(4) {
+		case 0:
+			require.NoError(t, db.Delete(ctx, id))
+			delete(subs, id)
+		case 1:
+			updated := randomSub()
+			updated.ID = sub.ID
+			require.NoError(t, db.Update(ctx, &updated))
+			subs[id] = updated
+		}
+	}
+
+	diffs, err := rollup.Verify(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	for range 100 {
+		start := storagetest.Month(2019+rnd.IntN(12), time.Month(1+rnd.IntN(12)))
+		req := &models.SumRequest{
+			StartDate: start,
+			EndDate:   models.MonthDate{Time: start.Time.AddDate(0, rnd.IntN(36), 0), Valid: true},
+		}
+		if rnd.IntN(2) == 0 {
+			req.UserID = users[rnd.IntN(len(users))]
+		}
+		if rnd.IntN(2) == 0 {
+			req.ServiceName = services[rnd.IntN(len(services))]
+		}
+		if rnd.IntN(2) == 0 {
+			req.Category = categories[rnd.IntN(len(categories))]
+		}
+
+		want := 0
+		for _, sub := range subs {
+			if (req.UserID == uuid.Nil || req.UserID == sub.UserID) &&
+				(req.ServiceName == "" || req.ServiceName == sub.ServiceName) &&
+				(req.Category == "" || req.Category == sub.Category) {
+				want += sub.Cost(req.StartDate, req.EndDate)
+			}
+		}
+
+		got, err := db.Summary(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, models.Undiscounted(want), got, "%+v", req)
+	}
+}
+
+func TestRollup_Refresh(t *testing.T) {
+	conn := pgtest.DB(t)
+	db := postgres.NewSubsAPI(conn)
+	rollup := postgres.NewRollupAPI(conn)
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(ctx, &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+	}))
+
+	_, built, err := rollup.Through(ctx)
+	require.NoError(t, err)
+	assert.False(t, built)
+
+	require.NoError(t, rollup.Refresh(ctx, time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)))
+
+	through, built, err := rollup.Through(ctx)
+	require.NoError(t, err)
+	assert.True(t, built)
+	assert.Equal(t, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), through.UTC())
+
+	year := &models.SumRequest{StartDate: storagetest.Month(2024, time.January), EndDate: storagetest.Month(2024, time.December)}
+
+	// Past the horizon Summary falls back to raw subscriptions.
+	total, err := db.Summary(ctx, year)
+	require.NoError(t, err)
+	assert.Equal(t, 12000, total.Net)
+
+	require.NoError(t, rollup.Refresh(ctx, time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)))
+
+	var months int
+	require.NoError(t, conn.Get(&months, "SELECT count(*) FROM spend_rollup"))
+	assert.Equal(t, 12, months)
+
+	total, err = db.Summary(ctx, year)
+	require.NoError(t, err)
+	assert.Equal(t, 12000, total.Net)
+}
+
+func TestRollup_Rebuild(t *testing.T) {
+	conn := pgtest.DB(t)
+	db := postgres.NewSubsAPI(conn)
+	rollup := postgres.NewRollupAPI(conn)
+	ctx := context.Background()
+
+	require.NoError(t, rollup.Refresh(ctx, horizon))
+	require.NoError(t, db.Create(ctx, &models.Subscription{
+		ServiceName: "Netflix", Price: 1000, UserID: uuid.New(), StartDate: storagetest.Month(2024, time.January),
+		EndDate: storagetest.Month(2024, time.March),
+	}))
+
+	_, err := conn.Exec("UPDATE spend_rollup SET total = 0 WHERE month = '2024-02-01'")
+	require.NoError(t, err)
+
+	diffs, err := rollup.Verify(ctx)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, 1000, diffs[0].Want)
+	assert.Zero(t, diffs[0].Got)
+
+	require.NoError(t, rollup.Rebuild(ctx, horizon))
+
+	diffs, err = rollup.Verify(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
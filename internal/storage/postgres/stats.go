@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type StatsAPI interface {
+	ServiceStats(ctx context.Context) ([]models.ServiceStats, error)
+}
+
+type statsDB struct {
+	db *sqlx.DB
+}
+
+func NewStatsAPI(db *sqlx.DB) StatsAPI {
+	return &statsDB{db}
+}
+
+// ServiceStats counts subscriptions active in the current month and sums
+// what they charge for it per service, so trial months add nothing.
+func (s *statsDB) ServiceStats(ctx context.Context) ([]models.ServiceStats, error) {
+	const query = `
+		SELECT service_name, COUNT(*) AS active,
+			SUM(subscription_price(subscriptions, date_trunc('month', CURRENT_DATE)::date)) AS monthly_revenue
+		FROM subscriptions
+		WHERE period @> date_trunc('month', CURRENT_DATE)::date
+		GROUP BY service_name
+	`
+
+	stats := []models.ServiceStats{}
+	if err := s.db.SelectContext(ctx, &stats, query); err != nil {
+		return nil, fmt.Errorf("service stats fail: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrTransition is returned when the lifecycle does not let a
+	// subscription move to the requested status.
+	ErrTransition = errors.New("transition not allowed")
+	// ErrCancelBeforeStart is returned when a cancellation takes effect
+	// before the start month of its subscription.
+	ErrCancelBeforeStart = errors.New("cancellation before subscription start")
+)
+
+const transitionColumns = "id, subscription_id, from_status, to_status, reason, created_at"
+
+// StatusAPI moves subscriptions along their lifecycle and keeps the
+// history of their transitions.
+type StatusAPI interface {
+	TryLock(ctx context.Context, key int64) (func(), bool, error)
+	// Cancel moves the subscription of c to cancelled at once, whatever the
+	// effective month of c, ends it with that month, or keeps an earlier end
+	// month as the effective one, and stores c with the start month of the
+	// subscription. It returns ErrNotFound when the subscription does
+	// not exist, ErrTransition when it cannot be cancelled and
+	// ErrCancelBeforeStart when c takes effect before it starts.
+	Cancel(ctx context.Context, c *models.Cancellation) (*models.Transition, error)
+	// Churn reports, for every service and month of req, the subscriptions
+	// running and those cancelled with effect in that month.
+	Churn(ctx context.Context, req *models.ChurnRequest) ([]models.Churn, error)
+	// Advance moves subscriptions, all of them or only ids, as far along
+	// their lifecycle as their dates and pauses call for in month and
+	// returns the number of transitions made. A non-empty reason replaces
+	// the default reason of every step.
+	Advance(ctx context.Context, month models.MonthDate, reason string, ids ...uuid.UUID) (int, error)
+	ListTransitions(ctx context.Context, subID uuid.UUID) ([]models.Transition, error)
+}
+
+type statusDB struct {
+	db *sqlx.DB
+}
+
+func NewStatusAPI(db *sqlx.DB) StatusAPI {
+	return &statusDB{db}
+}
+
+func (s *statusDB) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	return TryAdvisoryLock(ctx, s.db, key)
+}
+
+func (s *statusDB) Cancel(ctx context.Context, c *models.Cancellation) (*models.Transition, error) {
+	const (
+		lock = `
+			SELECT ` + subColumns + ` FROM subscriptions
+			WHERE id = $1
+			FOR UPDATE
+		`
+		update = `
+			UPDATE subscriptions
+			SET status = $1, end_date = $2
+			WHERE id = $3
+		`
+		insert = `
+			INSERT INTO subscription_cancellations (
+				subscription_id, user_id, service_name, category, start_date, effective_date, reason_code, reason
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at
+		`
+	)
+
+	var transition *models.Transition
+
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		var sub models.Subscription
+		if err := tx.GetContext(ctx, &sub, lock, c.SubscriptionID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("lock sub fail: %w", err)
+		}
+
+		if !sub.Status.CanMoveTo(models.StatusCancelled) {
+			return ErrTransition
+		}
+
+		c.EffectiveDate = c.EffectiveDate.Month()
+		if c.EffectiveDate.Time.Before(sub.StartDate.Month().Time) {
+			return ErrCancelBeforeStart
+		}
+
+		if sub.EndDate.Valid && !sub.EndDate.Month().Time.After(c.EffectiveDate.Time) {
+			c.EffectiveDate = sub.EndDate.Month()
+		} else {
+			sub.EndDate = c.EffectiveDate
+		}
+
+		from := sub.Status
+		sub.Status = models.StatusCancelled
+
+		if _, err := tx.ExecContext(ctx, update, sub.Status, sub.EndDate, sub.ID); err != nil {
+			return fmt.Errorf("cancel sub fail: %w", err)
+		}
+
+		c.UserID, c.ServiceName, c.Category = sub.UserID, sub.ServiceName, sub.Category
+		c.StartDate = sub.StartDate.Month()
+
+		if err := tx.QueryRowContext(
+			ctx,
+			insert,
+			c.SubscriptionID, c.UserID, c.ServiceName, c.Category, c.StartDate, c.EffectiveDate, c.ReasonCode, c.Reason,
+		).Scan(&c.ID, &c.CreatedAt); err != nil {
+			return fmt.Errorf("insert cancellation fail: %w", err)
+		}
+
+		var err error
+		if transition, err = recordTransition(ctx, tx, sub.ID, from, sub.Status, c.TransitionReason()); err != nil {
+			return err
+		}
+
+		return writeEvent(ctx, tx, models.EventSubUpdated, &sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transition, nil
+}
+
+func recordTransition(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	subID uuid.UUID,
+	from, to models.Status,
+	reason string,
+) (*models.Transition, error) {
+	const query = `
+		INSERT INTO subscription_transitions (subscription_id, from_status, to_status, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + transitionColumns
+
+	var transition models.Transition
+	if err := tx.GetContext(ctx, &transition, query, subID, from, to, reason); err != nil {
+		return nil, fmt.Errorf("insert transition fail: %w", err)
+	}
+
+	return &transition, nil
+}
+
+// advanceQuery moves every subscription in a non-final status, or only
+// those in $3, one step along its lifecycle in month $1, records the
+// transitions with reason $2 or a default one and returns the moved
+// subscriptions.
+const advanceQuery = `
+	WITH moved AS (
+		UPDATE subscriptions s
+		SET status = next.to_status
+		FROM (
+			SELECT id AS next_id, status AS from_status, subscription_next_status(subscriptions, $1::date) AS to_status
+			FROM subscriptions
+			WHERE status IN ('scheduled', 'active', 'paused')
+				AND ($3::uuid[] IS NULL OR id = ANY($3::uuid[]))
+		) next
+		WHERE s.id = next.next_id AND s.status = next.from_status AND next.to_status <> next.from_status
+		RETURNING ` + subColumns + `, next.from_status
+	), recorded AS (
+		INSERT INTO subscription_transitions (subscription_id, from_status, to_status, reason)
+		SELECT id, from_status, status, COALESCE($2, CASE
+			WHEN status = 'expired' THEN 'end date passed'
+			WHEN status = 'paused' THEN 'pause started'
+			WHEN from_status = 'paused' THEN 'pause ended'
+			ELSE 'start date reached'
+		END)
+		FROM moved
+	)
+	SELECT ` + subColumns + ` FROM moved
+`
+
+func (s *statusDB) Advance(ctx context.Context, month models.MonthDate, reason string, ids ...uuid.UUID) (int, error) {
+	var reasonArg, idsArg any
+	if reason != "" {
+		reasonArg = reason
+	}
+
+	if len(ids) > 0 {
+		idsArg = pq.Array(ids)
+	}
+
+	total := 0
+
+	// Every step moves a subscription one transition further, and no
+	// path through the lifecycle is longer than its list of statuses.
+	for range models.Statuses {
+		var moved []models.Subscription
+
+		err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+			if err := tx.SelectContext(ctx, &moved, advanceQuery, month.Month(), reasonArg, idsArg); err != nil {
+				return fmt.Errorf("advance statuses fail: %w", err)
+			}
+
+			for i := range moved {
+				if err := writeEvent(ctx, tx, models.EventSubUpdated, &moved[i]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+
+		if len(moved) == 0 {
+			break
+		}
+
+		total += len(moved)
+	}
+
+	return total, nil
+}
+
+func (s *statusDB) ListTransitions(ctx context.Context, subID uuid.UUID) ([]models.Transition, error) {
+	const query = `
+		SELECT ` + transitionColumns + ` FROM subscription_transitions
+		WHERE subscription_id = $1
+		ORDER BY created_at, id
+	`
+
+	transitions := []models.Transition{}
+	if err := s.db.SelectContext(ctx, &transitions, query, subID); err != nil {
+		return nil, fmt.Errorf("list transitions fail: %w", err)
+	}
+
+	return transitions, nil
+}
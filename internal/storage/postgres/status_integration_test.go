@@ -0,0 +1,257 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusAPI(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	pauses := postgres.NewPausesAPI(conn)
+	db := postgres.NewStatusAPI(conn)
+	ctx := context.Background()
+
+	gym := &models.Subscription{
+		ServiceName: "Gym",
+		Price:       3000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, gym))
+	assert.Equal(t, models.StatusActive, gym.Status)
+
+	later := &models.Subscription{
+		ServiceName: "Gym",
+		Price:       3000,
+		UserID:      gym.UserID,
+		StartDate:   storagetest.Month(2099, time.January),
+	}
+	require.NoError(t, subs.Create(ctx, later))
+	assert.Equal(t, models.StatusScheduled, later.Status)
+
+	_, err := db.Cancel(ctx, &models.Cancellation{
+		SubscriptionID: later.ID, EffectiveDate: storagetest.Month(2099, time.March), ReasonCode: models.CancelOther,
+	})
+	assert.ErrorIs(t, err, postgres.ErrTransition, "scheduled subscriptions are deleted, not cancelled")
+
+	require.NoError(t, pauses.CreatePause(ctx, &models.Pause{SubscriptionID: gym.ID, StartDate: storagetest.Month(2024, time.June)}))
+
+	moved, err := db.Advance(ctx, storagetest.Month(2024, time.July), "", gym.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	var events int
+	require.NoError(t, conn.GetContext(ctx, &events, `
+		SELECT count(*) FROM outbox
+		WHERE event_type = $1 AND payload->>'id' = $2 AND payload->>'status' = 'paused'
+	`, models.EventSubUpdated, gym.ID.String()))
+	assert.Equal(t, 1, events, "every advanced subscription is announced")
+
+	moved, err = db.Advance(ctx, storagetest.Month(2024, time.July), "", gym.ID)
+	require.NoError(t, err)
+	assert.Zero(t, moved, "advancing twice in a month changes nothing")
+
+	sum, err := subs.Summary(ctx, &models.SumRequest{
+		UserID:    gym.UserID,
+		StartDate: storagetest.Month(2024, time.January),
+		EndDate:   storagetest.Month(2024, time.December),
+		Status:    models.StatusPaused,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 15000, sum.Net, "only the paused gym, January to May")
+
+	cancellation := &models.Cancellation{
+		SubscriptionID: gym.ID,
+		EffectiveDate:  storagetest.Month(2024, time.September),
+		ReasonCode:     models.CancelOther,
+		Reason:         "moved away",
+	}
+	transition, err := db.Cancel(ctx, cancellation)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, cancellation.ID)
+	assert.Equal(t, "Gym", cancellation.ServiceName)
+	assert.Equal(t, models.StatusPaused, transition.From)
+	assert.Equal(t, models.StatusCancelled, transition.To)
+
+	read, err := subs.Read(ctx, gym.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, read.Status)
+	assert.Equal(t, storagetest.Month(2024, time.September), read.EndDate)
+
+	_, err = db.Cancel(ctx, &models.Cancellation{
+		SubscriptionID: gym.ID, EffectiveDate: storagetest.Month(2024, time.October), ReasonCode: models.CancelOther,
+	})
+	assert.ErrorIs(t, err, postgres.ErrTransition)
+
+	_, err = db.Cancel(ctx, &models.Cancellation{
+		SubscriptionID: uuid.New(), EffectiveDate: storagetest.Month(2024, time.October), ReasonCode: models.CancelOther,
+	})
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+
+	list, err := db.ListTransitions(ctx, gym.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "pause started", list[0].Reason)
+	assert.Equal(t, "other: moved away", list[1].Reason)
+
+	moved, err = db.Advance(ctx, storagetest.Month(2099, time.February), "")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, moved, 1)
+
+	read, err = subs.Read(ctx, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusActive, read.Status)
+
+	list, err = db.ListTransitions(ctx, later.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "start date reached", list[0].Reason)
+}
+
+func TestStatusAPI_CancelDates(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewStatusAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Gym",
+		Price:       3000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.March),
+		EndDate:     storagetest.Month(2030, time.June),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+
+	_, err := db.Cancel(ctx, &models.Cancellation{
+		SubscriptionID: sub.ID, EffectiveDate: storagetest.Month(2024, time.February), ReasonCode: models.CancelNotUsing,
+	})
+	assert.ErrorIs(t, err, postgres.ErrCancelBeforeStart)
+
+	cancellation := &models.Cancellation{
+		SubscriptionID: sub.ID, EffectiveDate: storagetest.Month(2031, time.January), ReasonCode: models.CancelNotUsing,
+	}
+	_, err = db.Cancel(ctx, cancellation)
+	require.NoError(t, err)
+	assert.Equal(t, storagetest.Month(2030, time.June), cancellation.EffectiveDate, "an earlier end month is kept")
+	assert.Equal(t, storagetest.Month(2024, time.March), cancellation.StartDate)
+
+	read, err := subs.Read(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storagetest.Month(2030, time.June), read.EndDate)
+	assert.Equal(t, models.StatusCancelled, read.Status, "the status changes at once, before the effective month")
+
+	for m, want := range map[time.Month]int{time.June: 3000, time.July: 0} {
+		sum, err := subs.Summary(ctx, &models.SumRequest{
+			UserID:    sub.UserID,
+			StartDate: storagetest.Month(2030, m),
+			EndDate:   storagetest.Month(2030, m),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, want, sum.Net, "charged up to the effective month, %s", m)
+	}
+}
+
+func TestStatusAPI_UpdateRestatus(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewStatusAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Gym",
+		Price:       3000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+		EndDate:     storagetest.Month(2024, time.June),
+	}
+	require.NoError(t, subs.Create(ctx, sub))
+	require.Equal(t, models.StatusExpired, sub.Status)
+
+	sub.EndDate = storagetest.Month(2099, time.June)
+	require.NoError(t, subs.Update(ctx, sub))
+	assert.Equal(t, models.StatusActive, sub.Status, "an expired subscription extended is active again")
+
+	transitions, err := db.ListTransitions(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.StatusExpired, transitions[0].From)
+	assert.Equal(t, models.StatusActive, transitions[0].To)
+	assert.Equal(t, "subscription updated", transitions[0].Reason)
+
+	sub.Price = 3500
+	require.NoError(t, subs.Update(ctx, sub))
+
+	transitions, err = db.ListTransitions(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Len(t, transitions, 1, "edits keeping the status record nothing")
+}
+
+func TestStatusAPI_Churn(t *testing.T) {
+	conn := pgtest.DB(t)
+	subs := postgres.NewSubsAPI(conn)
+	db := postgres.NewStatusAPI(conn)
+	ctx := context.Background()
+
+	create := func(service string) *models.Subscription {
+		sub := &models.Subscription{
+			ServiceName: service,
+			Price:       1000,
+			UserID:      uuid.New(),
+			StartDate:   storagetest.Month(2024, time.January),
+		}
+		require.NoError(t, subs.Create(ctx, sub))
+		return sub
+	}
+
+	cancel := func(sub *models.Subscription, m time.Month, code models.CancelReason) {
+		_, err := db.Cancel(ctx, &models.Cancellation{
+			SubscriptionID: sub.ID, EffectiveDate: storagetest.Month(2024, m), ReasonCode: code,
+		})
+		require.NoError(t, err)
+	}
+
+	netflix := []*models.Subscription{create("Netflix"), create("Netflix"), create("Netflix"), create("Netflix")}
+	create("Spotify")
+
+	cancel(netflix[0], time.February, models.CancelTooExpensive)
+	cancel(netflix[1], time.February, models.CancelNotUsing)
+	cancel(netflix[2], time.March, models.CancelTooExpensive)
+	require.NoError(t, subs.Delete(ctx, netflix[2].ID))
+
+	churn, err := db.Churn(ctx, &models.ChurnRequest{
+		ServiceName: "Netflix",
+		StartDate:   storagetest.Month(2024, time.February),
+		EndDate:     storagetest.Month(2024, time.March),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.Churn{
+		{
+			ServiceName: "Netflix",
+			Month:       storagetest.Month(2024, time.February),
+			Active:      4,
+			Cancelled:   2,
+			Rate:        0.5,
+			Reasons:     map[models.CancelReason]int{models.CancelTooExpensive: 1, models.CancelNotUsing: 1},
+		},
+		{
+			ServiceName: "Netflix",
+			Month:       storagetest.Month(2024, time.March),
+			Active:      2,
+			Cancelled:   1,
+			Rate:        0.5,
+			Reasons:     map[models.CancelReason]int{models.CancelTooExpensive: 1},
+		},
+	}, churn, "the deleted subscription still counts up to its last month")
+}
@@ -0,0 +1,106 @@
+package postgres_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	pgtest.Main(m)
+}
+
+func newStore(conn *sqlx.DB) storagetest.Store {
+	return storagetest.Store{
+		SubsAPI:   postgres.NewSubsAPI(conn),
+		Discounts: postgres.NewDiscountsAPI(conn),
+		Pauses:    postgres.NewPausesAPI(conn),
+		Members:   postgres.NewMembersAPI(conn),
+	}
+}
+
+func TestSubsAPI(t *testing.T) {
+	storagetest.RunStore(t, func(t *testing.T) storagetest.Store {
+		return newStore(pgtest.DB(t))
+	})
+}
+
+func TestSubsAPI_Outbox(t *testing.T) {
+	conn := pgtest.DB(t)
+	db := postgres.NewSubsAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	sub.Price = 1200
+	require.NoError(t, db.Update(ctx, sub))
+	require.NoError(t, db.Delete(ctx, sub.ID))
+
+	var events []struct {
+		EventType models.EventType `db:"event_type"`
+		Payload   []byte           `db:"payload"`
+	}
+	require.NoError(t, conn.Select(&events, "SELECT event_type, payload FROM outbox ORDER BY id"))
+	require.Len(t, events, 3)
+
+	wantTypes := []models.EventType{models.EventSubCreated, models.EventSubUpdated, models.EventSubDeleted}
+	for i, event := range events {
+		assert.Equal(t, wantTypes[i], event.EventType)
+
+		var payload models.Subscription
+		require.NoError(t, json.Unmarshal(event.Payload, &payload))
+		assert.Equal(t, sub.ID, payload.ID)
+	}
+}
+
+func TestSubsAPI_FailedWriteRollsBack(t *testing.T) {
+	conn := pgtest.DB(t)
+	db := postgres.NewSubsAPI(conn)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       -1,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	assert.Error(t, db.Create(ctx, sub))
+
+	var events int
+	require.NoError(t, conn.Get(&events, "SELECT count(*) FROM outbox"))
+	assert.Zero(t, events)
+}
+
+func TestSubsAPI_ReadPrimary(t *testing.T) {
+	conn := pgtest.DB(t)
+	db := postgres.NewReplicatedSubsAPI(conn, postgres.NewReplicaSet(conn, nil))
+	ctx := postgres.WithPrimary(context.Background())
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	read, err := db.Read(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub, read)
+}
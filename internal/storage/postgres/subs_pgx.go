@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BulkAPI loads many subscriptions at once.
+type BulkAPI interface {
+	// CreateMany inserts subs and their outbox events in one transaction,
+	// pipelined in a single round trip per step, and sets their IDs.
+	CreateMany(ctx context.Context, subs []*models.Subscription) error
+	// Import streams subs with COPY. Missing IDs are generated. No outbox
+	// events are written, so webhooks do not see imported subscriptions.
+	Import(ctx context.Context, subs []models.Subscription) (int64, error)
+}
+
+type PgxSubsAPI interface {
+	SubsAPI
+	BulkAPI
+}
+
+// pgxSubs is SubsAPI on a pgx pool: subsDB run over database/sql with the
+// pgx driver on the same pool, so that both drivers share one
+// implementation. Only the bulk paths use the pool directly.
+type pgxSubs struct {
+	*subsDB
+	pool *pgxpool.Pool
+}
+
+func NewPgxSubsAPI(pool *pgxpool.Pool) PgxSubsAPI {
+	return NewReplicatedPgxSubsAPI(pool, NewReplicaSet(PoolDB(pool), nil))
+}
+
+// NewReplicatedPgxSubsAPI is NewReplicatedSubsAPI for the pgx driver. The
+// primary of reads must be served by pool, see PoolDB.
+func NewReplicatedPgxSubsAPI(pool *pgxpool.Pool, reads *ReplicaSet) PgxSubsAPI {
+	return &pgxSubs{
+		subsDB: &subsDB{db: reads.primary, reads: reads},
+		pool:   pool,
+	}
+}
+
+func (s *pgxSubs) Close() error {
+	err := s.subsDB.Close()
+	s.pool.Close()
+	return err
+}
+
+func (s *pgxSubs) CreateMany(ctx context.Context, subs []*models.Subscription) (err error) {
+	ctx, span := startSpan(ctx, "CreateMany", insertQuery)
+	rows := 0
+	defer func() { endSpan(span, rows, err) }()
+
+	month := models.MonthOf(time.Now())
+
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		inserts := &pgx.Batch{}
+		for _, sub := range subs {
+			sub.Status = sub.StatusAt(month, nil)
+
+			inserts.Queue(
+				insertQuery,
+				sub.ServiceName, sub.Category, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+				sub.TrialMonths, sub.IntroPrice, sub.IntroMonths, sub.Status,
+			).QueryRow(func(row pgx.Row) error {
+				return row.Scan(&sub.ID)
+			})
+		}
+
+		if err := tx.SendBatch(ctx, inserts).Close(); err != nil {
+			return fmt.Errorf("insert subs fail: %w", err)
+		}
+
+		events := &pgx.Batch{}
+		for _, sub := range subs {
+			if err := queueEvent(events, models.EventSubCreated, sub); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.SendBatch(ctx, events).Close(); err != nil {
+			return fmt.Errorf("write outbox events fail: %w", err)
+		}
+
+		rows = len(subs)
+		return nil
+	})
+}
+
+func (s *pgxSubs) Import(ctx context.Context, subs []models.Subscription) (_ int64, err error) {
+	columns := strings.Split(subColumns, ", ")
+	month := models.MonthOf(time.Now())
+
+	ctx, span := startSpan(ctx, "Import", "COPY subscriptions ("+subColumns+") FROM STDIN")
+	var copied int64
+	defer func() { endSpan(span, int(copied), err) }()
+
+	copied, err = s.pool.CopyFrom(ctx, pgx.Identifier{"subscriptions"}, columns, pgx.CopyFromSlice(len(subs), func(i int) ([]any, error) {
+		sub := &subs[i]
+		if sub.ID == uuid.Nil {
+			sub.ID = uuid.New()
+		}
+
+		sub.Status = sub.StatusAt(month, nil)
+
+		return []any{
+			sub.ID, sub.ServiceName, sub.Category, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+			sub.TrialMonths, sub.IntroPrice, sub.IntroMonths, sub.Status,
+		}, nil
+	}))
+	if err != nil {
+		return 0, fmt.Errorf("copy subs fail: %w", err)
+	}
+
+	return copied, nil
+}
+
+func queueEvent(batch *pgx.Batch, eventType models.EventType, sub *models.Subscription) error {
+	const query = `
+		INSERT INTO outbox (event_type, payload)
+		VALUES ($1, $2)
+	`
+
+	payload, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("marshal event payload fail: %w", err)
+	}
+
+	batch.Queue(query, eventType, payload)
+	return nil
+}
@@ -0,0 +1,166 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres/pgtest"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPgxSubsAPI(t *testing.T) {
+	storagetest.RunSubsAPI(t, func(t *testing.T) postgres.SubsAPI {
+		return postgres.NewPgxSubsAPI(pgtest.Pool(t))
+	})
+}
+
+func outboxTypes(t *testing.T, pool *pgxpool.Pool) []models.EventType {
+	t.Helper()
+
+	rows, err := pool.Query(context.Background(), "SELECT event_type FROM outbox ORDER BY id")
+	require.NoError(t, err)
+
+	var types []models.EventType
+	for rows.Next() {
+		var eventType models.EventType
+		require.NoError(t, rows.Scan(&eventType))
+		types = append(types, eventType)
+	}
+	require.NoError(t, rows.Err())
+
+	return types
+}
+
+func TestPgxSubsAPI_Outbox(t *testing.T) {
+	pool := pgtest.Pool(t)
+	db := postgres.NewPgxSubsAPI(pool)
+	ctx := context.Background()
+
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	sub.Price = 1200
+	require.NoError(t, db.Update(ctx, sub))
+	require.NoError(t, db.Delete(ctx, sub.ID))
+
+	assert.Equal(t, []models.EventType{
+		models.EventSubCreated, models.EventSubUpdated, models.EventSubDeleted,
+	}, outboxTypes(t, pool))
+}
+
+func TestPgxSubsAPI_Reassign(t *testing.T) {
+	pool := pgtest.Pool(t)
+	db := postgres.NewPgxSubsAPI(pool)
+	ctx := context.Background()
+
+	owner, member, buyer := uuid.New(), uuid.New(), uuid.New()
+
+	sub := &models.Subscription{
+		ServiceName: "Spotify",
+		Price:       300,
+		UserID:      owner,
+		StartDate:   storagetest.Month(2024, time.January),
+	}
+	require.NoError(t, db.Create(ctx, sub))
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO subscription_members (subscription_id, user_id, weight)
+		VALUES ($1, $2, 1), ($1, $3, 2)
+	`, sub.ID, owner, member)
+	require.NoError(t, err)
+
+	sub.UserID = buyer
+	require.NoError(t, db.Update(ctx, sub))
+
+	rows, err := pool.Query(ctx, "SELECT user_id, weight FROM subscription_members WHERE subscription_id = $1", sub.ID)
+	require.NoError(t, err)
+
+	weights := map[uuid.UUID]int{}
+	for rows.Next() {
+		var (
+			userID uuid.UUID
+			weight int
+		)
+		require.NoError(t, rows.Scan(&userID, &weight))
+		weights[userID] = weight
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, map[uuid.UUID]int{buyer: 1, member: 2}, weights, "the owner's row moves to the new owner")
+}
+
+func TestPgxSubsAPI_CreateMany(t *testing.T) {
+	pool := pgtest.Pool(t)
+	db := postgres.NewPgxSubsAPI(pool)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	subs := []*models.Subscription{
+		{ServiceName: "Netflix", Price: 1000, UserID: userID, StartDate: storagetest.Month(2024, time.January)},
+		{ServiceName: "Spotify", Price: 300, UserID: userID, StartDate: storagetest.Month(2024, time.March)},
+	}
+	require.NoError(t, db.CreateMany(ctx, subs))
+
+	for _, sub := range subs {
+		assert.NotEqual(t, uuid.Nil, sub.ID)
+
+		read, err := db.Read(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sub, read)
+	}
+
+	assert.Equal(t, []models.EventType{models.EventSubCreated, models.EventSubCreated}, outboxTypes(t, pool))
+
+	// A failing row rolls back the whole batch.
+	err := db.CreateMany(ctx, []*models.Subscription{
+		{ServiceName: "YouTube", Price: 500, UserID: userID, StartDate: storagetest.Month(2024, time.January)},
+		{ServiceName: "Broken", Price: -1, UserID: userID, StartDate: storagetest.Month(2024, time.January)},
+	})
+	assert.Error(t, err)
+
+	list, err := db.List(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestPgxSubsAPI_Import(t *testing.T) {
+	db := postgres.NewPgxSubsAPI(pgtest.Pool(t))
+	ctx := context.Background()
+	userID := uuid.New()
+
+	subs := make([]models.Subscription, 100)
+	for i := range subs {
+		subs[i] = models.Subscription{
+			ServiceName: "Netflix",
+			Price:       100,
+			UserID:      userID,
+			StartDate:   storagetest.Month(2024, time.January),
+			EndDate:     storagetest.Month(2024, time.December),
+		}
+	}
+
+	copied, err := db.Import(ctx, subs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), copied)
+	assert.NotEqual(t, uuid.Nil, subs[0].ID)
+
+	total, err := db.Summary(ctx, &models.SumRequest{
+		UserID:    userID,
+		StartDate: storagetest.Month(2024, time.June),
+		EndDate:   storagetest.Month(2025, time.June),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100*100*7, total.Net)
+}
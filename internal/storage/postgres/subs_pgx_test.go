@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolConfig(t *testing.T) {
+	cfg := &config.Postgres{
+		Host:             "postgres",
+		Port:             "5432",
+		User:             "subs",
+		Password:         `it's a \secret`,
+		DB:               "subs",
+		SSLMode:          "disable",
+		MaxOpenConns:     10,
+		ConnMaxLifetime:  time.Hour,
+		ApplicationName:  "subs aggregator",
+		StatementTimeout: 30 * time.Second,
+		StatementCache:   128,
+	}
+
+	poolCfg, err := poolConfig(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, `it's a \secret`, poolCfg.ConnConfig.Password)
+	assert.Equal(t, int32(10), poolCfg.MaxConns)
+	assert.Equal(t, time.Hour, poolCfg.MaxConnLifetime)
+	assert.Equal(t, "subs aggregator", poolCfg.ConnConfig.RuntimeParams["application_name"])
+	assert.Equal(t, "30000", poolCfg.ConnConfig.RuntimeParams["statement_timeout"])
+	assert.Equal(t, 128, poolCfg.ConnConfig.StatementCacheCapacity)
+	assert.Equal(t, pgx.QueryExecModeCacheStatement, poolCfg.ConnConfig.DefaultQueryExecMode)
+
+	cfg.StatementCache = 0
+	poolCfg, err = poolConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, pgx.QueryExecModeDescribeExec, poolCfg.ConnConfig.DefaultQueryExecMode)
+}
+
+func TestMonthDate_PgxCodec(t *testing.T) {
+	m := pgtype.NewMap()
+	month := models.MonthDate{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		buf, err := m.Encode(pgtype.DateOID, format, month, nil)
+		require.NoError(t, err)
+
+		var scanned models.MonthDate
+		require.NoError(t, m.Scan(pgtype.DateOID, format, buf, &scanned))
+		assert.Equal(t, month, scanned)
+
+		buf, err = m.Encode(pgtype.DateOID, format, models.MonthDate{}, nil)
+		require.NoError(t, err)
+		assert.Nil(t, buf)
+
+		scanned = month
+		require.NoError(t, m.Scan(pgtype.DateOID, format, nil, &scanned))
+		assert.False(t, scanned.Valid)
+	}
+}
+
+func TestMonthRange_PgxCodec(t *testing.T) {
+	m := pgtype.NewMap()
+	from := models.MonthDate{Time: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	to := models.MonthDate{Time: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	tests := []struct {
+		name string
+		r    models.MonthRange
+		text string
+	}{
+		{"closed", models.MonthRange{From: from, To: to}, "[2024-01-01,2024-07-01)"},
+		{"open ended", models.MonthRange{From: from}, "[2024-01-01,)"},
+		{"empty", models.MonthRange{}, "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := m.Encode(pgtype.DaterangeOID, pgtype.TextFormatCode, tt.r, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, string(buf))
+
+			value, err := tt.r.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, value)
+
+			for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+				buf, err := m.Encode(pgtype.DaterangeOID, format, tt.r, nil)
+				require.NoError(t, err)
+
+				var scanned models.MonthRange
+				require.NoError(t, m.Scan(pgtype.DaterangeOID, format, buf, &scanned))
+				assert.Equal(t, tt.r, scanned)
+			}
+		})
+	}
+}
+
+func TestUUID_PgxCodec(t *testing.T) {
+	m := pgtype.NewMap()
+	id := uuid.New()
+
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		buf, err := m.Encode(pgtype.UUIDOID, format, id, nil)
+		require.NoError(t, err)
+
+		var scanned uuid.UUID
+		require.NoError(t, m.Scan(pgtype.UUIDOID, format, buf, &scanned))
+		assert.Equal(t, id, scanned)
+	}
+
+	buf, err := m.Encode(pgtype.UUIDArrayOID, pgtype.BinaryFormatCode, []uuid.UUID{id, uuid.New()}, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, buf)
+}
@@ -7,57 +7,355 @@ import (
 
 	"github.com/P3rCh1/subs-aggregator/internal/models"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-type sumData struct {
-	Price     int              `db:"price"`
-	StartDate models.MonthDate `db:"start_date"`
-	EndDate   models.MonthDate `db:"end_date"`
+// Summary reads spend_rollup when it covers every requested month and
+// falls back to raw subscriptions otherwise. The rollup holds gross amounts
+// of whole months, charged to the owner: prorated requests and requests for
+// a user sharing subscriptions are summed up in Go instead. Discounts and
+// pauses are always read from their tables.
+func (s *subsDB) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	itemized, err := s.itemized(ctx, req)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	if itemized {
+		return s.itemizedSummary(ctx, req)
+	}
+
+	var (
+		gross   int
+		covered bool
+	)
+
+	// The rollup has no statuses, filtering by them needs the raw rows.
+	if req.Status == "" {
+		if gross, covered, err = s.rollupSummary(ctx, req); err != nil {
+			return models.Summary{}, err
+		}
+	}
+
+	if !covered {
+		if gross, err = s.rawSummary(ctx, req); err != nil {
+			return models.Summary{}, err
+		}
+	}
+
+	paused, err := s.pausedSummary(ctx, req)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	gross -= paused
+
+	discount, err := s.discountSummary(ctx, req)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
+	return models.Summary{Gross: gross, Discount: discount, Net: gross - discount}, nil
 }
 
-func (s *subsDB) Summary(ctx context.Context, req *models.SumRequest) (int, error) {
-	conds := []string{
-		"start_date <= $2",
-		"(end_date IS NULL OR end_date >= $1)",
+// itemized reports whether req has to be summed up subscription by
+// subscription in Go.
+func (s *subsDB) itemized(ctx context.Context, req *models.SumRequest) (shares bool, err error) {
+	if req.Prorate {
+		return true, nil
 	}
-	args := []any{req.StartDate.Time, req.EndDate.Time}
 
-	if req.ServiceName != "" {
-		conds = append(conds, fmt.Sprintf("service_name = $%d", len(args)+1))
-		args = append(args, req.ServiceName)
+	if req.UserID == uuid.Nil {
+		return false, nil
 	}
 
-	if req.UserID != uuid.Nil {
-		conds = append(conds, fmt.Sprintf("user_id = $%d", len(args)+1))
-		args = append(args, req.UserID)
+	ctx, span := startSpan(ctx, "Summary.shares", sharesQuery)
+	defer func() { endSpan(span, 1, err) }()
+
+	if err := s.reads.Reader(ctx).GetContext(ctx, &shares, sharesQuery, req.UserID); err != nil {
+		return false, fmt.Errorf("summary shares fail: %w", err)
 	}
 
+	return shares, nil
+}
+
+// sharesQuery reports whether the user $1 owns or is a member of a shared
+// subscription.
+const sharesQuery = `
+	SELECT EXISTS (SELECT 1 FROM subscription_members WHERE user_id = $1)
+		OR EXISTS (
+			SELECT 1 FROM subscriptions s
+			JOIN subscription_members m ON m.subscription_id = s.id
+			WHERE s.user_id = $1
+		)
+`
+
+func (s *subsDB) rollupSummary(ctx context.Context, req *models.SumRequest) (_ int, _ bool, err error) {
+	conds, args := summaryFilters(
+		[]string{"month BETWEEN $1 AND $2", "(SELECT covered FROM state)"},
+		[]any{req.StartDate.Month().Time, req.EndDate.Month().Time},
+		req,
+	)
+
 	query := fmt.Sprintf(`
-		SELECT price, start_date, end_date
-		FROM subscriptions
+		WITH state AS (
+			SELECT COALESCE(through >= $2, FALSE) AS covered
+			FROM spend_rollup_state
+		)
+		SELECT (SELECT covered FROM state) AS covered, COALESCE(SUM(total), 0) AS total
+		FROM spend_rollup
 		WHERE %s
 	`, strings.Join(conds, " AND "))
 
-	var subs []sumData
-	if err := s.db.SelectContext(ctx, &subs, query, args...); err != nil {
+	var result struct {
+		Covered bool `db:"covered"`
+		Total   int  `db:"total"`
+	}
+
+	ctx, span := startSpan(ctx, "Summary.rollup", query)
+	defer func() { endSpan(span, 1, err) }()
+
+	if err := s.reads.Reader(ctx).GetContext(ctx, &result, query, args...); err != nil {
+		return 0, false, fmt.Errorf("summary rollup fail: %w", err)
+	}
+
+	return result.Total, result.Covered, nil
+}
+
+func (s *subsDB) rawSummary(ctx context.Context, req *models.SumRequest) (total int, err error) {
+	conds, args := summaryFilters([]string{"period && $1::daterange"}, []any{req.Period()}, req)
+	query := fmt.Sprintf(rawSummaryQuery, strings.Join(conds, " AND "))
+
+	ctx, span := startSpan(ctx, "Summary", query)
+	defer func() { endSpan(span, 1, err) }()
+
+	if err := s.reads.Reader(ctx).GetContext(ctx, &total, query, args...); err != nil {
 		return 0, fmt.Errorf("summary fetch fail: %w", err)
 	}
 
-	total := 0
+	return total, nil
+}
 
-	for _, sub := range subs {
-		firstPay := req.StartDate
-		if sub.StartDate.Valid && sub.StartDate.Time.After(req.StartDate.Time) {
-			firstPay = sub.StartDate
-		}
+// rawSummaryQuery charges every matching subscription for the months its
+// period shares with the requested one, passed as $1: nothing for trial
+// months, intro_price for intro months and price for the rest.
+const rawSummaryQuery = `
+	SELECT COALESCE(SUM(
+		price::bigint * (
+			month_count(period * $1::daterange)
+			- month_count(trial * period * $1::daterange)
+			- month_count(intro * period * $1::daterange)
+		)
+		+ intro_price::bigint * month_count(intro * period * $1::daterange)
+	), 0)::bigint
+	FROM subscriptions
+	WHERE %s
+`
 
-		end := req.EndDate
-		if sub.EndDate.Valid && sub.EndDate.Time.Before(req.EndDate.Time) {
-			end = sub.EndDate
-		}
+func (s *subsDB) pausedSummary(ctx context.Context, req *models.SumRequest) (total int, err error) {
+	conds, args := summaryFilters([]string{"period && $1::daterange"}, []any{req.Period()}, req)
+	query := fmt.Sprintf(pausedSummaryQuery, strings.Join(conds, " AND "))
+
+	ctx, span := startSpan(ctx, "Summary.pauses", query)
+	defer func() { endSpan(span, 1, err) }()
 
-		total += sub.Price * firstPay.MonthsBetween(end)
+	if err := s.reads.Reader(ctx).GetContext(ctx, &total, query, args...); err != nil {
+		return 0, fmt.Errorf("summary pauses fetch fail: %w", err)
 	}
 
 	return total, nil
 }
+
+// pausedSummaryQuery sums what every matching subscription with pauses
+// would charge for its paused months among the requested ones, passed as
+// $1, so that Summary can take it off the gross amount.
+const pausedSummaryQuery = `
+	SELECT COALESCE(SUM(subscription_price(subscriptions, m::date)), 0)::bigint
+	FROM subscriptions,
+		generate_series(
+			lower(period * $1::daterange)::timestamp,
+			upper(period * $1::daterange)::timestamp - INTERVAL '1 month',
+			INTERVAL '1 month'
+		) m
+	WHERE %s AND EXISTS (
+		SELECT 1 FROM subscription_pauses p
+		WHERE p.subscription_id = subscriptions.id AND p.period @> m::date
+	)
+`
+
+func (s *subsDB) discountSummary(ctx context.Context, req *models.SumRequest) (total int, err error) {
+	conds, args := summaryFilters([]string{"period && $1::daterange"}, []any{req.Period()}, req)
+	query := fmt.Sprintf(discountSummaryQuery, strings.Join(conds, " AND "))
+
+	ctx, span := startSpan(ctx, "Summary.discounts", query)
+	defer func() { endSpan(span, 1, err) }()
+
+	if err := s.reads.Reader(ctx).GetContext(ctx, &total, query, args...); err != nil {
+		return 0, fmt.Errorf("summary discounts fetch fail: %w", err)
+	}
+
+	return total, nil
+}
+
+// discountSummaryQuery sums the discounts of every matching subscription
+// with discounts over the months its period shares with the requested
+// one, passed as $1, but for paused months.
+const discountSummaryQuery = `
+	SELECT COALESCE(SUM(subscription_discount(subscriptions, m::date)), 0)::bigint
+	FROM subscriptions,
+		generate_series(
+			lower(period * $1::daterange)::timestamp,
+			upper(period * $1::daterange)::timestamp - INTERVAL '1 month',
+			INTERVAL '1 month'
+		) m
+	WHERE %s AND EXISTS (
+		SELECT 1 FROM discounts d
+		WHERE d.subscription_id = subscriptions.id AND d.period && subscriptions.period * $1::daterange
+	) AND NOT EXISTS (
+		SELECT 1 FROM subscription_pauses p
+		WHERE p.subscription_id = subscriptions.id AND p.period @> m::date
+	)
+`
+
+func (s *subsDB) itemizedSummary(ctx context.Context, req *models.SumRequest) (total models.Summary, err error) {
+	conds, args := itemizedFilters(req)
+	query := fmt.Sprintf(itemizedSummaryQuery, strings.Join(conds, " AND "))
+
+	var subs []models.Subscription
+
+	ctx, span := startSpan(ctx, "Summary.itemized", query)
+	defer func() { endSpan(span, len(subs), err) }()
+
+	reader := s.reads.Reader(ctx)
+
+	if err := reader.SelectContext(ctx, &subs, query, args...); err != nil {
+		return models.Summary{}, fmt.Errorf("summary fetch fail: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(subs))
+	for i := range subs {
+		ids[i] = subs[i].ID
+	}
+
+	var discounts []models.Discount
+	if err := reader.SelectContext(ctx, &discounts, summaryDiscountsQuery, pq.Array(ids), req.Period()); err != nil {
+		return models.Summary{}, fmt.Errorf("summary discounts fetch fail: %w", err)
+	}
+
+	var pauses []models.Pause
+	if err := reader.SelectContext(ctx, &pauses, summaryPausesQuery, pq.Array(ids), req.Period()); err != nil {
+		return models.Summary{}, fmt.Errorf("summary pauses fetch fail: %w", err)
+	}
+
+	var members []models.Member
+	if err := reader.SelectContext(ctx, &members, summaryMembersQuery, pq.Array(ids)); err != nil {
+		return models.Summary{}, fmt.Errorf("summary members fetch fail: %w", err)
+	}
+
+	return itemize(req, subs, discounts, pauses, members), nil
+}
+
+// itemizedFilters is summaryFilters for itemizedSummaryQuery: the user
+// filter also matches the subscriptions the user is a member of.
+func itemizedFilters(req *models.SumRequest) ([]string, []any) {
+	filters := *req
+	filters.UserID = uuid.Nil
+
+	conds, args := summaryFilters([]string{"period && $1::daterange"}, []any{req.Period()}, &filters)
+
+	if req.UserID != uuid.Nil {
+		conds = append(conds, fmt.Sprintf(
+			"(user_id = $%[1]d OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = $%[1]d))",
+			len(args)+1,
+		))
+		args = append(args, req.UserID)
+	}
+
+	return conds, args
+}
+
+// itemizedSummaryQuery selects the subscriptions charged in the requested
+// months, passed as $1, to be summed up in Go.
+const itemizedSummaryQuery = `
+	SELECT ` + subColumns + `
+	FROM subscriptions
+	WHERE %s
+`
+
+// summaryDiscountsQuery selects the discounts of the subscriptions $1
+// that apply to the requested months, passed as $2.
+const summaryDiscountsQuery = `
+	SELECT ` + discountColumns + `
+	FROM discounts
+	WHERE subscription_id = ANY($1) AND period && $2::daterange
+`
+
+// summaryPausesQuery selects the pauses of the subscriptions $1 within
+// the requested months, passed as $2.
+const summaryPausesQuery = `
+	SELECT ` + pauseColumns + `
+	FROM subscription_pauses
+	WHERE subscription_id = ANY($1) AND period && $2::daterange
+`
+
+// summaryMembersQuery selects the members of the subscriptions $1.
+const summaryMembersQuery = `
+	SELECT subscription_id, user_id, weight, created_at
+	FROM subscription_members
+	WHERE subscription_id = ANY($1)
+`
+
+// itemize sums up what subs charge for the months of req after discounts
+// and pauses. With a user filter only the user's share of every
+// subscription counts.
+func itemize(
+	req *models.SumRequest,
+	subs []models.Subscription,
+	discounts []models.Discount,
+	pauses []models.Pause,
+	members []models.Member,
+) models.Summary {
+	discountsBySub := bySub(discounts, func(d models.Discount) uuid.UUID { return d.SubscriptionID })
+	pausesBySub := bySub(pauses, func(p models.Pause) uuid.UUID { return p.SubscriptionID })
+	membersBySub := bySub(members, func(m models.Member) uuid.UUID { return m.SubscriptionID })
+
+	var total models.Summary
+
+	for i := range subs {
+		charge := subs[i].Charge(req, discountsBySub[subs[i].ID], pausesBySub[subs[i].ID])
+
+		if req.UserID != uuid.Nil {
+			charge = charge.Share(membersBySub[subs[i].ID], subs[i].UserID, req.UserID)
+		}
+
+		total = total.Add(charge)
+	}
+
+	return total
+}
+
+// summaryFilters appends the optional service, category, user and status
+// filters of req to conds and args.
+func summaryFilters(conds []string, args []any, req *models.SumRequest) ([]string, []any) {
+	if req.ServiceName != "" {
+		conds = append(conds, fmt.Sprintf("service_name = $%d", len(args)+1))
+		args = append(args, req.ServiceName)
+	}
+
+	if req.Category != "" {
+		conds = append(conds, fmt.Sprintf("category = $%d", len(args)+1))
+		args = append(args, req.Category)
+	}
+
+	if req.UserID != uuid.Nil {
+		conds = append(conds, fmt.Sprintf("user_id = $%d", len(args)+1))
+		args = append(args, req.UserID)
+	}
+
+	if req.Status != "" {
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)+1))
+		args = append(args, req.Status)
+	}
+
+	return conds, args
+}
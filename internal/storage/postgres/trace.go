@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/P3rCh1/subs-aggregator/internal/storage/postgres")
+
+// startSpan opens a client span for a SubsAPI method running query.
+func startSpan(ctx context.Context, method, query string) (context.Context, trace.Span) {
+	return tracer.Start(
+		ctx,
+		"SubsAPI."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+// endSpan records how many rows the statement returned or affected. A
+// missing row is an expected outcome and does not mark the span as failed.
+func endSpan(span trace.Span, rows int, err error) {
+	span.SetAttributes(attribute.Int("db.rows", rows))
+
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WebhooksAPI interface {
+	CreateWebhook(ctx context.Context, hook *models.Webhook) error
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	// PublishEvent writes an outbox event with tx, so that it is committed
+	// or rolled back together with the other writes of the caller.
+	PublishEvent(ctx context.Context, tx sqlx.ExecerContext, eventType models.EventType, sub *models.Subscription) error
+	FanOutEvents(ctx context.Context, limit int) (int, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.Delivery, error)
+	// MarkDelivered and MarkFailed record the result of a delivery claimed
+	// with lease, its next attempt time returned by ClaimDeliveries. They
+	// return ErrLeaseLost when the delivery was claimed again since then.
+	MarkDelivered(ctx context.Context, id uuid.UUID, lease time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lease time.Time, reason string, next time.Time, dead bool) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, status models.DeliveryStatus) ([]models.Delivery, error)
+	Redeliver(ctx context.Context, id uuid.UUID) error
+}
+
+var ErrLeaseLost = errors.New("delivery lease lost")
+
+type webhooksDB struct {
+	db *sqlx.DB
+}
+
+func NewWebhooksAPI(db *sqlx.DB) WebhooksAPI {
+	return &webhooksDB{db}
+}
+
+func (w *webhooksDB) CreateWebhook(ctx context.Context, hook *models.Webhook) error {
+	const query = `
+		INSERT INTO webhooks (url, secret, events)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	if err := w.db.QueryRowContext(
+		ctx,
+		query,
+		hook.URL, hook.Secret, pq.Array(hook.Events),
+	).Scan(&hook.ID, &hook.CreatedAt); err != nil {
+		return fmt.Errorf("insert webhook fail: %w", err)
+	}
+
+	return nil
+}
+
+func (w *webhooksDB) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	const query = `
+		SELECT id, url, events, created_at
+		FROM webhooks
+		ORDER BY created_at
+	`
+
+	rows, err := w.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks fail: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []models.Webhook{}
+	for rows.Next() {
+		var (
+			hook   models.Webhook
+			events []string
+		)
+
+		if err := rows.Scan(&hook.ID, &hook.URL, pq.Array(&events), &hook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan webhook fail: %w", err)
+		}
+
+		for _, event := range events {
+			hook.Events = append(hook.Events, models.EventType(event))
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list webhooks fail: %w", err)
+	}
+
+	return hooks, nil
+}
+
+func (w *webhooksDB) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		DELETE FROM webhooks WHERE id = $1
+	`
+
+	res, err := w.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook fail: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (w *webhooksDB) PublishEvent(
+	ctx context.Context,
+	tx sqlx.ExecerContext,
+	eventType models.EventType,
+	sub *models.Subscription,
+) error {
+	return writeEvent(ctx, tx, eventType, sub)
+}
+
+// FanOutEvents turns unprocessed outbox events into one pending delivery per
+// subscribed webhook and marks the events as processed in the same statement.
+func (w *webhooksDB) FanOutEvents(ctx context.Context, limit int) (int, error) {
+	const query = `
+		WITH events AS (
+			SELECT id, event_type FROM outbox
+			WHERE processed_at IS NULL
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), fanout AS (
+			INSERT INTO webhook_deliveries (webhook_id, event_id)
+			SELECT hooks.id, events.id
+			FROM events
+			JOIN webhooks hooks ON events.event_type = ANY(hooks.events)
+		)
+		UPDATE outbox SET processed_at = now()
+		FROM events
+		WHERE outbox.id = events.id
+	`
+
+	res, err := w.db.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("fan out events fail: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("database error: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ClaimDeliveries returns pending deliveries that are due and pushes their
+// next attempt forward by lease, so that concurrent dispatchers skip them.
+func (w *webhooksDB) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.Delivery, error) {
+	const query = `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = now() + make_interval(secs => $2), updated_at = now()
+			FROM due
+			WHERE d.id = due.id
+			RETURNING d.*
+		)
+		SELECT
+			claimed.id, claimed.webhook_id, claimed.event_id, claimed.status,
+			claimed.attempts, claimed.next_attempt_at, claimed.created_at,
+			COALESCE(claimed.last_error, '') AS last_error,
+			outbox.event_type, outbox.payload, hooks.url, hooks.secret
+		FROM claimed
+		JOIN outbox ON outbox.id = claimed.event_id
+		JOIN webhooks hooks ON hooks.id = claimed.webhook_id
+	`
+
+	deliveries := []models.Delivery{}
+	if err := w.db.SelectContext(ctx, &deliveries, query, limit, lease.Seconds()); err != nil {
+		return nil, fmt.Errorf("claim deliveries fail: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (w *webhooksDB) MarkDelivered(ctx context.Context, id uuid.UUID, lease time.Time) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, last_error = NULL, updated_at = now()
+		WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+	`
+
+	res, err := w.db.ExecContext(ctx, query, id, lease)
+	if err != nil {
+		return fmt.Errorf("mark delivered fail: %w", err)
+	}
+
+	return leaseHeld(res)
+}
+
+func (w *webhooksDB) MarkFailed(
+	ctx context.Context,
+	id uuid.UUID,
+	lease time.Time,
+	reason string,
+	next time.Time,
+	dead bool,
+) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = $3, attempts = attempts + 1, last_error = $4, next_attempt_at = $5, updated_at = now()
+		WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+	`
+
+	status := models.DeliveryPending
+	if dead {
+		status = models.DeliveryDead
+	}
+
+	res, err := w.db.ExecContext(ctx, query, id, lease, status, reason, next)
+	if err != nil {
+		return fmt.Errorf("mark failed fail: %w", err)
+	}
+
+	return leaseHeld(res)
+}
+
+// leaseHeld reports ErrLeaseLost when a delivery result matched no row: the
+// lease expired and another dispatcher claimed the delivery in the meantime.
+func leaseHeld(res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (w *webhooksDB) ListDeliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	status models.DeliveryStatus,
+) ([]models.Delivery, error) {
+	const query = `
+		SELECT
+			d.id, d.webhook_id, d.event_id, d.status, d.attempts,
+			d.next_attempt_at, d.created_at, COALESCE(d.last_error, '') AS last_error,
+			outbox.event_type, outbox.payload
+		FROM webhook_deliveries d
+		JOIN outbox ON outbox.id = d.event_id
+		WHERE d.webhook_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.created_at DESC
+	`
+
+	deliveries := []models.Delivery{}
+	if err := w.db.SelectContext(ctx, &deliveries, query, webhookID, status); err != nil {
+		return nil, fmt.Errorf("list deliveries fail: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (w *webhooksDB) Redeliver(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = now(), updated_at = now()
+		WHERE id = $1
+	`
+
+	res, err := w.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("redeliver fail: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
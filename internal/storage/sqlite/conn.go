@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Open opens the database file at path, creating it and the schema when
+// missing. ":memory:" gives a private database that lives as long as the
+// returned handle.
+func Open(ctx context.Context, path string) (*sqlx.DB, error) {
+	db, err := sqlx.Open("sqlite", "file:"+path+"?_time_format=sqlite&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite fail: %w", err)
+	}
+
+	// SQLite serialises writers anyway, and an in-memory database exists
+	// only on the connection that created it.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create sqlite schema fail: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id TEXT PRIMARY KEY,
+    service_name TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    price INTEGER NOT NULL CHECK (price >= 0),
+    user_id TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    trial_months INTEGER NOT NULL DEFAULT 0 CHECK (trial_months >= 0),
+    intro_price INTEGER NOT NULL DEFAULT 0 CHECK (intro_price >= 0),
+    intro_months INTEGER NOT NULL DEFAULT 0 CHECK (intro_months >= 0),
+    status TEXT NOT NULL DEFAULT 'active'
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions (user_id);
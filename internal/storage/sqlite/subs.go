@@ -0,0 +1,241 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// subsDB stores subscriptions in an embedded SQLite database. It does not
+// write outbox events, so webhooks are not available with this backend.
+// Nothing moves statuses along either, so the stored status only holds as
+// of the last write and reads work out the current one.
+type subsDB struct {
+	db *sqlx.DB
+}
+
+func NewSubsAPI(db *sqlx.DB) postgres.SubsAPI {
+	return &subsDB{db}
+}
+
+func (s *subsDB) Close() error {
+	return s.db.Close()
+}
+
+func (s *subsDB) Create(ctx context.Context, sub *models.Subscription) error {
+	const query = `
+		INSERT INTO subscriptions (
+			id, service_name, category, price, user_id, start_date, end_date, trial_months, intro_price, intro_months,
+			status
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	id := uuid.New()
+	status := sub.StatusAt(models.MonthOf(time.Now()), nil)
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		id, sub.ServiceName, sub.Category, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+		sub.TrialMonths, sub.IntroPrice, sub.IntroMonths, status,
+	); err != nil {
+		return fmt.Errorf("insert sub fail: %w", err)
+	}
+
+	sub.ID, sub.Status = id, status
+	return nil
+}
+
+func (s *subsDB) Read(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	const query = `
+		SELECT * FROM subscriptions
+		WHERE id = ?
+	`
+
+	var sub models.Subscription
+	if err := s.db.GetContext(ctx, &sub, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, postgres.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("read sub fail: %w", err)
+	}
+
+	sub.Status = sub.Restatus(models.MonthOf(time.Now()), nil)
+	return &sub, nil
+}
+
+func (s *subsDB) Update(ctx context.Context, sub *models.Subscription) error {
+	// A cancellation stands, any other status follows the new dates.
+	const query = `
+		UPDATE subscriptions
+		SET service_name = ?, category = ?, price = ?, user_id = ?, start_date = ?, end_date = ?,
+			trial_months = ?, intro_price = ?, intro_months = ?,
+			status = CASE WHEN status = 'cancelled' THEN status ELSE ? END
+		WHERE id = ?
+		RETURNING status
+	`
+
+	sub.Status = sub.StatusAt(models.MonthOf(time.Now()), nil)
+
+	if err := s.db.QueryRowContext(
+		ctx,
+		query,
+		sub.ServiceName, sub.Category, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+		sub.TrialMonths, sub.IntroPrice, sub.IntroMonths, sub.Status, sub.ID,
+	).Scan(&sub.Status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return postgres.ErrNotFound
+		}
+
+		return fmt.Errorf("update sub fail: %w", err)
+	}
+
+	return nil
+}
+
+func (s *subsDB) Delete(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		DELETE FROM subscriptions WHERE id = ?
+	`
+
+	res, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete sub fail: %w", err)
+	}
+
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return postgres.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *subsDB) List(ctx context.Context, userID uuid.UUID) ([]models.Subscription, error) {
+	const query = `
+		SELECT * FROM subscriptions
+		WHERE user_id = ?
+	`
+
+	subs := []models.Subscription{}
+	if err := s.db.SelectContext(ctx, &subs, query, userID); err != nil {
+		return nil, fmt.Errorf("list subs fail: %w", err)
+	}
+
+	month := models.MonthOf(time.Now())
+	for i := range subs {
+		subs[i].Status = subs[i].Restatus(month, nil)
+	}
+
+	return subs, nil
+}
+
+// ListUsers lists owned subscriptions without discounts, pauses and
+// members: they live in PostgreSQL only.
+func (s *subsDB) ListUsers(ctx context.Context, userIDs []uuid.UUID) ([]models.UserSubscription, error) {
+	subs := []models.UserSubscription{}
+	if len(userIDs) == 0 {
+		return subs, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT *, user_id AS payer FROM subscriptions
+		WHERE user_id IN (?)
+	`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build list users query fail: %w", err)
+	}
+
+	if err := s.db.SelectContext(ctx, &subs, query, args...); err != nil {
+		return nil, fmt.Errorf("list users subs fail: %w", err)
+	}
+
+	month := models.MonthOf(time.Now())
+	for i := range subs {
+		subs[i].Status = subs[i].Restatus(month, nil)
+	}
+
+	return subs, nil
+}
+
+// Summary is never discounted: discounts live in PostgreSQL only.
+func (s *subsDB) Summary(ctx context.Context, req *models.SumRequest) (models.Summary, error) {
+	conds := []string{
+		"start_date < ?",
+		"(end_date IS NULL OR end_date >= ?)",
+	}
+	args := []any{req.EndDate.Month().Time.AddDate(0, 1, 0), req.StartDate.Month()}
+
+	if req.ServiceName != "" {
+		conds = append(conds, "service_name = ?")
+		args = append(args, req.ServiceName)
+	}
+
+	if req.Category != "" {
+		conds = append(conds, "category = ?")
+		args = append(args, req.Category)
+	}
+
+	if req.UserID != uuid.Nil {
+		conds = append(conds, "user_id = ?")
+		args = append(args, req.UserID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT price, start_date, end_date, trial_months, intro_price, intro_months, status
+		FROM subscriptions
+		WHERE %s
+	`, strings.Join(conds, " AND "))
+
+	var subs []models.Subscription
+	if err := s.db.SelectContext(ctx, &subs, query, args...); err != nil {
+		return models.Summary{}, fmt.Errorf("summary fetch fail: %w", err)
+	}
+
+	month := models.MonthOf(time.Now())
+
+	var total models.Summary
+
+	for _, sub := range subs {
+		// The status filter goes by the current status, not the stored one.
+		if req.Status != "" && sub.Restatus(month, nil) != req.Status {
+			continue
+		}
+
+		total = total.Add(sub.Charge(req, nil, nil))
+	}
+
+	return total, nil
+}
+
+func (s *subsDB) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping fail: %w", err)
+	}
+
+	return nil
+}
+
+// MigrationVersion reports the expected version: Open creates the schema
+// this binary needs.
+func (s *subsDB) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return postgres.SchemaVersion, false, nil
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/storagetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsAPI(t *testing.T) {
+	storagetest.RunSubsAPI(t, func(t *testing.T) postgres.SubsAPI {
+		db, err := Open(context.Background(), filepath.Join(t.TempDir(), "subs.db"))
+		require.NoError(t, err)
+
+		api := NewSubsAPI(db)
+		t.Cleanup(func() { api.Close() })
+
+		return api
+	})
+}
+
+func TestSubsAPI_CurrentStatus(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "subs.db"))
+	require.NoError(t, err)
+
+	api := NewSubsAPI(db)
+	t.Cleanup(func() { api.Close() })
+
+	// Stored as active when it was created, it ended in 2020.
+	sub := &models.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		UserID:      uuid.New(),
+		StartDate:   storagetest.Month(2020, time.January),
+		EndDate:     storagetest.Month(2020, time.June),
+	}
+	require.NoError(t, api.Create(ctx, sub))
+	_, err = db.ExecContext(ctx, `UPDATE subscriptions SET status = 'active' WHERE id = ?`, sub.ID)
+	require.NoError(t, err)
+
+	read, err := api.Read(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusExpired, read.Status)
+
+	list, err := api.List(ctx, sub.UserID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, models.StatusExpired, list[0].Status)
+
+	req := &models.SumRequest{StartDate: storagetest.Month(2020, time.January), EndDate: storagetest.Month(2020, time.December)}
+
+	req.Status = models.StatusActive
+	sum, err := api.Summary(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, sum.Net)
+
+	req.Status = models.StatusExpired
+	sum, err = api.Summary(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 6000, sum.Net)
+}
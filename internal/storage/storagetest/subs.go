@@ -0,0 +1,516 @@
+// Package storagetest holds the conformance suite every SubsAPI backend
+// has to pass.
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Month returns the first day of the month as a set MonthDate.
+func Month(y int, m time.Month) models.MonthDate {
+	return models.MonthDate{Time: time.Date(y, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+// Day returns the day as a set MonthDate of day precision.
+func Day(y int, m time.Month, d int) models.MonthDate {
+	return models.MonthDate{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func defaultSub(userID uuid.UUID) *models.Subscription {
+	return &models.Subscription{
+		ServiceName: "Netflix",
+		Category:    "video",
+		Price:       1000,
+		UserID:      userID,
+		StartDate:   Month(2024, time.January),
+	}
+}
+
+// Store is a backend under test: its SubsAPI and the stores of the
+// adjustments its Summary has to apply. An adjustment store is nil when
+// the backend does not support it, and its cases are skipped.
+type Store struct {
+	postgres.SubsAPI
+
+	Discounts postgres.DiscountsAPI
+	Pauses    postgres.PausesAPI
+	Members   postgres.MembersAPI
+}
+
+// RunSubsAPI runs the suite against the backend returned by newDB, which
+// stores no adjustments. Every subtest gets a fresh, empty store.
+func RunSubsAPI(t *testing.T, newDB func(t *testing.T) postgres.SubsAPI) {
+	RunStore(t, func(t *testing.T) Store {
+		return Store{SubsAPI: newDB(t)}
+	})
+}
+
+// RunStore is RunSubsAPI for a backend with adjustment stores.
+func RunStore(t *testing.T, newStore func(t *testing.T) Store) {
+	newDB := func(t *testing.T) postgres.SubsAPI { return newStore(t).SubsAPI }
+
+	t.Run("CreateRead", func(t *testing.T) { testCreateRead(t, newDB(t)) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newDB(t)) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newDB(t)) })
+	t.Run("UpdateStatus", func(t *testing.T) { testUpdateStatus(t, newDB(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newDB(t)) })
+	t.Run("List", func(t *testing.T) { testList(t, newDB(t)) })
+	t.Run("ListUsersAdjustments", func(t *testing.T) { testListUsersAdjustments(t, newStore(t)) })
+	t.Run("Summary", func(t *testing.T) { testSummary(t, newDB) })
+	t.Run("SummaryProrated", func(t *testing.T) { testSummaryProrated(t, newDB(t)) })
+	t.Run("SummaryTrial", func(t *testing.T) { testSummaryTrial(t, newDB(t)) })
+	t.Run("SummaryDiscounts", func(t *testing.T) { testSummaryDiscounts(t, newStore(t)) })
+	t.Run("SummaryPauses", func(t *testing.T) { testSummaryPauses(t, newStore(t)) })
+	t.Run("SummaryShares", func(t *testing.T) { testSummaryShares(t, newStore(t)) })
+	t.Run("Health", func(t *testing.T) { testHealth(t, newDB(t)) })
+}
+
+func testCreateRead(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+
+	sub := defaultSub(uuid.New())
+	require.NoError(t, db.Create(ctx, sub))
+	assert.NotEqual(t, uuid.Nil, sub.ID)
+
+	read, err := db.Read(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub, read)
+	assert.False(t, read.EndDate.Valid)
+	assert.Equal(t, models.StatusActive, read.Status)
+
+	ended := defaultSub(uuid.New())
+	ended.EndDate = Month(2024, time.June)
+	require.NoError(t, db.Create(ctx, ended))
+	assert.NotEqual(t, sub.ID, ended.ID)
+
+	read, err = db.Read(ctx, ended.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ended, read)
+	assert.Equal(t, models.StatusExpired, read.Status)
+}
+
+func testNotFound(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	_, err := db.Read(ctx, id)
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+
+	sub := defaultSub(uuid.New())
+	sub.ID = id
+	assert.ErrorIs(t, db.Update(ctx, sub), postgres.ErrNotFound)
+	assert.ErrorIs(t, db.Delete(ctx, id), postgres.ErrNotFound)
+}
+
+func testUpdate(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+
+	sub := defaultSub(uuid.New())
+	require.NoError(t, db.Create(ctx, sub))
+
+	sub.ServiceName = "Netflix Premium"
+	sub.Category = "streaming"
+	sub.Price = 1500
+	sub.EndDate = Month(2024, time.December)
+	require.NoError(t, db.Update(ctx, sub))
+
+	read, err := db.Read(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub, read)
+
+	sub.EndDate = models.MonthDate{}
+	require.NoError(t, db.Update(ctx, sub))
+
+	read, err = db.Read(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.False(t, read.EndDate.Valid)
+}
+
+func testUpdateStatus(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+	now := models.MonthOf(time.Now())
+
+	sub := defaultSub(uuid.New())
+	sub.EndDate = Month(2024, time.June)
+	require.NoError(t, db.Create(ctx, sub))
+	require.Equal(t, models.StatusExpired, sub.Status)
+
+	tests := []struct {
+		name  string
+		start models.MonthDate
+		end   models.MonthDate
+		want  models.Status
+	}{
+		{name: "extended", start: sub.StartDate, end: models.MonthDate{Time: now.Time.AddDate(1, 0, 0), Valid: true}, want: models.StatusActive},
+		{name: "ended again", start: sub.StartDate, end: Month(2024, time.June), want: models.StatusExpired},
+		{name: "open", start: sub.StartDate, want: models.StatusActive},
+		{name: "moved to the future", start: models.MonthDate{Time: now.Time.AddDate(1, 0, 0), Valid: true}, want: models.StatusScheduled},
+	}
+
+	for _, test := range tests {
+		sub.StartDate, sub.EndDate = test.start, test.end
+		require.NoError(t, db.Update(ctx, sub), test.name)
+		assert.Equal(t, test.want, sub.Status, test.name)
+
+		read, err := db.Read(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, read.Status, test.name)
+	}
+}
+
+func testDelete(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+
+	sub := defaultSub(uuid.New())
+	require.NoError(t, db.Create(ctx, sub))
+	require.NoError(t, db.Delete(ctx, sub.ID))
+
+	_, err := db.Read(ctx, sub.ID)
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+	assert.ErrorIs(t, db.Delete(ctx, sub.ID), postgres.ErrNotFound)
+}
+
+func testList(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	var created []models.Subscription
+	for _, userID := range []uuid.UUID{alice, alice, bob} {
+		sub := defaultSub(userID)
+		require.NoError(t, db.Create(ctx, sub))
+		created = append(created, *sub)
+	}
+
+	list, err := db.List(ctx, alice)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, created[:2], list)
+
+	list, err = db.List(ctx, carol)
+	require.NoError(t, err)
+	assert.NotNil(t, list)
+	assert.Empty(t, list)
+
+	users, err := db.ListUsers(ctx, []uuid.UUID{alice, bob, carol})
+	require.NoError(t, err)
+	require.Len(t, users, len(created))
+	for _, sub := range users {
+		assert.Contains(t, created, sub.Subscription)
+		assert.Equal(t, sub.UserID, sub.Payer)
+		assert.Empty(t, sub.Discounts)
+		assert.Empty(t, sub.Pauses)
+	}
+
+	users, err = db.ListUsers(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func testListUsersAdjustments(t *testing.T, store Store) {
+	if store.Discounts == nil || store.Pauses == nil {
+		t.Skip("backend does not store discounts and pauses")
+	}
+
+	ctx := context.Background()
+
+	sub := defaultSub(uuid.New())
+	require.NoError(t, store.Create(ctx, sub))
+
+	discount := models.Discount{SubscriptionID: sub.ID, Kind: models.DiscountFixed, Amount: 200, StartDate: Month(2024, time.March)}
+	require.NoError(t, store.Discounts.CreateDiscount(ctx, &discount))
+
+	pause := models.Pause{SubscriptionID: sub.ID, StartDate: Month(2024, time.June), EndDate: Month(2024, time.August)}
+	require.NoError(t, store.Pauses.CreatePause(ctx, &pause))
+
+	users, err := store.ListUsers(ctx, []uuid.UUID{sub.UserID})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+
+	req := &models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.December)}
+
+	sum, err := store.Summary(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, sum, users[0].Due(req), "ListUsers must carry what Summary applies")
+	assert.Equal(t, models.Summary{Gross: 9000, Discount: 1400, Net: 7600}, sum)
+}
+
+func testSummary(t *testing.T, newDB func(t *testing.T) postgres.SubsAPI) {
+	alice, bob := uuid.New(), uuid.New()
+
+	subs := []models.Subscription{
+		// Jan 2024 – open: 1000/month.
+		{ServiceName: "Netflix", Category: "video", Price: 1000, UserID: alice, StartDate: Month(2024, time.January)},
+		// Mar 2024 – May 2024: 300/month.
+		{ServiceName: "Spotify", Category: "music", Price: 300, UserID: alice, StartDate: Month(2024, time.March), EndDate: Month(2024, time.May)},
+		// Jun 2023 – Dec 2023: ended before 2024.
+		{ServiceName: "Netflix", Category: "video", Price: 900, UserID: bob, StartDate: Month(2023, time.June), EndDate: Month(2023, time.December)},
+		// Feb 2025 – open: starts after 2024.
+		{ServiceName: "Netflix", Category: "video", Price: 1100, UserID: bob, StartDate: Month(2025, time.February)},
+		// Nov 2023 – Jan 2024: only January falls in 2024.
+		{ServiceName: "Yandex Plus", Category: "bundle", Price: 400, UserID: bob, StartDate: Month(2023, time.November), EndDate: Month(2024, time.January)},
+		// Free tier.
+		{ServiceName: "Spotify", Category: "music", Price: 0, UserID: bob, StartDate: Month(2024, time.January)},
+	}
+
+	year := func(r models.SumRequest) models.SumRequest {
+		r.StartDate, r.EndDate = Month(2024, time.January), Month(2024, time.December)
+		return r
+	}
+
+	tests := []struct {
+		name string
+		req  models.SumRequest
+		want int
+	}{
+		{name: "whole year", req: year(models.SumRequest{}), want: 12*1000 + 3*300 + 400},
+		{name: "by user", req: year(models.SumRequest{UserID: alice}), want: 12*1000 + 3*300},
+		{name: "by service", req: year(models.SumRequest{ServiceName: "Spotify"}), want: 3 * 300},
+		{name: "by category", req: year(models.SumRequest{Category: "bundle"}), want: 400},
+		{name: "combined filters", req: year(models.SumRequest{UserID: bob, ServiceName: "Netflix"}), want: 0},
+		{name: "unknown user", req: year(models.SumRequest{UserID: uuid.New()}), want: 0},
+		{
+			name: "single month on end boundary",
+			req:  models.SumRequest{StartDate: Month(2024, time.May), EndDate: Month(2024, time.May)},
+			want: 1000 + 300,
+		},
+		{
+			name: "single month on start boundary",
+			req:  models.SumRequest{StartDate: Month(2024, time.March), EndDate: Month(2024, time.March)},
+			want: 1000 + 300,
+		},
+		{
+			name: "range before every subscription",
+			req:  models.SumRequest{StartDate: Month(2020, time.January), EndDate: Month(2020, time.December)},
+			want: 0,
+		},
+		{
+			name: "range across years",
+			req:  models.SumRequest{StartDate: Month(2023, time.December), EndDate: Month(2025, time.February)},
+			want: 14*1000 + 3*300 + 900 + 2*400 + 1100,
+		},
+	}
+
+	db := newDB(t)
+	ctx := context.Background()
+
+	for i := range subs {
+		require.NoError(t, db.Create(ctx, &subs[i]))
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sum, err := db.Summary(ctx, &test.req)
+			require.NoError(t, err)
+			assert.Equal(t, models.Undiscounted(test.want), sum)
+		})
+	}
+
+	t.Run("empty store", func(t *testing.T) {
+		req := year(models.SumRequest{})
+		sum, err := newDB(t).Summary(ctx, &req)
+		require.NoError(t, err)
+		assert.Zero(t, sum.Net)
+	})
+}
+
+func testSummaryProrated(t *testing.T, db postgres.SubsAPI) {
+	user := uuid.New()
+
+	subs := []models.Subscription{
+		// Jan 1 – Mar 16 2024: 16 of 31 days in March.
+		{ServiceName: "Netflix", Price: 3100, UserID: user, StartDate: Day(2024, time.January, 1), EndDate: Day(2024, time.March, 16)},
+		// Feb 15 2024 – open: 15 of 29 days in February.
+		{ServiceName: "Spotify", Price: 2900, UserID: user, StartDate: Day(2024, time.February, 15)},
+		// Jan – Feb 2024 of month precision: the end month is whole.
+		{ServiceName: "Yandex Plus", Price: 1000, UserID: user, StartDate: Month(2024, time.January), EndDate: Month(2024, time.February)},
+	}
+
+	ctx := context.Background()
+
+	for i := range subs {
+		require.NoError(t, db.Create(ctx, &subs[i]))
+	}
+
+	req := models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.March)}
+
+	sum, err := db.Summary(ctx, &req)
+	require.NoError(t, err)
+	assert.Equal(t, 3*3100+2*2900+2*1000, sum.Net)
+
+	req.Prorate = true
+	sum, err = db.Summary(ctx, &req)
+	require.NoError(t, err)
+	assert.Equal(t, 2*3100+1600+1500+2900+2*1000, sum.Net)
+}
+
+func testSummaryTrial(t *testing.T, db postgres.SubsAPI) {
+	user := uuid.New()
+
+	subs := []models.Subscription{
+		// Jan – Feb 2024 free, Mar – May 2024 at 500, then 1000/month.
+		{
+			ServiceName: "Netflix", Price: 1000, UserID: user, StartDate: Month(2024, time.January),
+			TrialMonths: 2, IntroPrice: 500, IntroMonths: 3,
+		},
+		// Nov 2023 – Jan 2024 free, Feb 2024: 300.
+		{
+			ServiceName: "Spotify", Price: 300, UserID: user, StartDate: Month(2023, time.November),
+			EndDate: Month(2024, time.February), TrialMonths: 3,
+		},
+	}
+
+	ctx := context.Background()
+
+	for i := range subs {
+		require.NoError(t, db.Create(ctx, &subs[i]))
+	}
+
+	read, err := db.Read(ctx, subs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, subs[0], *read)
+
+	tests := []struct {
+		name string
+		req  models.SumRequest
+		want int
+	}{
+		{"whole year", models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.December)}, 3*500 + 7*1000 + 300},
+		{"trial months", models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.January)}, 0},
+		{"trial ends", models.SumRequest{StartDate: Month(2024, time.February), EndDate: Month(2024, time.February)}, 300},
+		{"intro months", models.SumRequest{StartDate: Month(2024, time.March), EndDate: Month(2024, time.April)}, 2 * 500},
+		{"after intro", models.SumRequest{StartDate: Month(2024, time.June), EndDate: Month(2024, time.June)}, 1000},
+		{
+			name: "prorated",
+			req:  models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.December), Prorate: true},
+			want: 3*500 + 7*1000 + 300,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sum, err := db.Summary(ctx, &test.req)
+			require.NoError(t, err)
+			assert.Equal(t, models.Undiscounted(test.want), sum)
+		})
+	}
+}
+
+func testSummaryDiscounts(t *testing.T, store Store) {
+	if store.Discounts == nil {
+		t.Skip("backend does not store discounts, its summary ignores them")
+	}
+
+	ctx := context.Background()
+
+	sub := defaultSub(uuid.New())
+	require.NoError(t, store.Create(ctx, sub))
+
+	discounts := []models.Discount{
+		// 200 off from March 2024.
+		{SubscriptionID: sub.ID, Kind: models.DiscountFixed, Amount: 200, StartDate: Month(2024, time.March)},
+		// 10% off in December 2024.
+		{SubscriptionID: sub.ID, Kind: models.DiscountPercent, Amount: 10, StartDate: Month(2024, time.December), EndDate: Month(2024, time.December)},
+	}
+	for i := range discounts {
+		require.NoError(t, store.Discounts.CreateDiscount(ctx, &discounts[i]))
+	}
+
+	for _, prorate := range []bool{false, true} {
+		req := &models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.December), Prorate: prorate}
+
+		sum, err := store.Summary(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, sub.Charge(req, discounts, nil), sum, "prorate %v", prorate)
+		assert.Equal(t, 12*1000, sum.Gross, "prorate %v", prorate)
+		assert.Less(t, sum.Net, sum.Gross, "prorate %v", prorate)
+	}
+}
+
+func testSummaryPauses(t *testing.T, store Store) {
+	if store.Pauses == nil {
+		t.Skip("backend does not store pauses, its summary ignores them")
+	}
+
+	ctx := context.Background()
+
+	sub := defaultSub(uuid.New())
+	require.NoError(t, store.Create(ctx, sub))
+
+	// Not charged June to August 2024.
+	pauses := []models.Pause{
+		{SubscriptionID: sub.ID, StartDate: Month(2024, time.June), EndDate: Month(2024, time.August)},
+	}
+	require.NoError(t, store.Pauses.CreatePause(ctx, &pauses[0]))
+
+	for _, prorate := range []bool{false, true} {
+		req := &models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.December), Prorate: prorate}
+
+		sum, err := store.Summary(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, sub.Charge(req, nil, pauses), sum, "prorate %v", prorate)
+		assert.Equal(t, models.Undiscounted(9*1000), sum, "prorate %v", prorate)
+	}
+}
+
+func testSummaryShares(t *testing.T, store Store) {
+	if store.Members == nil {
+		t.Skip("backend does not store members, its summary ignores shares")
+	}
+
+	ctx := context.Background()
+	owner, member := uuid.New(), uuid.New()
+
+	sub := defaultSub(owner)
+	sub.Price = 300
+	require.NoError(t, store.Create(ctx, sub))
+	require.NoError(t, store.Members.SetMember(ctx, &models.Member{SubscriptionID: sub.ID, UserID: member, Weight: 2}))
+
+	for _, prorate := range []bool{false, true} {
+		summary := func(user uuid.UUID) models.Summary {
+			sum, err := store.Summary(ctx, &models.SumRequest{
+				UserID:    user,
+				StartDate: Month(2024, time.January),
+				EndDate:   Month(2024, time.December),
+				Prorate:   prorate,
+			})
+			require.NoError(t, err)
+			return sum
+		}
+
+		// 3600 a year, a third of it is the owner's.
+		assert.Equal(t, models.Undiscounted(1200), summary(owner), "prorate %v", prorate)
+		assert.Equal(t, models.Undiscounted(2400), summary(member), "prorate %v", prorate)
+	}
+	// Listed once per payer, each with the share Summary counts.
+	users, err := store.ListUsers(ctx, []uuid.UUID{owner, member})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	req := &models.SumRequest{StartDate: Month(2024, time.January), EndDate: Month(2024, time.December)}
+	due := map[uuid.UUID]models.Summary{}
+	for _, sub := range users {
+		due[sub.Payer] = sub.Due(req)
+	}
+	assert.Equal(t, map[uuid.UUID]models.Summary{
+		owner:  models.Undiscounted(1200),
+		member: models.Undiscounted(2400),
+	}, due)
+}
+
+func testHealth(t *testing.T, db postgres.SubsAPI) {
+	ctx := context.Background()
+
+	assert.NoError(t, db.Ping(ctx))
+
+	version, dirty, err := db.MigrationVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, postgres.SchemaVersion, version)
+	assert.False(t, dirty)
+}
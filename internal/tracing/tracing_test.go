@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func defaultConfig() *config.Tracing {
+	return &config.Tracing{
+		Exporter:    "none",
+		SampleRatio: 1,
+		ServiceName: "subs-aggregator",
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  error
+	}{
+		{name: "no-op", exporter: "none"},
+		{name: "stdout", exporter: "stdout"},
+		{name: "unknown", exporter: "zipkin", wantErr: ErrUnknownExporter},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Exporter = test.exporter
+
+			shutdown, err := Setup(context.Background(), cfg)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestNewProvider_Sampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	cfg := defaultConfig()
+	cfg.SampleRatio = 0
+
+	provider := NewProvider(cfg, exporter)
+	tracer := provider.Tracer("test")
+
+	_, root := tracer.Start(context.Background(), "root")
+	root.End()
+
+	parent := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+	_, child := tracer.Start(parent, "child")
+	child.End()
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, trace.TraceID{1}, spans[0].SpanContext.TraceID())
+}
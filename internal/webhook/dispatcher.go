@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+type Envelope struct {
+	DeliveryID uuid.UUID        `json:"delivery_id"`
+	EventID    int64            `json:"event_id"`
+	Event      models.EventType `json:"event"`
+	Data       json.RawMessage  `json:"data"`
+}
+
+type Dispatcher struct {
+	Logger *slog.Logger
+	Config *config.Webhooks
+	Store  postgres.WebhooksAPI
+	Client *http.Client
+}
+
+func NewDispatcher(logger *slog.Logger, cfg *config.Webhooks, store postgres.WebhooksAPI) *Dispatcher {
+	return &Dispatcher{
+		Logger: logger,
+		Config: cfg,
+		Store:  store,
+		Client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Sign returns the value of the signature header for body: the hex encoded
+// HMAC-SHA256 of the raw request body keyed by the webhook secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before the next attempt after attempts failures.
+func Backoff(attempts int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= limit {
+			return limit
+		}
+	}
+
+	return min(delay, limit)
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) Tick(ctx context.Context) {
+	if _, err := d.Store.FanOutEvents(ctx, d.Config.BatchSize); err != nil {
+		d.Logger.Error(
+			"webhooks fan out",
+			"error", err,
+		)
+	}
+
+	deliveries, err := d.Store.ClaimDeliveries(ctx, d.Config.BatchSize, d.Lease())
+	if err != nil {
+		d.Logger.Error(
+			"webhooks claim deliveries",
+			"error", err,
+		)
+		return
+	}
+
+	// The whole batch shares one lease, so its deliveries are sent
+	// concurrently: one after another, slow endpoints would keep the last
+	// ones past the lease and another dispatcher would send them again.
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.Delivery) {
+			defer wg.Done()
+			d.process(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+}
+
+// Lease returns how long claimed deliveries stay hidden from other
+// dispatchers: twice the request timeout, which every delivery of a batch
+// fits in as they are sent concurrently.
+func (d *Dispatcher) Lease() time.Duration {
+	return d.Config.Timeout * 2
+}
+
+func (d *Dispatcher) process(ctx context.Context, delivery *models.Delivery) {
+	sendErr := d.send(ctx, delivery)
+	if sendErr == nil {
+		d.mark(delivery, "webhooks mark delivered", d.Store.MarkDelivered(ctx, delivery.ID, delivery.NextAttemptAt))
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	dead := attempts >= d.Config.MaxAttempts
+	next := time.Now().Add(Backoff(attempts, d.Config.BaseBackoff, d.Config.MaxBackoff))
+
+	d.Logger.Warn(
+		"webhook delivery failed",
+		"delivery_id", delivery.ID,
+		"webhook_id", delivery.WebhookID,
+		"attempts", attempts,
+		"dead", dead,
+		"error", sendErr,
+	)
+
+	err := d.Store.MarkFailed(ctx, delivery.ID, delivery.NextAttemptAt, sendErr.Error(), next, dead)
+	d.mark(delivery, "webhooks mark failed", err)
+}
+
+// mark logs the error of recording a delivery result. A lost lease is only
+// a warning: the delivery outlived its lease and the result of the
+// dispatcher that claimed it again is kept instead.
+func (d *Dispatcher) mark(delivery *models.Delivery, msg string, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, postgres.ErrLeaseLost):
+		d.Logger.Warn(
+			msg,
+			"delivery_id", delivery.ID,
+			"error", err,
+		)
+	default:
+		d.Logger.Error(
+			msg,
+			"delivery_id", delivery.ID,
+			"error", err,
+		)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *models.Delivery) error {
+	body, err := json.Marshal(Envelope{
+		DeliveryID: delivery.ID,
+		EventID:    delivery.EventID,
+		Event:      delivery.EventType,
+		Data:       delivery.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal envelope fail: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request fail: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request fail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
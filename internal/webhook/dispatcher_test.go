@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/P3rCh1/subs-aggregator/internal/config"
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failure struct {
+	reason string
+	next   time.Time
+	dead   bool
+}
+
+type fakeStore struct {
+	mu         sync.Mutex
+	deliveries []models.Delivery
+	delivered  []uuid.UUID
+	failed     []failure
+	lease      time.Duration
+	leases     []time.Time
+	lost       bool
+}
+
+func (f *fakeStore) CreateWebhook(ctx context.Context, hook *models.Webhook) error { return nil }
+
+func (f *fakeStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) { return nil, nil }
+
+func (f *fakeStore) DeleteWebhook(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (f *fakeStore) PublishEvent(
+	ctx context.Context,
+	tx sqlx.ExecerContext,
+	eventType models.EventType,
+	sub *models.Subscription,
+) error {
+	return nil
+}
+
+func (f *fakeStore) FanOutEvents(ctx context.Context, limit int) (int, error) { return 0, nil }
+
+func (f *fakeStore) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lease = lease
+	claimed := f.deliveries
+	f.deliveries = nil
+	return claimed, nil
+}
+
+func (f *fakeStore) MarkDelivered(ctx context.Context, id uuid.UUID, lease time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.leases = append(f.leases, lease)
+	if f.lost {
+		return postgres.ErrLeaseLost
+	}
+
+	f.delivered = append(f.delivered, id)
+	return nil
+}
+
+func (f *fakeStore) MarkFailed(
+	ctx context.Context,
+	id uuid.UUID,
+	lease time.Time,
+	reason string,
+	next time.Time,
+	dead bool,
+) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.leases = append(f.leases, lease)
+	if f.lost {
+		return postgres.ErrLeaseLost
+	}
+
+	f.failed = append(f.failed, failure{reason, next, dead})
+	return nil
+}
+
+func (f *fakeStore) ListDeliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	status models.DeliveryStatus,
+) ([]models.Delivery, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Redeliver(ctx context.Context, id uuid.UUID) error { return nil }
+
+func setup(store *fakeStore) *Dispatcher {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Webhooks{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+		Timeout:      time.Second,
+	}
+
+	return NewDispatcher(logger, cfg, store)
+}
+
+func defaultDelivery(url string) models.Delivery {
+	return models.Delivery{
+		ID:        uuid.New(),
+		WebhookID: uuid.New(),
+		EventID:   1,
+		EventType: models.EventSubCreated,
+		Payload:   json.RawMessage(`{"service_name":"Netflix"}`),
+		Status:    models.DeliveryPending,
+		URL:       url,
+		Secret:    "secret",
+		// ClaimDeliveries returns the lease as the next attempt time.
+		NextAttemptAt: time.Now().Add(2 * time.Second).Truncate(time.Microsecond),
+	}
+}
+
+func TestTick_DeliversSignedEvent(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	delivery := defaultDelivery(receiver.URL)
+	store := &fakeStore{deliveries: []models.Delivery{delivery}}
+
+	setup(store).Tick(context.Background())
+
+	require.Equal(t, []uuid.UUID{delivery.ID}, store.delivered)
+	assert.Empty(t, store.failed)
+	assert.Equal(t, []time.Time{delivery.NextAttemptAt}, store.leases)
+
+	assert.Equal(t, Sign("secret", body), headers.Get(HeaderSignature))
+	assert.Equal(t, string(models.EventSubCreated), headers.Get(HeaderEvent))
+	assert.Equal(t, delivery.ID.String(), headers.Get(HeaderDelivery))
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, delivery.ID, envelope.DeliveryID)
+	assert.Equal(t, models.EventSubCreated, envelope.Event)
+	assert.JSONEq(t, string(delivery.Payload), string(envelope.Data))
+}
+
+func TestTick_RetriesWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	delivery := defaultDelivery(receiver.URL)
+	store := &fakeStore{deliveries: []models.Delivery{delivery}}
+
+	start := time.Now()
+	setup(store).Tick(context.Background())
+
+	assert.Empty(t, store.delivered)
+	require.Len(t, store.failed, 1)
+	assert.False(t, store.failed[0].dead)
+	assert.Contains(t, store.failed[0].reason, "500")
+	assert.WithinDuration(t, start.Add(time.Second), store.failed[0].next, 500*time.Millisecond)
+	assert.Equal(t, []time.Time{delivery.NextAttemptAt}, store.leases)
+}
+
+func TestTick_LeaseLost(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	delivery := defaultDelivery(receiver.URL)
+	store := &fakeStore{deliveries: []models.Delivery{delivery}, lost: true}
+
+	setup(store).Tick(context.Background())
+
+	assert.Equal(t, []time.Time{delivery.NextAttemptAt}, store.leases)
+	assert.Empty(t, store.delivered, "a result after the lease is lost must not be recorded")
+	assert.Empty(t, store.failed)
+}
+
+func TestTick_DeadLetterAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer receiver.Close()
+
+	delivery := defaultDelivery(receiver.URL)
+	delivery.Attempts = 2
+	store := &fakeStore{deliveries: []models.Delivery{delivery}}
+
+	setup(store).Tick(context.Background())
+
+	require.Len(t, store.failed, 1)
+	assert.True(t, store.failed[0].dead)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 4, want: 80 * time.Second},
+		{attempts: 10, want: 5 * time.Minute},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, Backoff(test.attempts, 10*time.Second, 5*time.Minute))
+	}
+}
+
+func TestTick_BatchWithinLease(t *testing.T) {
+	const slow = 150 * time.Millisecond
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(slow)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store := &fakeStore{}
+	for range 5 {
+		store.deliveries = append(store.deliveries, defaultDelivery(receiver.URL))
+	}
+
+	dispatcher := setup(store)
+	dispatcher.Config.Timeout = slow * 2
+	dispatcher.Client.Timeout = dispatcher.Config.Timeout
+	require.Greater(t, 5*slow, dispatcher.Lease(), "sent one by one, the batch would outlive its lease")
+
+	start := time.Now()
+	dispatcher.Tick(context.Background())
+	elapsed := time.Since(start)
+
+	assert.Len(t, store.delivered, 5)
+	assert.Empty(t, store.failed)
+	assert.Equal(t, dispatcher.Lease(), store.lease)
+	assert.Less(t, elapsed, store.lease, "every delivery must be done before its lease ends")
+}
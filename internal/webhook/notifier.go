@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/P3rCh1/subs-aggregator/internal/models"
+	"github.com/P3rCh1/subs-aggregator/internal/storage/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+// Notifier publishes reminders as outbox events, so they reach every
+// webhook subscribed to the matching event type. The event is written with
+// the transaction recording the reminder.
+type Notifier struct {
+	Store postgres.WebhooksAPI
+}
+
+func (n *Notifier) Notify(ctx context.Context, tx sqlx.ExecerContext, rem *models.Reminder) error {
+	return n.Store.PublishEvent(ctx, tx, rem.Kind.Event(), &rem.Subscription)
+}
@@ -0,0 +1,7 @@
+CREATE TABLE reminders (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    period DATE NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, kind, period)
+);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS budgets;
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS category;
\ No newline at end of file
@@ -0,0 +1,15 @@
+ALTER TABLE subscriptions
+ADD COLUMN category VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE budgets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255) NOT NULL DEFAULT '',
+    category VARCHAR(255) NOT NULL DEFAULT '',
+    monthly_limit INTEGER NOT NULL CHECK (monthly_limit > 0),
+    state VARCHAR(16) NOT NULL DEFAULT 'ok',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_user_id
+ON budgets USING HASH (user_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+DROP CONSTRAINT IF EXISTS subscriptions_price_check;
+
+ALTER TABLE subscriptions
+ADD CONSTRAINT subscriptions_price_check CHECK (price >= 0);
\ No newline at end of file
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS subscriptions_spend_rollup ON subscriptions;
+
+DROP FUNCTION IF EXISTS spend_rollup_sync();
+
+DROP FUNCTION IF EXISTS spend_rollup_apply(subscriptions, INTEGER);
+
+DROP TABLE IF EXISTS spend_rollup_state;
+
+DROP TABLE IF EXISTS spend_rollup;
\ No newline at end of file
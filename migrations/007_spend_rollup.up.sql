@@ -0,0 +1,51 @@
+CREATE TABLE spend_rollup (
+    month DATE NOT NULL,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255) NOT NULL,
+    category VARCHAR(255) NOT NULL DEFAULT '',
+    total BIGINT NOT NULL,
+    PRIMARY KEY (month, user_id, service_name, category)
+);
+
+CREATE INDEX IF NOT EXISTS idx_spend_rollup_user_id
+ON spend_rollup (user_id, month);
+
+-- through is the last month materialised for open ended subscriptions.
+-- The rollup is not used until the first refresh sets it.
+CREATE TABLE spend_rollup_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    through DATE NULL,
+    refreshed_at TIMESTAMPTZ NULL
+);
+
+INSERT INTO spend_rollup_state DEFAULT VALUES;
+
+CREATE FUNCTION spend_rollup_apply(sub subscriptions, sign INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO spend_rollup AS r (month, user_id, service_name, category, total)
+    SELECT m::date, sub.user_id, sub.service_name, sub.category, sign * sub.price
+    FROM spend_rollup_state st,
+        generate_series(sub.start_date::timestamp, LEAST(sub.end_date, st.through)::timestamp, INTERVAL '1 month') m
+    WHERE st.through IS NOT NULL
+    ON CONFLICT (month, user_id, service_name, category)
+    DO UPDATE SET total = r.total + EXCLUDED.total;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION spend_rollup_sync() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM spend_rollup_apply(OLD, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM spend_rollup_apply(NEW, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER subscriptions_spend_rollup
+AFTER INSERT OR UPDATE OR DELETE ON subscriptions
+FOR EACH ROW EXECUTE FUNCTION spend_rollup_sync();
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP FUNCTION IF EXISTS month_count(DATERANGE);
+
+DROP INDEX IF EXISTS idx_subscriptions_period;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS period;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- period holds the months a subscription is billed for as a half open
+-- daterange, so overlap queries can use the range operators and the GiST
+-- index instead of comparing start_date and end_date by hand.
+ALTER TABLE subscriptions
+ADD COLUMN period DATERANGE GENERATED ALWAYS AS (
+    daterange(start_date, (end_date + INTERVAL '1 month')::date, '[)')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_period
+ON subscriptions USING GIST (period);
+
+-- month_count returns the number of whole months in a range whose bounds
+-- are first days of months, such as period or its intersection with one.
+CREATE FUNCTION month_count(r DATERANGE) RETURNS INTEGER AS $$
+    SELECT ((EXTRACT(YEAR FROM upper(r)) - EXTRACT(YEAR FROM lower(r))) * 12
+        + EXTRACT(MONTH FROM upper(r)) - EXTRACT(MONTH FROM lower(r)))::integer
+$$ LANGUAGE sql IMMUTABLE;
\ No newline at end of file
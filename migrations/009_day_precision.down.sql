@@ -0,0 +1,23 @@
+CREATE OR REPLACE FUNCTION spend_rollup_apply(sub subscriptions, sign INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO spend_rollup AS r (month, user_id, service_name, category, total)
+    SELECT m::date, sub.user_id, sub.service_name, sub.category, sign * sub.price
+    FROM spend_rollup_state st,
+        generate_series(sub.start_date::timestamp, LEAST(sub.end_date, st.through)::timestamp, INTERVAL '1 month') m
+    WHERE st.through IS NOT NULL
+    ON CONFLICT (month, user_id, service_name, category)
+    DO UPDATE SET total = r.total + EXCLUDED.total;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_subscriptions_period;
+
+ALTER TABLE subscriptions DROP COLUMN period;
+
+ALTER TABLE subscriptions
+ADD COLUMN period DATERANGE GENERATED ALWAYS AS (
+    daterange(start_date, (end_date + INTERVAL '1 month')::date, '[)')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_period
+ON subscriptions USING GIST (period);
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE OR REPLACE FUNCTION spend_rollup_apply(sub subscriptions, sign INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO spend_rollup AS r (month, user_id, service_name, category, total)
+    SELECT m::date, sub.user_id, sub.service_name, sub.category, sign * sub.price
+    FROM spend_rollup_state st,
+        generate_series(lower(sub.period)::timestamp, LEAST(sub.end_date, st.through)::timestamp, INTERVAL '1 month') m
+    WHERE st.through IS NOT NULL
+    ON CONFLICT (month, user_id, service_name, category)
+    DO UPDATE SET total = r.total + EXCLUDED.total;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS subscription_price(subscriptions, DATE);
+
+CREATE OR REPLACE FUNCTION month_count(r DATERANGE) RETURNS INTEGER AS $$
+    SELECT ((EXTRACT(YEAR FROM upper(r)) - EXTRACT(YEAR FROM lower(r))) * 12
+        + EXTRACT(MONTH FROM upper(r)) - EXTRACT(MONTH FROM lower(r)))::integer
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS intro,
+DROP COLUMN IF EXISTS trial,
+DROP COLUMN IF EXISTS intro_months,
+DROP COLUMN IF EXISTS intro_price,
+DROP COLUMN IF EXISTS trial_months;
\ No newline at end of file
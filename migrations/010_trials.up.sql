@@ -0,0 +1,52 @@
+ALTER TABLE subscriptions
+ADD COLUMN trial_months INTEGER NOT NULL DEFAULT 0 CHECK (trial_months >= 0),
+ADD COLUMN intro_price INTEGER NOT NULL DEFAULT 0 CHECK (intro_price >= 0),
+ADD COLUMN intro_months INTEGER NOT NULL DEFAULT 0 CHECK (intro_months >= 0);
+
+-- trial holds the free months at the start of a subscription and intro the
+-- months charged intro_price right after them, in the same form as period.
+-- Generated columns cannot refer to period, so the start month is repeated.
+ALTER TABLE subscriptions
+ADD COLUMN trial DATERANGE GENERATED ALWAYS AS (
+    daterange(
+        date_trunc('month', start_date::timestamp)::date,
+        (date_trunc('month', start_date::timestamp) + trial_months * INTERVAL '1 month')::date,
+        '[)'
+    )
+) STORED,
+ADD COLUMN intro DATERANGE GENERATED ALWAYS AS (
+    daterange(
+        (date_trunc('month', start_date::timestamp) + trial_months * INTERVAL '1 month')::date,
+        (date_trunc('month', start_date::timestamp) + (trial_months + intro_months) * INTERVAL '1 month')::date,
+        '[)'
+    )
+) STORED;
+
+CREATE OR REPLACE FUNCTION month_count(r DATERANGE) RETURNS INTEGER AS $$
+    SELECT CASE WHEN isempty(r) THEN 0 ELSE
+        ((EXTRACT(YEAR FROM upper(r)) - EXTRACT(YEAR FROM lower(r))) * 12
+            + EXTRACT(MONTH FROM upper(r)) - EXTRACT(MONTH FROM lower(r)))::integer
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- subscription_price returns what sub charges for month, the first day of
+-- a month within its period.
+CREATE FUNCTION subscription_price(sub subscriptions, month DATE) RETURNS INTEGER AS $$
+    SELECT CASE
+        WHEN sub.trial @> month THEN 0
+        WHEN sub.intro @> month THEN sub.intro_price
+        ELSE sub.price
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION spend_rollup_apply(sub subscriptions, sign INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO spend_rollup AS r (month, user_id, service_name, category, total)
+    SELECT m::date, sub.user_id, sub.service_name, sub.category, sign * subscription_price(sub, m::date)
+    FROM spend_rollup_state st,
+        generate_series(lower(sub.period)::timestamp, LEAST(sub.end_date, st.through)::timestamp, INTERVAL '1 month') m
+    WHERE st.through IS NOT NULL
+    ON CONFLICT (month, user_id, service_name, category)
+    DO UPDATE SET total = r.total + EXCLUDED.total;
+END;
+$$ LANGUAGE plpgsql;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS subscription_discount(subscriptions, DATE);
+
+DROP TABLE IF EXISTS discounts;
\ No newline at end of file
@@ -0,0 +1,34 @@
+CREATE TABLE discounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    amount INTEGER NOT NULL CHECK (amount > 0 AND (kind <> 'percent' OR amount <= 100)),
+    start_date DATE NOT NULL,
+    end_date DATE NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- period has the form of subscriptions.period.
+    period DATERANGE GENERATED ALWAYS AS (
+        daterange(
+            date_trunc('month', start_date::timestamp)::date,
+            (date_trunc('month', end_date::timestamp) + INTERVAL '1 month')::date,
+            '[)'
+        )
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_discounts_subscription_id
+ON discounts (subscription_id);
+
+-- subscription_discount returns how much the discounts of sub take off its
+-- price for month: percentages round half up and the sum never exceeds the
+-- price.
+CREATE FUNCTION subscription_discount(sub subscriptions, month DATE) RETURNS INTEGER AS $$
+    SELECT LEAST(subscription_price(sub, month), COALESCE(SUM(
+        CASE d.kind
+            WHEN 'percent' THEN round(subscription_price(sub, month) * d.amount / 100.0)::integer
+            ELSE d.amount
+        END
+    ), 0))::integer
+    FROM discounts d
+    WHERE d.subscription_id = sub.id AND d.period @> month
+$$ LANGUAGE sql STABLE;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- subscription_members splits a shared subscription among its members by
+-- weight. The owner of a shared subscription is one of its rows; a
+-- subscription without rows is paid by its owner alone.
+CREATE TABLE subscription_members (
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1 CHECK (weight > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_members_user_id
+ON subscription_members (user_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS subscription_pauses;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- subscription_pauses holds the months a subscription is not charged for,
+-- from start_date to end_date, both first days of their months. A pause
+-- without end_date lasts until the subscription is resumed.
+CREATE TABLE subscription_pauses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NULL CHECK (end_date >= start_date),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- period has the form of subscriptions.period.
+    period DATERANGE GENERATED ALWAYS AS (
+        daterange(
+            date_trunc('month', start_date::timestamp)::date,
+            (date_trunc('month', end_date::timestamp) + INTERVAL '1 month')::date,
+            '[)'
+        )
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_pauses_subscription_id
+ON subscription_pauses (subscription_id);
\ No newline at end of file
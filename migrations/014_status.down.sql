@@ -0,0 +1,12 @@
+DROP FUNCTION IF EXISTS subscription_next_status(subscriptions, DATE);
+
+DROP TABLE IF EXISTS subscription_transitions;
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS status;
+
+DROP TRIGGER IF EXISTS subscriptions_spend_rollup ON subscriptions;
+
+CREATE TRIGGER subscriptions_spend_rollup
+AFTER INSERT OR UPDATE OR DELETE ON subscriptions
+FOR EACH ROW EXECUTE FUNCTION spend_rollup_sync();
\ No newline at end of file
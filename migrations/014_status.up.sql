@@ -0,0 +1,61 @@
+-- status changes must not touch the rollup: fire only on the columns it
+-- is computed from.
+DROP TRIGGER subscriptions_spend_rollup ON subscriptions;
+
+CREATE TRIGGER subscriptions_spend_rollup
+AFTER INSERT
+    OR UPDATE OF service_name, category, price, user_id, start_date, end_date, trial_months, intro_price, intro_months
+    OR DELETE
+ON subscriptions
+FOR EACH ROW EXECUTE FUNCTION spend_rollup_sync();
+
+ALTER TABLE subscriptions
+ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('scheduled', 'active', 'paused', 'cancelled', 'expired'));
+
+UPDATE subscriptions s
+SET status = CASE
+    WHEN lower(s.period) > date_trunc('month', now())::date THEN 'scheduled'
+    WHEN NOT upper_inf(s.period) AND upper(s.period) <= date_trunc('month', now())::date THEN 'expired'
+    WHEN EXISTS (
+        SELECT 1 FROM subscription_pauses p
+        WHERE p.subscription_id = s.id AND p.period @> date_trunc('month', now())::date
+    ) THEN 'paused'
+    ELSE 'active'
+END;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status
+ON subscriptions (status);
+
+-- subscription_transitions is the history of status changes.
+CREATE TABLE subscription_transitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_transitions_subscription_id
+ON subscription_transitions (subscription_id);
+
+-- subscription_next_status returns the status sub moves to in month, the
+-- first day of a month: one step along the lifecycle, or its status when
+-- its dates and pauses call for no change. It mirrors
+-- models.Subscription.NextStatus.
+CREATE FUNCTION subscription_next_status(sub subscriptions, month DATE) RETURNS VARCHAR AS $$
+    SELECT CASE
+        WHEN sub.status = 'scheduled' AND month >= lower(sub.period) THEN 'active'
+        WHEN sub.status IN ('active', 'paused') AND NOT upper_inf(sub.period) AND month >= upper(sub.period) THEN 'expired'
+        WHEN sub.status = 'active' AND paused THEN 'paused'
+        WHEN sub.status = 'paused' AND NOT paused THEN 'active'
+        ELSE sub.status
+    END
+    FROM (
+        SELECT EXISTS (
+            SELECT 1 FROM subscription_pauses p
+            WHERE p.subscription_id = sub.id AND p.period @> month
+        ) AS paused
+    ) pauses
+$$ LANGUAGE sql STABLE;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- subscription_cancellations keeps every cancellation with the subscription
+-- fields churn is grouped by. subscription_id deliberately has no foreign
+-- key: churn history outlives its subscriptions, and churn counts a
+-- cancelled subscription deleted since as running from start_date to
+-- effective_date.
+CREATE TABLE subscription_cancellations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL UNIQUE,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255) NOT NULL,
+    category VARCHAR(255) NOT NULL DEFAULT '',
+    start_date DATE NOT NULL,
+    effective_date DATE NOT NULL,
+    reason_code VARCHAR(32) NOT NULL
+        CHECK (reason_code IN ('too_expensive', 'not_using', 'switched_service', 'missing_features', 'technical_issues', 'other')),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_cancellations_effective_date
+ON subscription_cancellations (effective_date, service_name);
\ No newline at end of file